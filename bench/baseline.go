@@ -0,0 +1,39 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Baseline maps a benchmark name (e.g. "BenchmarkHotCachePut") to its
+// recorded ns/op, the unit `go test -bench` reports and the one
+// RunBenchmarks parses results into.
+type Baseline map[string]float64
+
+// LoadBaselines reads a Baseline previously written by Save.
+func LoadBaselines(path string) (Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("bench: reading baselines %s: %w", path, err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("bench: decoding baselines %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Save writes b as indented JSON to path, e.g. after -update regenerates
+// it from a fresh benchmark run.
+func (b Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("bench: encoding baselines: %w", err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("bench: writing baselines %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var benchLineRE = regexp.MustCompile(`^(Benchmark\S+)\s+\d+\s+([0-9.]+) ns/op`)
+
+// RunBenchmarks runs `go test -run=^$ -bench=. -benchmem` against
+// pkgPath (e.g. "./bench/...") and returns each benchmark's ns/op,
+// keyed by name.
+func RunBenchmarks(ctx context.Context, pkgPath string) (map[string]float64, error) {
+	cmd := exec.CommandContext(ctx, "go", "test", "-run=^$", "-bench=.", "-benchmem", pkgPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("bench: running go test -bench on %s: %w: %s", pkgPath, err, output)
+	}
+
+	results := make(map[string]float64)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		m := benchLineRE.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		ns, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			continue
+		}
+		results[m[1]] = ns
+	}
+	return results, nil
+}
+
+// Result is one benchmark's comparison against its Baseline entry.
+type Result struct {
+	Name            string
+	NsPerOp         float64
+	BaselineNsPerOp float64 // 0 if the benchmark has no recorded baseline yet
+	RegressionPct   float64 // positive means slower than baseline
+	Regressed       bool
+}
+
+// Compare reports, for every benchmark in current, how it moved against
+// baseline. A benchmark with no baseline entry is reported but never
+// marked Regressed, since there is nothing to regress against yet.
+func Compare(current map[string]float64, baseline Baseline, thresholdPct float64) []Result {
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]Result, 0, len(names))
+	for _, name := range names {
+		r := Result{Name: name, NsPerOp: current[name]}
+		if base, ok := baseline[name]; ok && base > 0 {
+			r.BaselineNsPerOp = base
+			r.RegressionPct = (r.NsPerOp - base) / base * 100
+			r.Regressed = r.RegressionPct > thresholdPct
+		}
+		results = append(results, r)
+	}
+	return results
+}
@@ -0,0 +1,16 @@
+// Package bench holds reproducible Go benchmarks for this repo's
+// performance-sensitive primitives, plus cmd/benchcheck, a runner that
+// compares a benchmark run against a stored baseline (baselines.json)
+// and fails when ns/op regresses beyond a threshold.
+//
+// It covers pkg/hotcache.MemStore and pkg/hashring's HRW (Route) and
+// Maglev routing, both of which exist and sit on hot paths (grpc-router
+// calls both per request). It does not cover a FreqTracker or a
+// logservice writer path: neither exists anywhere in this tree yet (the
+// closest things are pkg/client.LogServiceClient, which only talks to a
+// remote LogService over HTTP, and cmd/zenglow's "serve-logservice",
+// which is still a stub — see notYetImplemented in cmd/zenglow/main.go).
+// There is nothing in-process to benchmark for either until one of
+// those lands; add BenchmarkFreqTracker* and BenchmarkLogWriter* here
+// once they do, following the pattern in hotcache_bench_test.go.
+package bench
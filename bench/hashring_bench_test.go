@@ -0,0 +1,40 @@
+package bench
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+)
+
+func seededNodes(n int) []hashring.Node {
+	nodes := make([]hashring.Node, n)
+	for i := range nodes {
+		nodes[i] = hashring.Node{ID: fmt.Sprintf("node-%d", i), Weight: 1}
+	}
+	return nodes
+}
+
+func BenchmarkHRWRoute(b *testing.B) {
+	nodes := seededNodes(100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hashring.Route(fmt.Sprintf("key-%d", i), nodes)
+	}
+}
+
+func BenchmarkMaglevBuildTable(b *testing.B) {
+	nodes := seededNodes(100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hashring.BuildMaglevTable(nodes, 2039)
+	}
+}
+
+func BenchmarkMaglevLookup(b *testing.B) {
+	table := hashring.BuildMaglevTable(seededNodes(100), 2039)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		table.Lookup(fmt.Sprintf("key-%d", i))
+	}
+}
@@ -0,0 +1,45 @@
+package bench
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hotcache"
+)
+
+func seededHotCache(n int) *hotcache.MemStore {
+	store := hotcache.NewMemStore()
+	for i := 0; i < n; i++ {
+		store.Put(hotcache.Entry{
+			Key:    fmt.Sprintf("key-%d", i),
+			Vector: []float32{float32(i), float32(i) * 2, float32(i) * 3},
+			Score:  float64(i),
+		})
+	}
+	return store
+}
+
+func BenchmarkHotCachePut(b *testing.B) {
+	store := hotcache.NewMemStore()
+	entry := hotcache.Entry{Key: "k", Vector: []float32{1, 2, 3}, Score: 0.5}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		store.Put(entry)
+	}
+}
+
+func BenchmarkHotCacheSnapshot(b *testing.B) {
+	store := seededHotCache(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = store.Snapshot()
+	}
+}
+
+func BenchmarkHotCacheLoad(b *testing.B) {
+	entries := seededHotCache(1000).Snapshot()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hotcache.NewMemStore().Load(entries)
+	}
+}
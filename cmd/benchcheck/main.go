@@ -0,0 +1,67 @@
+// Command benchcheck runs bench/'s Go benchmarks and compares them
+// against bench/baselines.json, exiting non-zero if any regressed by
+// more than -threshold percent. Run with -update to overwrite the
+// baseline file with the current run instead of comparing against it,
+// e.g. after an intentional performance-changing refactor.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/cgb808/ZenGlowNext/bench"
+)
+
+func main() {
+	var (
+		pkg       = flag.String("pkg", "./bench/...", "package pattern to run benchmarks in")
+		baseline  = flag.String("baseline", "bench/baselines.json", "path to the stored baseline file")
+		threshold = flag.Float64("threshold", 10.0, "percent regression over baseline ns/op that fails the run")
+		update    = flag.Bool("update", false, "overwrite -baseline with this run's results instead of comparing against it")
+	)
+	flag.Parse()
+
+	current, err := bench.RunBenchmarks(context.Background(), *pkg)
+	if err != nil {
+		log.Fatalf("benchcheck: %v", err)
+	}
+	if len(current) == 0 {
+		log.Fatalf("benchcheck: no benchmark results parsed from %s", *pkg)
+	}
+
+	if *update {
+		if err := bench.Baseline(current).Save(*baseline); err != nil {
+			log.Fatalf("benchcheck: %v", err)
+		}
+		fmt.Printf("wrote %d benchmark(s) to %s\n", len(current), *baseline)
+		return
+	}
+
+	base, err := bench.LoadBaselines(*baseline)
+	if err != nil {
+		log.Fatalf("benchcheck: %v", err)
+	}
+
+	results := bench.Compare(current, base, *threshold)
+	regressed := false
+	for _, r := range results {
+		if r.BaselineNsPerOp == 0 {
+			fmt.Printf("%-32s %10.1f ns/op  (no baseline)\n", r.Name, r.NsPerOp)
+			continue
+		}
+		marker := "ok"
+		if r.Regressed {
+			marker = "REGRESSED"
+			regressed = true
+		}
+		fmt.Printf("%-32s %10.1f ns/op  baseline %10.1f ns/op  %+6.1f%%  %s\n", r.Name, r.NsPerOp, r.BaselineNsPerOp, r.RegressionPct, marker)
+	}
+
+	if regressed {
+		fmt.Fprintf(os.Stderr, "benchcheck: one or more benchmarks regressed beyond %.1f%%\n", *threshold)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,216 @@
+// Command grpc-router selects a backend for an incoming key using the same
+// pkg/hashring logic as the hrw CLI, exposed here over a small JSON/HTTP
+// control API (the transport the rest of this service's RPC surface sits
+// on top of). Keeping backend selection in pkg/hashring means this service
+// and the hrw CLI can never disagree on where a key routes. A replica
+// started with -warm-from-peer streams a pkg/hotcache snapshot from that
+// peer and only reports ready on /readyz once warm-up finishes, so a
+// load balancer gating on /readyz doesn't send it traffic straight into
+// a cold cache. Setting -spiffe-cert/-spiffe-key/-spiffe-bundle serves the
+// control API over mTLS using pkg/spiffe instead of a plain listener, and
+// -spiffe-allowed-callers restricts /route to a fixed set of caller SVIDs.
+// Setting -querystats-redis-addr samples each /route call's latency and
+// outcome into pkg/querystats, and -querystats-database-url additionally
+// serves those rollups over GET /stats.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/admin"
+	"github.com/cgb808/ZenGlowNext/pkg/apierror"
+	"github.com/cgb808/ZenGlowNext/pkg/gateway"
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+	"github.com/cgb808/ZenGlowNext/pkg/hotcache"
+	"github.com/cgb808/ZenGlowNext/pkg/querystats"
+	"github.com/cgb808/ZenGlowNext/pkg/shutdown"
+	"github.com/cgb808/ZenGlowNext/pkg/spiffe"
+	"github.com/cgb808/ZenGlowNext/pkg/telemetry"
+)
+
+// backends holds the current node set this router selects from. It is
+// replaced wholesale on reload rather than mutated in place. Recorder is
+// optional and, when set, samples each /route call's latency and
+// outcome into pkg/querystats.
+type backends struct {
+	nodes    []hashring.Node
+	Recorder querystats.Recorder
+}
+
+func (b *backends) handleRoute(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		http.Error(w, "missing key", http.StatusBadRequest)
+		return
+	}
+	node := hashring.Route(key, b.nodes)
+	if node == "" {
+		b.recordSample(r, start, 0)
+		apierror.WriteHTTP(w, apierror.DependencyUnavailable("backends", nil))
+		return
+	}
+	b.recordSample(r, start, 1)
+	json.NewEncoder(w).Encode(map[string]string{"key": key, "node": node})
+}
+
+// recordSample best-effort samples the call into querystats; router
+// lookups have no cache tiers or filters, so those Sample fields are
+// left zero.
+func (b *backends) recordSample(r *http.Request, start time.Time, resultCount int) {
+	if b.Recorder == nil {
+		return
+	}
+	err := b.Recorder.Record(r.Context(), querystats.Sample{
+		Service:     "router",
+		Timestamp:   start,
+		LatencyMS:   float64(time.Since(start).Microseconds()) / 1000,
+		ResultCount: resultCount,
+	})
+	if err != nil {
+		log.Printf("grpc-router: recording query stats sample: %v", err)
+	}
+}
+
+func main() {
+	var (
+		addr         = flag.String("addr", ":8081", "listen address")
+		nodesArg     = flag.String("nodes", "", "comma-separated backend node ids")
+		adminAddr    = flag.String("admin-addr", "", "loopback address for pprof/debug endpoints, e.g. 127.0.0.1:6060 (disabled if empty)")
+		warmFromPeer = flag.String("warm-from-peer", "", "base URL of a healthy replica to stream a hotcache snapshot from before reporting ready, e.g. http://router-1:8081 (skips warm-up if empty)")
+
+		spiffeCert           = flag.String("spiffe-cert", "", "SVID certificate file; if set with -spiffe-key and -spiffe-bundle, serve over mTLS instead of plain HTTP")
+		spiffeKey            = flag.String("spiffe-key", "", "SVID private key file")
+		spiffeBundle         = flag.String("spiffe-bundle", "", "trust bundle file used to verify client certificates")
+		spiffeAllowedCallers = flag.String("spiffe-allowed-callers", "", "comma-separated SPIFFE IDs allowed to call /route over mTLS (allows any presented client cert if empty)")
+
+		querystatsRedisAddr   = flag.String("querystats-redis-addr", "", "Redis address to sample /route latency and outcomes to via pkg/querystats (disabled if empty)")
+		querystatsDatabaseURL = flag.String("querystats-database-url", "", "Postgres connection string serving GET /stats; requires -querystats-redis-addr to have any data to serve")
+	)
+	flag.Parse()
+
+	b := &backends{}
+	for _, id := range strings.Split(*nodesArg, ",") {
+		id = strings.TrimSpace(id)
+		if id != "" {
+			b.nodes = append(b.nodes, hashring.Node{ID: id, Weight: 1})
+		}
+	}
+
+	reg := telemetry.NewRegistry()
+	cache := hotcache.NewMemStore()
+	if *querystatsRedisAddr != "" {
+		b.Recorder = &querystats.RedisRecorder{Addr: *querystatsRedisAddr}
+	}
+
+	var ready atomic.Bool
+	ready.Store(*warmFromPeer == "")
+
+	var routeHandler http.Handler = http.HandlerFunc(b.handleRoute)
+	var tlsConfig *tls.Config
+	if *spiffeCert != "" || *spiffeKey != "" || *spiffeBundle != "" {
+		source, err := spiffe.NewFileSource(*spiffeCert, *spiffeKey, *spiffeBundle)
+		if err != nil {
+			log.Fatalf("grpc-router: loading SPIFFE identity: %v", err)
+		}
+		tlsConfig = spiffe.ServerConfig(source)
+		go source.Watch(context.Background(), 30*time.Second, func() {
+			log.Printf("grpc-router: rotated SPIFFE SVID/trust bundle")
+		}, func(err error) {
+			log.Printf("grpc-router: SPIFFE rotation check failed: %v", err)
+		})
+
+		if *spiffeAllowedCallers != "" {
+			allowed := make(map[string][]string)
+			for _, id := range strings.Split(*spiffeAllowedCallers, ",") {
+				id = strings.TrimSpace(id)
+				if id != "" {
+					allowed[id] = []string{"route"}
+				}
+			}
+			policy := spiffe.NewPolicy(allowed)
+			routeHandler = spiffe.Middleware(policy, func(r *http.Request) string { return "route" }, routeHandler)
+		}
+	}
+
+	gw := gateway.New()
+	gw.Register(gateway.Route{
+		Service: "RouterService",
+		Method:  "GET",
+		Path:    "/route",
+		Summary: "Select a backend node for a key",
+		Handler: telemetry.Middleware(reg, "grpc_router", routeHandler),
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/route", gw)
+	mux.Handle("/openapi.json", gw)
+	mux.Handle("/metrics", telemetry.Handler(reg))
+	mux.Handle("/hotcache/snapshot", hotcache.Handler(cache))
+	if *querystatsDatabaseURL != "" {
+		mux.Handle("/stats", querystats.Handler(querystats.PostgresSink{DatabaseURL: *querystatsDatabaseURL}))
+	}
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !ready.Load() {
+			http.Error(w, "warming up", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{Addr: *addr, Handler: mux, TLSConfig: tlsConfig}
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			log.Printf("grpc-router listening on %s (mTLS) with %d backend(s)", *addr, len(b.nodes))
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			log.Printf("grpc-router listening on %s with %d backend(s)", *addr, len(b.nodes))
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	if *warmFromPeer != "" {
+		go func() {
+			n, err := hotcache.FetchSnapshot(context.Background(), *warmFromPeer, cache)
+			if err != nil {
+				log.Printf("grpc-router: hotcache warm-up from %s failed, starting cold: %v", *warmFromPeer, err)
+			} else {
+				log.Printf("grpc-router: warmed hotcache with %d entries from %s", n, *warmFromPeer)
+			}
+			ready.Store(true)
+		}()
+	}
+
+	if *adminAddr != "" {
+		go func() {
+			log.Printf("grpc-router: admin listening on %s", *adminAddr)
+			if err := admin.ListenAndServe(*adminAddr); err != nil {
+				log.Printf("grpc-router: admin server: %v", err)
+			}
+		}()
+	}
+
+	runner := shutdown.New()
+	runner.Register(shutdown.Component{
+		Name:    "http server",
+		Timeout: 15 * time.Second,
+		Stop: func(ctx context.Context) error {
+			return server.Shutdown(ctx)
+		},
+	})
+	runner.Wait(func(component string, err error) {
+		log.Printf("grpc-router: %v", err)
+	})
+}
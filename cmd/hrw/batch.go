@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+)
+
+// runBatch reads keys one per line from r and writes a routing table to w:
+// plain "key\tnode" lines when k <= 1, or one JSON object per line
+// ({"key":...,"nodes":[...]}) when k > 1. Keeping everything in a single
+// process avoids the fork-per-key overhead of shelling out to hrw in a
+// loop.
+func runBatch(r io.Reader, w io.Writer, hf hashring.HashFunc, seed string, nodes []hashring.Node, k int) error {
+	scanner := bufio.NewScanner(r)
+	enc := json.NewEncoder(w)
+	for scanner.Scan() {
+		key := scanner.Text()
+		if key == "" {
+			continue
+		}
+		if k <= 1 {
+			fmt.Fprintf(w, "%s\t%s\n", key, hashring.RouteWithHash(hf, seed, key, nodes))
+			continue
+		}
+		if err := enc.Encode(map[string]interface{}{
+			"key":   key,
+			"nodes": hashring.TopKWithHash(hf, seed, key, nodes, k),
+		}); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+)
+
+// runDistribution samples keys and reports how evenly they spread across
+// nodes, so a weight or hash-function change can be sanity-checked before
+// it ships.
+func runDistribution(hf hashring.HashFunc, seed string, nodes []hashring.Node, keysFile string, sample int) error {
+	keys, err := loadOrSampleKeys(keysFile, sample)
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, key := range keys {
+		counts[hashring.RouteWithHash(hf, seed, key, nodes)]++
+	}
+
+	totalWeight := 0.0
+	for _, n := range nodes {
+		w := n.Weight
+		if w <= 0 {
+			w = 1
+		}
+		totalWeight += w
+	}
+
+	ids := make([]string, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID
+	}
+	sort.Strings(ids)
+
+	fmt.Printf("keys sampled: %d across %d node(s)\n\n", len(keys), len(nodes))
+	fmt.Printf("%-20s %10s %12s %12s\n", "node", "count", "expected", "deviation")
+
+	var maxDeviation float64
+	weightByID := map[string]float64{}
+	for _, n := range nodes {
+		w := n.Weight
+		if w <= 0 {
+			w = 1
+		}
+		weightByID[n.ID] = w
+	}
+	for _, id := range ids {
+		expected := float64(len(keys)) * weightByID[id] / totalWeight
+		actual := float64(counts[id])
+		deviation := 0.0
+		if expected > 0 {
+			deviation = 100 * (actual - expected) / expected
+		}
+		if math.Abs(deviation) > math.Abs(maxDeviation) {
+			maxDeviation = deviation
+		}
+		fmt.Printf("%-20s %10d %12.1f %11.1f%%\n", id, counts[id], expected, deviation)
+	}
+	fmt.Printf("\nmax deviation from expected: %.1f%%\n", maxDeviation)
+	return nil
+}
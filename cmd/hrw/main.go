@@ -0,0 +1,268 @@
+// Command hrw routes keys to nodes using weighted rendezvous hashing, on
+// top of the shared pkg/hashring library also used by grpc-router.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+	"github.com/cgb808/ZenGlowNext/pkg/nodeset"
+)
+
+func main() {
+	var (
+		cmd        = flag.String("cmd", "route", "command: route, rebalance, distribution, serve, or batch")
+		mode       = flag.String("mode", "hrw", "routing mode: hrw or jump")
+		key        = flag.String("key", "", "key to route")
+		nodesArg   = flag.String("nodes", "", "comma-separated nodes, each id or id:weight")
+		k          = flag.Int("k", 1, "number of nodes to return (hrw mode only)")
+		zoneAware  = flag.Bool("zone-aware", false, "spread the -k replicas across distinct node zones (hrw mode only)")
+		buckets    = flag.Int("buckets", 0, "number of buckets (jump mode only)")
+		tableSize  = flag.Int("table-size", 65537, "lookup table size (maglev mode only, should be prime)")
+		exportJSON = flag.Bool("export-json", false, "print the full maglev table as JSON instead of looking up -key")
+
+		oldNodesArg = flag.String("old-nodes", "", "comma-separated old node set (rebalance cmd)")
+		newNodesArg = flag.String("new-nodes", "", "comma-separated new node set (rebalance cmd)")
+		keysFile    = flag.String("keys-file", "", "newline-delimited key set to sample (rebalance cmd)")
+		sample      = flag.Int("sample", 10000, "number of synthetic keys to sample when -keys-file is unset (rebalance cmd)")
+
+		addr = flag.String("addr", ":8090", "listen address (serve cmd)")
+
+		hashName = flag.String("hash", "fnv1a", "hash function: fnv1a, crc32, or sha256")
+		seed     = flag.String("seed", "", "seed mixed into every hash, for running an independent ring")
+
+		loadsArg       = flag.String("loads", "", "comma-separated id:count current load per node (hrw mode, bounded load)")
+		capacityFactor = flag.Float64("capacity-factor", 1.25, "max multiple of fair share a node may carry before spilling over (bounded load)")
+
+		nodesFile      = flag.String("nodes-file", "", "load the node set from this file instead of -nodes")
+		nodesEnv       = flag.String("nodes-env", "", "load the node set from this environment variable instead of -nodes")
+		nodesRedisAddr = flag.String("nodes-redis-addr", "", "load the node set from a Redis set at this host:port instead of -nodes")
+		nodesRedisKey  = flag.String("nodes-redis-key", "hrw:nodes", "Redis set key holding the node list (with -nodes-redis-addr)")
+		watchInterval  = flag.Duration("watch", 0, "poll -nodes-file/-nodes-env/-nodes-redis-addr at this interval and hot-reload (serve cmd)")
+	)
+	flag.Parse()
+
+	hf, err := parseHashFunc(*hashName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "hrw:", err)
+		os.Exit(2)
+	}
+
+	if *cmd == "rebalance" {
+		oldNodes, err := parseNodes(*oldNodesArg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		newNodes, err := parseNodes(*newNodesArg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		if err := runRebalance(hf, *seed, oldNodes, newNodes, *keysFile, *sample); err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *cmd == "distribution" {
+		nodes, err := parseNodes(*nodesArg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		if err := runDistribution(hf, *seed, nodes, *keysFile, *sample); err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *cmd == "serve" {
+		src, err := resolveNodeSource(*nodesFile, *nodesEnv, *nodesRedisAddr, *nodesRedisKey)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(2)
+		}
+		nodes, err := loadNodes(src, *nodesArg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		provider := newNodeProvider(nodes)
+		if src != nil && *watchInterval > 0 {
+			go nodeset.Watch(src, *watchInterval, func(updated []hashring.Node) {
+				log.Printf("hrw serve: node set updated, now %d node(s)", len(updated))
+				provider.set(updated)
+			}, func(err error) {
+				log.Printf("hrw serve: node source error: %v", err)
+			}, nil)
+		}
+		if err := runServe(*addr, provider); err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *cmd == "batch" {
+		nodes, err := parseNodes(*nodesArg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		if err := runBatch(os.Stdin, os.Stdout, hf, *seed, nodes, *k); err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *mode == "maglev" && *exportJSON {
+		nodes, err := parseNodes(*nodesArg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		table := hashring.BuildMaglevTableWithHash(hf, *seed, nodes, *tableSize)
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(table.Entries()); err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *key == "" {
+		fmt.Fprintln(os.Stderr, "usage: hrw -key KEY -nodes id[:weight],id[:weight],... [-k N]")
+		fmt.Fprintln(os.Stderr, "   or: hrw -mode jump -key KEY -buckets N")
+		fmt.Fprintln(os.Stderr, "   or: hrw -mode maglev -key KEY -nodes ... [-table-size N]")
+		os.Exit(2)
+	}
+
+	switch *mode {
+	case "maglev":
+		if *nodesArg == "" {
+			fmt.Fprintln(os.Stderr, "hrw: -nodes is required in maglev mode")
+			os.Exit(2)
+		}
+		nodes, err := parseNodes(*nodesArg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		table := hashring.BuildMaglevTableWithHash(hf, *seed, nodes, *tableSize)
+		fmt.Println(table.Lookup(*key))
+	case "jump":
+		if *buckets <= 0 {
+			fmt.Fprintln(os.Stderr, "hrw: -buckets must be > 0 in jump mode")
+			os.Exit(2)
+		}
+		fmt.Println(hashring.JumpWithHash(hf, *seed, *key, *buckets))
+	case "hrw":
+		if *nodesArg == "" {
+			fmt.Fprintln(os.Stderr, "hrw: -nodes is required in hrw mode")
+			os.Exit(2)
+		}
+		nodes, err := parseNodes(*nodesArg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hrw:", err)
+			os.Exit(1)
+		}
+		if *loadsArg != "" {
+			loads, err := parseLoads(*loadsArg)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "hrw:", err)
+				os.Exit(1)
+			}
+			fmt.Println(hashring.RouteBoundedWithHash(hf, *seed, *key, nodes, loads, *capacityFactor))
+			return
+		}
+		if *k <= 1 {
+			fmt.Println(hashring.RouteWithHash(hf, *seed, *key, nodes))
+			return
+		}
+		picks := hashring.TopKWithHash(hf, *seed, *key, nodes, *k)
+		if *zoneAware {
+			picks = hashring.TopKZoneAware(*key, nodes, *k)
+		}
+		for _, id := range picks {
+			fmt.Println(id)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "hrw: unknown mode %q (want hrw or jump)\n", *mode)
+		os.Exit(2)
+	}
+}
+
+// parseLoads parses a comma-separated "id:count" list into a load map.
+func parseLoads(arg string) (map[string]int, error) {
+	loads := map[string]int{}
+	for _, p := range strings.Split(arg, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		id, countStr, ok := strings.Cut(p, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid load entry %q, want id:count", p)
+		}
+		count, err := strconv.Atoi(countStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid load count for node %q: %w", id, err)
+		}
+		loads[id] = count
+	}
+	return loads, nil
+}
+
+// parseHashFunc maps the -hash flag to a hashring.HashFunc.
+func parseHashFunc(name string) (hashring.HashFunc, error) {
+	switch name {
+	case "fnv1a", "":
+		return hashring.HashFNV1a, nil
+	case "crc32":
+		return hashring.HashCRC32, nil
+	case "sha256":
+		return hashring.HashSHA256, nil
+	default:
+		return 0, fmt.Errorf("unknown -hash %q (want fnv1a, crc32, or sha256)", name)
+	}
+}
+
+// parseNodes parses a comma-separated "id[:weight[:zone]]" list. A bare id
+// defaults to weight 1 and no zone.
+func parseNodes(arg string) ([]hashring.Node, error) {
+	parts := strings.Split(arg, ",")
+	nodes := make([]hashring.Node, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		fields := strings.Split(p, ":")
+		id := fields[0]
+		weight := 1.0
+		zone := ""
+		if len(fields) > 1 && fields[1] != "" {
+			w, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid weight for node %q: %w", id, err)
+			}
+			weight = w
+		}
+		if len(fields) > 2 {
+			zone = fields[2]
+		}
+		nodes = append(nodes, hashring.Node{ID: id, Weight: weight, Zone: zone})
+	}
+	return nodes, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+	"github.com/cgb808/ZenGlowNext/pkg/nodeset"
+)
+
+// resolveNodeSource returns the nodeset.Source implied by the dynamic node
+// flags (file, env, or Redis), or nil if none were set and the caller
+// should fall back to the static -nodes flag.
+func resolveNodeSource(nodesFile, nodesEnv, redisAddr, redisKey string) (nodeset.Source, error) {
+	set := 0
+	var src nodeset.Source
+	if nodesFile != "" {
+		src = nodeset.FileSource{Path: nodesFile}
+		set++
+	}
+	if nodesEnv != "" {
+		src = nodeset.EnvSource{Var: nodesEnv}
+		set++
+	}
+	if redisAddr != "" {
+		src = nodeset.RedisSource{Addr: redisAddr, Key: redisKey}
+		set++
+	}
+	if set > 1 {
+		return nil, fmt.Errorf("only one of -nodes-file, -nodes-env, -nodes-redis-addr may be set")
+	}
+	return src, nil
+}
+
+// loadNodes resolves the node set once, preferring a dynamic source over
+// the static -nodes flag.
+func loadNodes(src nodeset.Source, staticArg string) ([]hashring.Node, error) {
+	if src != nil {
+		return src.Load()
+	}
+	return parseNodes(staticArg)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+)
+
+// runRebalance compares key placement across oldNodes and newNodes, using
+// keys either read from keysFile (one per line) or synthetically sampled,
+// and reports how much churn the membership change causes.
+func runRebalance(hf hashring.HashFunc, seed string, oldNodes, newNodes []hashring.Node, keysFile string, sample int) error {
+	keys, err := loadOrSampleKeys(keysFile, sample)
+	if err != nil {
+		return err
+	}
+
+	oldCounts := map[string]int{}
+	newCounts := map[string]int{}
+	moved, avoidableMoves := 0, 0
+	oldIDs := nodeSet(oldNodes)
+
+	for _, key := range keys {
+		from := hashring.RouteWithHash(hf, seed, key, oldNodes)
+		to := hashring.RouteWithHash(hf, seed, key, newNodes)
+		oldCounts[from]++
+		newCounts[to]++
+		if from == to {
+			continue
+		}
+		moved++
+		// A move is "avoidable" if the key's old node is still present in
+		// the new node set: HRW/consistent hashing should only move keys
+		// whose old node left (or whose new highest-scoring node just
+		// joined), so a move away from a still-present node indicates
+		// unnecessary churn.
+		if oldIDs[from] && nodeSet(newNodes)[from] {
+			avoidableMoves++
+		}
+	}
+
+	fmt.Printf("keys sampled:      %d\n", len(keys))
+	fmt.Printf("keys moved:        %d (%.1f%%)\n", moved, pct(moved, len(keys)))
+	fmt.Printf("avoidable moves:   %d (%.1f%%)\n", avoidableMoves, pct(avoidableMoves, len(keys)))
+	fmt.Println()
+	fmt.Println("per-node counts (before -> after):")
+	printCounts("old", oldNodes, oldCounts)
+	printCounts("new", newNodes, newCounts)
+	return nil
+}
+
+func printCounts(label string, nodes []hashring.Node, counts map[string]int) {
+	ids := make([]string, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Printf("  [%s] %-20s %d\n", label, id, counts[id])
+	}
+}
+
+func nodeSet(nodes []hashring.Node) map[string]bool {
+	s := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		s[n.ID] = true
+	}
+	return s
+}
+
+func pct(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// loadOrSampleKeys reads newline-delimited keys from path if non-empty,
+// otherwise synthesizes `sample` deterministic keys for reproducible runs.
+func loadOrSampleKeys(path string, sample int) ([]string, error) {
+	if path == "" {
+		keys := make([]string, sample)
+		for i := range keys {
+			keys[i] = fmt.Sprintf("key-%d", i)
+		}
+		return keys, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening keys file: %w", err)
+	}
+	defer f.Close()
+
+	var keys []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			keys = append(keys, line)
+		}
+	}
+	return keys, scanner.Err()
+}
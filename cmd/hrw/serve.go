@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+	"github.com/cgb808/ZenGlowNext/pkg/telemetry"
+)
+
+// nodeProvider holds the current node set behind a mutex so it can be
+// swapped out by a nodeset.Watch callback while the HTTP handler is
+// reading it concurrently.
+type nodeProvider struct {
+	mu    sync.RWMutex
+	nodes []hashring.Node
+}
+
+func newNodeProvider(nodes []hashring.Node) *nodeProvider {
+	return &nodeProvider{nodes: nodes}
+}
+
+func (p *nodeProvider) set(nodes []hashring.Node) {
+	p.mu.Lock()
+	p.nodes = nodes
+	p.mu.Unlock()
+}
+
+func (p *nodeProvider) get() []hashring.Node {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.nodes
+}
+
+// runServe starts an HTTP routing microservice so callers that need many
+// lookups don't have to fork hrw per key or pipe everything through batch
+// mode. GET /route?key=...&k=N returns the top-k nodes for key (k defaults
+// to 1). provider's node set may be updated concurrently, e.g. by a
+// nodeset.Watch goroutine.
+func runServe(addr string, provider *nodeProvider) error {
+	reg := telemetry.NewRegistry()
+
+	mux := http.NewServeMux()
+	mux.Handle("/route", telemetry.Middleware(reg, "hrw_serve", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("key")
+		if key == "" {
+			http.Error(w, "missing key", http.StatusBadRequest)
+			return
+		}
+		k := 1
+		if ks := r.URL.Query().Get("k"); ks != "" {
+			fmt.Sscanf(ks, "%d", &k)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"key":   key,
+			"nodes": hashring.TopK(key, provider.get(), k),
+		})
+	})))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("/metrics", telemetry.Handler(reg))
+
+	log.Printf("hrw serve: listening on %s with %d node(s)", addr, len(provider.get()))
+	return http.ListenAndServe(addr, mux)
+}
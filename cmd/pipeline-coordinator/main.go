@@ -0,0 +1,76 @@
+// Command pipeline-coordinator drains rotated log segments out of the
+// logservice segment-rotation queue (a Redis list by default, or a
+// pkg/bus topic via -queue-backend), streams them through the ingester,
+// and confirms each one becomes visible in CanonicalService before
+// moving on. It is the glue that connects those three services, which
+// today only exist as independent pieces.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/bus"
+	"github.com/cgb808/ZenGlowNext/pkg/client"
+	"github.com/cgb808/ZenGlowNext/pkg/pipeline"
+)
+
+func main() {
+	var (
+		queueBackend  = flag.String("queue-backend", "redis", "segment-rotation queue backend: redis, redis-streams, nats, or kafka")
+		redisAddr     = flag.String("redis-addr", "localhost:6379", "logservice segment queue address, used for -queue-backend=redis and redis-streams")
+		redisKey      = flag.String("redis-key", "logservice:segments", "list key segments are pushed onto, used for -queue-backend=redis")
+		busAddr       = flag.String("bus-addr", "localhost:4222", "broker address, used for -queue-backend=nats")
+		busBrokers    = flag.String("bus-brokers", "localhost:9092", "comma-separated broker list, used for -queue-backend=kafka")
+		busTopic      = flag.String("bus-topic", "logservice.segments", "topic segments are published to, used for -queue-backend=redis-streams, nats, and kafka")
+		ingesterAddr  = flag.String("ingester-addr", "http://localhost:8090", "IngestionService address")
+		canonicalAddr = flag.String("canonical-addr", "http://localhost:8091", "CanonicalService address")
+	)
+	flag.Parse()
+
+	source, err := segmentSource(*queueBackend, *redisAddr, *redisKey, *busAddr, *busBrokers, *busTopic)
+	if err != nil {
+		log.Fatalf("pipeline-coordinator: %v", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	coord := &pipeline.Coordinator{
+		Source: source,
+		Sink:   client.NewIngestionClient(*ingesterAddr),
+		Check:  client.NewCanonicalClient(*canonicalAddr),
+	}
+
+	log.Printf("pipeline-coordinator: watching for segments via -queue-backend=%s", *queueBackend)
+	coord.Run(ctx, func(eventIDs []string, err error) {
+		if err != nil {
+			log.Printf("pipeline-coordinator: %v", err)
+			time.Sleep(time.Second)
+			return
+		}
+		log.Printf("pipeline-coordinator: confirmed %d event(s) visible", len(eventIDs))
+	})
+}
+
+// segmentSource builds the pipeline.SegmentSource for backend, the same
+// backend-selection shape cmd/zenglow's dlq subcommand uses.
+func segmentSource(backend, redisAddr, redisKey, busAddr, busBrokers, busTopic string) (pipeline.SegmentSource, error) {
+	switch backend {
+	case "redis":
+		return &pipeline.RedisSegmentSource{Addr: redisAddr, Key: redisKey}, nil
+	case "redis-streams":
+		return &pipeline.BusSegmentSource{Subscriber: &bus.RedisStreams{Addr: redisAddr}, Topic: busTopic}, nil
+	case "nats":
+		return &pipeline.BusSegmentSource{Subscriber: &bus.NATS{Addr: busAddr}, Topic: busTopic}, nil
+	case "kafka":
+		return &pipeline.BusSegmentSource{Subscriber: &bus.Kafka{Brokers: busBrokers}, Topic: busTopic}, nil
+	default:
+		return nil, fmt.Errorf("unknown -queue-backend %q", backend)
+	}
+}
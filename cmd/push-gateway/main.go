@@ -0,0 +1,92 @@
+// Command push-gateway lets UI clients register a standing query over
+// SSE or WebSocket and polls the canonical events table's change feed
+// (see pkg/replication) for newly ingested rows, pushing each one to
+// every registered query it matches. See pkg/pushgateway for the
+// matching and transport logic.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/admin"
+	"github.com/cgb808/ZenGlowNext/pkg/pushgateway"
+	"github.com/cgb808/ZenGlowNext/pkg/replication"
+	"github.com/cgb808/ZenGlowNext/pkg/secrets"
+	"github.com/cgb808/ZenGlowNext/pkg/telemetry"
+)
+
+func main() {
+	var (
+		addr         = flag.String("addr", ":8083", "listen address")
+		databaseURL  = flag.String("database-url", "", "events table's Postgres connection string; falls back to the DATABASE_URL secret")
+		table        = flag.String("table", "events", "events table to watch")
+		updatedAtCol = flag.String("updated-at-column", "updated_at", "column used as the change feed watermark")
+		columnsArg   = flag.String("columns", "embedding", "comma-separated list of columns to match standing queries against, excluding id and the updated-at column")
+		interval     = flag.Duration("interval", time.Second, "how often to poll the change feed")
+		adminAddr    = flag.String("admin-addr", "", "loopback address for pprof/debug endpoints, e.g. 127.0.0.1:6060 (disabled if empty)")
+	)
+	flag.Parse()
+
+	resolver := secrets.New(secrets.EnvSource{}, 0)
+	if *databaseURL == "" {
+		*databaseURL, _ = resolver.Get(context.Background(), "DATABASE_URL")
+	}
+	if *databaseURL == "" {
+		log.Fatal("push-gateway: -database-url is required (or set DATABASE_URL)")
+	}
+
+	var columns []string
+	for _, c := range strings.Split(*columnsArg, ",") {
+		c = strings.TrimSpace(c)
+		if c != "" {
+			columns = append(columns, c)
+		}
+	}
+
+	reg := telemetry.NewRegistry()
+	registry := pushgateway.NewRegistry()
+
+	worker := &replication.Worker{
+		Source: replication.PostgresSource{
+			DatabaseURL:     *databaseURL,
+			Table:           *table,
+			UpdatedAtColumn: *updatedAtCol,
+			Columns:         columns,
+		},
+		Sink:     pushgateway.ChangeFeedSink{Registry: registry},
+		Interval: *interval,
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	go worker.Run(ctx, func(err error) {
+		log.Printf("push-gateway: polling change feed: %v", err)
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/subscribe/sse", pushgateway.SSEHandler(registry))
+	mux.Handle("/subscribe/ws", pushgateway.WebSocketHandler(registry))
+	mux.Handle("/metrics", telemetry.Handler(reg))
+
+	if *adminAddr != "" {
+		go func() {
+			log.Printf("push-gateway: admin listening on %s", *adminAddr)
+			if err := admin.ListenAndServe(*adminAddr); err != nil {
+				log.Printf("push-gateway: admin server: %v", err)
+			}
+		}()
+	}
+
+	log.Printf("push-gateway: listening on %s, watching %s.%s every %s", *addr, *table, *updatedAtCol, *interval)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,96 @@
+// Command replicator polls a primary region's Postgres for changed event
+// rows and applies them to a secondary region's Postgres, acting as a
+// warm DR feed. See pkg/replication for the change-feed and
+// last-writer-wins apply logic.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/admin"
+	"github.com/cgb808/ZenGlowNext/pkg/replication"
+	"github.com/cgb808/ZenGlowNext/pkg/secrets"
+	"github.com/cgb808/ZenGlowNext/pkg/telemetry"
+)
+
+func main() {
+	var (
+		primaryURL   = flag.String("primary-url", "", "primary region Postgres connection string; falls back to the PRIMARY_DATABASE_URL secret")
+		secondaryURL = flag.String("secondary-url", "", "secondary region Postgres connection string; falls back to the SECONDARY_DATABASE_URL secret")
+		table        = flag.String("table", "events", "table to replicate")
+		updatedAtCol = flag.String("updated-at-column", "updated_at", "column used as the change feed watermark")
+		columnsArg   = flag.String("columns", "", "comma-separated list of columns to replicate, excluding id and the updated-at column")
+		interval     = flag.Duration("interval", 5*time.Second, "how often to poll for changes")
+		metricsAddr  = flag.String("metrics-addr", ":9108", "listen address for /metrics")
+		adminAddr    = flag.String("admin-addr", "", "loopback address for pprof/debug endpoints, e.g. 127.0.0.1:6060 (disabled if empty)")
+	)
+	flag.Parse()
+
+	resolver := secrets.New(secrets.EnvSource{}, 0)
+	if *primaryURL == "" {
+		*primaryURL, _ = resolver.Get(context.Background(), "PRIMARY_DATABASE_URL")
+	}
+	if *secondaryURL == "" {
+		*secondaryURL, _ = resolver.Get(context.Background(), "SECONDARY_DATABASE_URL")
+	}
+	if *primaryURL == "" || *secondaryURL == "" {
+		log.Fatal("replicator: -primary-url and -secondary-url are required (or set PRIMARY_DATABASE_URL / SECONDARY_DATABASE_URL)")
+	}
+
+	var columns []string
+	for _, c := range strings.Split(*columnsArg, ",") {
+		c = strings.TrimSpace(c)
+		if c != "" {
+			columns = append(columns, c)
+		}
+	}
+
+	reg := telemetry.NewRegistry()
+
+	worker := &replication.Worker{
+		Source: replication.PostgresSource{
+			DatabaseURL:     *primaryURL,
+			Table:           *table,
+			UpdatedAtColumn: *updatedAtCol,
+			Columns:         columns,
+		},
+		Sink: replication.PostgresSink{
+			DatabaseURL:     *secondaryURL,
+			Table:           *table,
+			UpdatedAtColumn: *updatedAtCol,
+		},
+		Interval:   *interval,
+		LagSeconds: reg.Gauge("replication_lag_seconds"),
+	}
+
+	go func() {
+		log.Printf("replicator: metrics listening on %s", *metricsAddr)
+		if err := http.ListenAndServe(*metricsAddr, telemetry.Handler(reg)); err != nil {
+			log.Printf("replicator: metrics server: %v", err)
+		}
+	}()
+
+	if *adminAddr != "" {
+		go func() {
+			log.Printf("replicator: admin listening on %s", *adminAddr)
+			if err := admin.ListenAndServe(*adminAddr); err != nil {
+				log.Printf("replicator: admin server: %v", err)
+			}
+		}()
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	log.Printf("replicator: replicating %s.%s every %s", *table, *updatedAtCol, *interval)
+	worker.Run(ctx, func(err error) {
+		log.Printf("replicator: %v", err)
+	})
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// runCall invokes an arbitrary service method by name with a JSON body
+// read from path ("-" for stdin), for ad hoc calls the CLI has no typed
+// flags for.
+func runCall(ctx context.Context, client *topkclient.Client, method, bodyPath string) error {
+	if method == "" {
+		return fmt.Errorf("usage: topk-client -cmd call -method NAME [-body-file FILE]")
+	}
+
+	raw, err := readBody(bodyPath)
+	if err != nil {
+		return err
+	}
+
+	var body interface{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &body); err != nil {
+			return fmt.Errorf("parsing -body-file as JSON: %w", err)
+		}
+	}
+
+	resp, err := client.Call(ctx, method, body)
+	if err != nil {
+		return err
+	}
+
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, resp, "", "  "); err != nil {
+		fmt.Println(string(resp)) // not JSON-formattable; print raw
+		return nil
+	}
+	fmt.Println(pretty.String())
+	return nil
+}
+
+func readBody(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,25 @@
+package main
+
+import "github.com/cgb808/ZenGlowNext/pkg/topkclient"
+
+// newClient builds a topkclient.Client from the CLI's TLS and auth flags,
+// taking the plain topkclient.New path when none of them are set.
+func newClient(addr, authToken, tlsCert, tlsKey, tlsCA string, insecureSkipVerify bool) (*topkclient.Client, error) {
+	var client *topkclient.Client
+	if tlsCert == "" && tlsKey == "" && tlsCA == "" && !insecureSkipVerify {
+		client = topkclient.New(addr)
+	} else {
+		c, err := topkclient.NewWithTLS(addr, topkclient.TLSOptions{
+			CAFile:             tlsCA,
+			CertFile:           tlsCert,
+			KeyFile:            tlsKey,
+			InsecureSkipVerify: insecureSkipVerify,
+		})
+		if err != nil {
+			return nil, err
+		}
+		client = c
+	}
+	client.AuthToken = authToken
+	return client, nil
+}
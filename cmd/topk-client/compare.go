@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// runCompare issues q against both clients and prints a side-by-side
+// ranked diff, so a candidate server (e.g. a new index build) can be
+// sanity-checked against production before cutover.
+func runCompare(ctx context.Context, w io.Writer, a, b *topkclient.Client, q topkclient.Query) error {
+	resultA, err := a.Search(ctx, q)
+	if err != nil {
+		return fmt.Errorf("server A (%s): %w", a.Addr, err)
+	}
+	resultB, err := b.Search(ctx, q)
+	if err != nil {
+		return fmt.Errorf("server B (%s): %w", b.Addr, err)
+	}
+
+	rankB := map[string]int{}
+	for i, m := range resultB.Matches {
+		rankB[m.ID] = i
+	}
+
+	fmt.Fprintf(w, "%-4s %-40s %10s %10s %10s\n", "rank", "id", "score A", "score B", "rank B")
+	onlyInA := 0
+	for i, m := range resultA.Matches {
+		rank, ok := rankB[m.ID]
+		rankStr := "-"
+		scoreB := "-"
+		if ok {
+			rankStr = fmt.Sprintf("%d", rank)
+			scoreB = fmt.Sprintf("%.4f", resultB.Matches[rank].Score)
+		} else {
+			onlyInA++
+		}
+		fmt.Fprintf(w, "%-4d %-40s %10.4f %10s %10s\n", i, m.ID, m.Score, scoreB, rankStr)
+	}
+
+	onlyInB := 0
+	rankA := map[string]bool{}
+	for _, m := range resultA.Matches {
+		rankA[m.ID] = true
+	}
+	for _, m := range resultB.Matches {
+		if !rankA[m.ID] {
+			onlyInB++
+		}
+	}
+
+	fmt.Fprintf(w, "\nonly in A: %d, only in B: %d\n", onlyInA, onlyInB)
+	return nil
+}
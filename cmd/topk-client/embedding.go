@@ -0,0 +1,23 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// readEmbedding loads a precomputed embedding vector from a JSON file
+// containing a flat array of numbers, e.g. [0.12, -0.04, ...]. This lets
+// callers skip the service's own text->embedding step when they already
+// have vectors on hand (batch re-scoring, cross-model comparisons).
+func readEmbedding(path string) ([]float32, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading embedding file: %w", err)
+	}
+	var vec []float32
+	if err := json.Unmarshal(data, &vec); err != nil {
+		return nil, fmt.Errorf("parsing embedding file: %w", err)
+	}
+	return vec, nil
+}
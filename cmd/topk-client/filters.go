@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/filterdsl"
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// parseFilters builds a topkclient.Filters from the CLI's filter flags,
+// returning nil if none were set.
+func parseFilters(user, eventType, since, until string) (*topkclient.Filters, error) {
+	if user == "" && eventType == "" && since == "" && until == "" {
+		return nil, nil
+	}
+
+	f := &topkclient.Filters{User: user, Type: eventType}
+	if since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -filter-since %q: %w", since, err)
+		}
+		f.From = &t
+	}
+	if until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -filter-until %q: %w", until, err)
+		}
+		f.To = &t
+	}
+	return f, nil
+}
+
+// parseFilterExpr builds a topkclient.Filters from a pkg/filterdsl
+// expression, the same grammar CanonicalService's SQL filtering and the
+// ingester's in-memory filtering will use. topkclient.Filters can only
+// represent a flat conjunction of comparisons on "user", "type", and
+// "created_at", so Or and Not anywhere in expr are rejected rather than
+// silently dropped.
+func parseFilterExpr(expr string) (*topkclient.Filters, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	parsed, err := filterdsl.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -filter %q: %w", expr, err)
+	}
+
+	f := &topkclient.Filters{}
+	if err := collectFilterTerms(parsed, f); err != nil {
+		return nil, fmt.Errorf("invalid -filter %q: %w", expr, err)
+	}
+	return f, nil
+}
+
+func collectFilterTerms(expr filterdsl.Expr, f *topkclient.Filters) error {
+	switch e := expr.(type) {
+	case filterdsl.And:
+		if err := collectFilterTerms(e.Left, f); err != nil {
+			return err
+		}
+		return collectFilterTerms(e.Right, f)
+
+	case filterdsl.Cmp:
+		switch e.Field {
+		case "user":
+			if e.Op != filterdsl.Eq {
+				return fmt.Errorf("user only supports =")
+			}
+			f.User = e.Value
+		case "type":
+			if e.Op != filterdsl.Eq {
+				return fmt.Errorf("type only supports =")
+			}
+			f.Type = e.Value
+		case "created_at":
+			t, err := time.Parse(time.RFC3339, e.Value)
+			if err != nil {
+				return fmt.Errorf("created_at: %w", err)
+			}
+			switch e.Op {
+			case filterdsl.Gte, filterdsl.Gt:
+				f.From = &t
+			case filterdsl.Lte, filterdsl.Lt:
+				f.To = &t
+			default:
+				return fmt.Errorf("created_at does not support %s", e.Op)
+			}
+		default:
+			return fmt.Errorf("unsupported field %q", e.Field)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("only a conjunction of comparisons is supported, not %T", expr)
+	}
+}
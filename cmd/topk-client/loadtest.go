@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// loadTestResult summarizes a load-test run.
+type loadTestResult struct {
+	requests int
+	errors   int
+	duration time.Duration
+	latency  []time.Duration // sorted ascending
+}
+
+// runLoadTest fires queries against client with concurrency workers for
+// duration, reporting throughput and latency percentiles.
+func runLoadTest(client *topkclient.Client, query string, k, concurrency int, duration time.Duration) *loadTestResult {
+	var (
+		mu      sync.Mutex
+		latency []time.Duration
+		errs    int
+	)
+
+	deadline := time.Now().Add(duration)
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				reqStart := time.Now()
+				_, err := client.Search(context.Background(), topkclient.Query{Text: query, K: k})
+				elapsed := time.Since(reqStart)
+
+				mu.Lock()
+				if err != nil {
+					errs++
+				} else {
+					latency = append(latency, elapsed)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(latency, func(i, j int) bool { return latency[i] < latency[j] })
+	return &loadTestResult{
+		requests: len(latency) + errs,
+		errors:   errs,
+		duration: time.Since(start),
+		latency:  latency,
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+func (r *loadTestResult) write(w io.Writer) {
+	fmt.Fprintf(w, "requests: %d (errors: %d)\n", r.requests, r.errors)
+	fmt.Fprintf(w, "duration: %s\n", r.duration)
+	if r.duration > 0 {
+		fmt.Fprintf(w, "throughput: %.1f req/s\n", float64(r.requests)/r.duration.Seconds())
+	}
+	fmt.Fprintf(w, "latency p50: %s\n", percentile(r.latency, 0.50))
+	fmt.Fprintf(w, "latency p90: %s\n", percentile(r.latency, 0.90))
+	fmt.Fprintf(w, "latency p99: %s\n", percentile(r.latency, 0.99))
+}
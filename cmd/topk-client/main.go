@@ -0,0 +1,186 @@
+// Command topk-client queries the top-k vector search service from the
+// command line.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+func main() {
+	var (
+		addr   = flag.String("addr", "http://localhost:8092", "topk service address")
+		query  = flag.String("query", "", "query text")
+		k      = flag.Int("k", 10, "number of results")
+		format = flag.String("format", "text", "output format: text, json, or csv")
+
+		cmd         = flag.String("cmd", "search", "command: search, loadtest, watch, call, compare, record-session, or replay-session")
+		concurrency = flag.Int("concurrency", 10, "number of concurrent workers (loadtest cmd)")
+		duration    = flag.Duration("duration", 10*time.Second, "how long to run (loadtest cmd)")
+
+		authToken          = flag.String("auth-token", "", "bearer token sent as the Authorization header")
+		tlsCert            = flag.String("tls-cert", "", "client certificate file, for mutual TLS")
+		tlsKey             = flag.String("tls-key", "", "client private key file, for mutual TLS")
+		tlsCA              = flag.String("tls-ca", "", "CA bundle to trust, instead of the system pool")
+		insecureSkipVerify = flag.Bool("insecure-skip-verify", false, "skip server certificate verification (testing only)")
+
+		embeddingFile = flag.String("embedding-file", "", "JSON file with a precomputed embedding vector, used instead of -query")
+
+		filterUser  = flag.String("filter-user", "", "restrict results to this user")
+		filterType  = flag.String("filter-type", "", "restrict results to this event type")
+		filterSince = flag.String("filter-since", "", "restrict results to events at or after this RFC3339 time")
+		filterUntil = flag.String("filter-until", "", "restrict results to events at or before this RFC3339 time")
+		filterExpr  = flag.String("filter", "", `pkg/filterdsl expression, e.g. user = "alice" AND created_at >= "2024-01-01T00:00:00Z"; takes precedence over the individual -filter-* flags`)
+
+		watchInterval = flag.Duration("watch-interval", 5*time.Second, "poll interval (watch cmd)")
+
+		retries  = flag.Int("retries", 0, "number of retries on failure, with exponential backoff")
+		backoff  = flag.Duration("backoff", 200*time.Millisecond, "initial backoff between retries")
+		deadline = flag.Duration("deadline", 0, "overall deadline for the request(s); 0 means no deadline")
+
+		method   = flag.String("method", "", "service method to invoke (call cmd)")
+		bodyFile = flag.String("body-file", "", "JSON request body file, or - for stdin (call cmd)")
+
+		addr2 = flag.String("addr2", "", "second topk service address to compare against -addr (compare cmd)")
+
+		sessionFile = flag.String("session-file", "", "saved query session file (record-session/replay-session cmds)")
+
+		explain = flag.Bool("explain", false, "ask the service for a score explanation with each match")
+	)
+	flag.Parse()
+
+	client, err := newClient(*addr, *authToken, *tlsCert, *tlsKey, *tlsCA, *insecureSkipVerify)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "topk-client:", err)
+		os.Exit(1)
+	}
+
+	if *cmd == "call" {
+		ctx := context.Background()
+		if *deadline > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *deadline)
+			defer cancel()
+		}
+		if err := runCall(ctx, client, *method, *bodyFile); err != nil {
+			fmt.Fprintln(os.Stderr, "topk-client:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *cmd == "loadtest" {
+		if *query == "" {
+			fmt.Fprintln(os.Stderr, "usage: topk-client -cmd loadtest -query TEXT [-concurrency N] [-duration 30s]")
+			os.Exit(2)
+		}
+		result := runLoadTest(client, *query, *k, *concurrency, *duration)
+		result.write(os.Stdout)
+		return
+	}
+
+	q := topkclient.Query{Text: *query, K: *k, Explain: *explain}
+	if *embeddingFile != "" {
+		embedding, err := readEmbedding(*embeddingFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "topk-client:", err)
+			os.Exit(1)
+		}
+		q.Text = ""
+		q.Embedding = embedding
+	}
+
+	if *query == "" && *embeddingFile == "" && *cmd != "replay-session" {
+		fmt.Fprintln(os.Stderr, "usage: topk-client -query TEXT [-k N] [-format text|json|csv]")
+		fmt.Fprintln(os.Stderr, "   or: topk-client -embedding-file vec.json [-k N]")
+		os.Exit(2)
+	}
+
+	var filters *topkclient.Filters
+	if *filterExpr != "" {
+		filters, err = parseFilterExpr(*filterExpr)
+	} else {
+		filters, err = parseFilters(*filterUser, *filterType, *filterSince, *filterUntil)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "topk-client:", err)
+		os.Exit(2)
+	}
+	q.Filters = filters
+
+	ctx := context.Background()
+	if *deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *deadline)
+		defer cancel()
+	}
+
+	if *cmd == "replay-session" {
+		if *sessionFile == "" {
+			fmt.Fprintln(os.Stderr, "topk-client: -session-file is required for -cmd replay-session")
+			os.Exit(2)
+		}
+		passed, err := replaySession(ctx, os.Stdout, client, *sessionFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "topk-client:", err)
+			os.Exit(1)
+		}
+		if !passed {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *cmd == "record-session" {
+		if *sessionFile == "" {
+			fmt.Fprintln(os.Stderr, "topk-client: -session-file is required for -cmd record-session")
+			os.Exit(2)
+		}
+		if err := recordSession(ctx, client, *sessionFile, q); err != nil {
+			fmt.Fprintln(os.Stderr, "topk-client:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *cmd == "compare" {
+		if *addr2 == "" {
+			fmt.Fprintln(os.Stderr, "topk-client: -addr2 is required for -cmd compare")
+			os.Exit(2)
+		}
+		clientB, err := newClient(*addr2, *authToken, *tlsCert, *tlsKey, *tlsCA, *insecureSkipVerify)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "topk-client:", err)
+			os.Exit(1)
+		}
+		if err := runCompare(ctx, os.Stdout, client, clientB, q); err != nil {
+			fmt.Fprintln(os.Stderr, "topk-client:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *cmd == "watch" {
+		if err := runWatch(ctx, os.Stdout, client, q, *watchInterval); err != nil {
+			fmt.Fprintln(os.Stderr, "topk-client:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	result, err := searchWithRetry(ctx, client, q, *retries, *backoff)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "topk-client:", err)
+		os.Exit(1)
+	}
+
+	if err := writeResult(os.Stdout, *format, result); err != nil {
+		fmt.Fprintln(os.Stderr, "topk-client:", err)
+		os.Exit(1)
+	}
+}
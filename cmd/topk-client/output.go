@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// writeResult renders result to w in the requested format.
+func writeResult(w io.Writer, format string, result *topkclient.Result) error {
+	switch format {
+	case "text", "":
+		for _, m := range result.Matches {
+			fmt.Fprintf(w, "%-40s %.4f\n", m.ID, m.Score)
+			if m.Explanation != nil {
+				explanation, err := json.Marshal(m.Explanation)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(w, "  explain: %s\n", explanation)
+			}
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"id", "score"}); err != nil {
+			return err
+		}
+		for _, m := range result.Matches {
+			if err := cw.Write([]string{m.ID, strconv.FormatFloat(m.Score, 'f', 6, 64)}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unknown -format %q (want text, json, or csv)", format)
+	}
+}
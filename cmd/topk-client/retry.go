@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// searchWithRetry calls client.Search, retrying up to maxRetries times
+// with exponential backoff (starting at baseBackoff, doubling each
+// attempt) on failure. ctx's deadline, if any, bounds the whole sequence.
+func searchWithRetry(ctx context.Context, client *topkclient.Client, q topkclient.Query, maxRetries int, baseBackoff time.Duration) (*topkclient.Result, error) {
+	var lastErr error
+	backoff := baseBackoff
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		result, err := client.Search(ctx, q)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+		if attempt == maxRetries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return nil, lastErr
+}
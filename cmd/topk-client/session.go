@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// sessionEntry is one recorded query and the node IDs it returned at
+// record time, used as the regression baseline on replay.
+type sessionEntry struct {
+	Query    topkclient.Query `json:"query"`
+	Expected []string         `json:"expected"`
+}
+
+// recordSession runs q, appends it with its current result as the
+// expected baseline, and rewrites path.
+func recordSession(ctx context.Context, client *topkclient.Client, path string, q topkclient.Query) error {
+	entries, err := loadSession(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	result, err := client.Search(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(result.Matches))
+	for i, m := range result.Matches {
+		ids[i] = m.ID
+	}
+	entries = append(entries, sessionEntry{Query: q, Expected: ids})
+
+	return saveSession(path, entries)
+}
+
+// replaySession re-runs every entry in path and reports whether its
+// current top-k IDs still match the recorded baseline, for catching
+// ranking regressions between service deploys.
+func replaySession(ctx context.Context, w io.Writer, client *topkclient.Client, path string) (allPassed bool, err error) {
+	entries, err := loadSession(path)
+	if err != nil {
+		return false, err
+	}
+
+	allPassed = true
+	for i, entry := range entries {
+		result, err := client.Search(ctx, entry.Query)
+		if err != nil {
+			fmt.Fprintf(w, "[%d] FAIL: %v\n", i, err)
+			allPassed = false
+			continue
+		}
+		actual := make([]string, len(result.Matches))
+		for j, m := range result.Matches {
+			actual[j] = m.ID
+		}
+		if reflect.DeepEqual(actual, entry.Expected) {
+			fmt.Fprintf(w, "[%d] PASS\n", i)
+			continue
+		}
+		allPassed = false
+		fmt.Fprintf(w, "[%d] FAIL: expected %v, got %v\n", i, entry.Expected, actual)
+	}
+	return allPassed, nil
+}
+
+func loadSession(path string) ([]sessionEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("reading session file: %w", err)
+	}
+	var entries []sessionEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing session file: %w", err)
+	}
+	return entries, nil
+}
+
+func saveSession(path string, entries []sessionEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding session file: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing session file: %w", err)
+	}
+	return nil
+}
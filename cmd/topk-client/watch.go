@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// runWatch re-issues q against client every interval, printing only the
+// matches not seen on a previous poll, until ctx is cancelled. This turns
+// a static top-k search into a crude tail of newly matching events.
+func runWatch(ctx context.Context, w io.Writer, client *topkclient.Client, q topkclient.Query, interval time.Duration) error {
+	seen := map[string]bool{}
+
+	poll := func() error {
+		result, err := client.Search(ctx, q)
+		if err != nil {
+			return err
+		}
+		for _, m := range result.Matches {
+			if seen[m.ID] {
+				continue
+			}
+			seen[m.ID] = true
+			fmt.Fprintf(w, "%s\t%-40s %.4f\n", time.Now().Format(time.RFC3339), m.ID, m.Score)
+		}
+		return nil
+	}
+
+	if err := poll(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := poll(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cgb808/ZenGlowNext/pkg/backup"
+)
+
+func runBackup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	segmentsDir := fs.String("segments-dir", "", "log segment directory to snapshot")
+	destDir := fs.String("dest-dir", "", "backup destination directory")
+	databaseURL := fs.String("database-url", "", "Postgres connection string; omit to skip events export")
+	table := fs.String("table", "events", "events table name")
+	incremental := fs.Bool("incremental", false, "skip segments already captured by dest-dir's manifest")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *segmentsDir == "" || *destDir == "" {
+		return fmt.Errorf("usage: zenglow backup -segments-dir DIR -dest-dir DIR [-database-url URL] [-incremental]")
+	}
+
+	manifestPath := filepath.Join(*destDir, "manifest.json")
+	prev, err := backup.LoadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := backup.SnapshotSegments(*segmentsDir, filepath.Join(*destDir, "segments"), prev, *incremental)
+	if err != nil {
+		return err
+	}
+	if err := manifest.Save(manifestPath); err != nil {
+		return err
+	}
+	fmt.Printf("snapshotted %d segment(s) to %s\n", len(manifest.Segments), *destDir)
+
+	if *databaseURL != "" {
+		eventsFile := filepath.Join(*destDir, "events.csv")
+		if err := backup.ExportEvents(context.Background(), *databaseURL, *table, eventsFile); err != nil {
+			return err
+		}
+		fmt.Printf("exported %s to %s\n", *table, eventsFile)
+	}
+	return nil
+}
+
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	srcDir := fs.String("src-dir", "", "backup directory created by zenglow backup")
+	segmentsDir := fs.String("segments-dir", "", "live log segment directory to restore into")
+	databaseURL := fs.String("database-url", "", "Postgres connection string; omit to skip events import")
+	table := fs.String("table", "events", "events table name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *srcDir == "" || *segmentsDir == "" {
+		return fmt.Errorf("usage: zenglow restore -src-dir DIR -segments-dir DIR [-database-url URL]")
+	}
+
+	manifest, err := backup.LoadManifest(filepath.Join(*srcDir, "manifest.json"))
+	if err != nil {
+		return err
+	}
+	if err := backup.RestoreSegments(manifest, filepath.Join(*srcDir, "segments"), *segmentsDir); err != nil {
+		return err
+	}
+	fmt.Printf("restored %d segment(s) to %s\n", len(manifest.Segments), *segmentsDir)
+
+	if *databaseURL != "" {
+		eventsFile := filepath.Join(*srcDir, "events.csv")
+		if err := backup.ImportEvents(context.Background(), *databaseURL, *table, eventsFile); err != nil {
+			return err
+		}
+		fmt.Printf("imported %s from %s\n", *table, eventsFile)
+	}
+	return nil
+}
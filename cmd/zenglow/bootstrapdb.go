@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/cgb808/ZenGlowNext/pkg/pgbootstrap"
+)
+
+func runBootstrapDB(args []string) error {
+	fs := flag.NewFlagSet("bootstrap-db", flag.ContinueOnError)
+	databaseURL := fs.String("database-url", "", "Postgres connection string")
+	model := fs.String("model", "text-embedding-3-small", "embedding model the vector column is sized for")
+	table := fs.String("table", "events", "events table name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *databaseURL == "" {
+		return fmt.Errorf("usage: zenglow bootstrap-db -database-url postgres://... [-model NAME] [-table NAME]")
+	}
+
+	report, err := pgbootstrap.Bootstrap(context.Background(), pgbootstrap.Options{
+		DatabaseURL: *databaseURL,
+		Model:       *model,
+		Table:       *table,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("pgvector available: %v\n", report.PgvectorAvailable)
+	fmt.Printf("vector dimensions:  %d\n", report.Dimensions)
+	fmt.Printf("table created:      %v\n", report.TableCreated)
+	fmt.Printf("HNSW index created: %v\n", report.IndexCreated)
+	return nil
+}
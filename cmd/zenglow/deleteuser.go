@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cgb808/ZenGlowNext/pkg/gdpr"
+	"github.com/cgb808/ZenGlowNext/pkg/keys"
+)
+
+// runDeleteUserData implements "zenglow delete-user-data", the
+// GDPR-style erasure workflow: it deletes a subject's rows from
+// Postgres, tombstones their frames out of rotated log segments,
+// invalidates any cached query results keyed by subject, and prints a
+// signed gdpr.Report as evidence the deletion ran.
+func runDeleteUserData(args []string) error {
+	fs := flag.NewFlagSet("delete-user-data", flag.ContinueOnError)
+	subject := fs.String("subject", "", "subject (authenticated caller identity) to delete (required)")
+	databaseURL := fs.String("database-url", "", "Postgres connection string; omit to skip row deletion")
+	table := fs.String("table", "events", "events table name")
+	segmentDir := fs.String("segment-dir", "", "rotated log segment directory; omit to skip tombstoning")
+	cacheAddr := fs.String("cache-addr", "", "Redis address fronting cached query results; omit to skip cache invalidation")
+	cacheKeyPatterns := fs.String("cache-key-patterns", "", "comma-separated fmt patterns with one %s for the subject, e.g. topk:%s,canonical:%s")
+	signingKeyFile := fs.String("signing-key-file", "", "pkg/keys FileSource JSON file to sign the report with; omit to leave it unsigned")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *subject == "" {
+		return fmt.Errorf("zenglow delete-user-data: -subject is required")
+	}
+
+	workflow := &gdpr.Workflow{
+		DatabaseURL: *databaseURL,
+		Table:       *table,
+		SegmentDir:  *segmentDir,
+		CacheAddr:   *cacheAddr,
+	}
+	if *cacheKeyPatterns != "" {
+		workflow.CacheKeyPatterns = strings.Split(*cacheKeyPatterns, ",")
+	}
+	if *signingKeyFile != "" {
+		src, err := keys.LoadFileSource(*signingKeyFile)
+		if err != nil {
+			return fmt.Errorf("zenglow delete-user-data: %w", err)
+		}
+		workflow.Keys = src
+	}
+
+	report, err := workflow.DeleteUserData(context.Background(), *subject)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/cgb808/ZenGlowNext/pkg/bus"
+	"github.com/cgb808/ZenGlowNext/pkg/dlq"
+)
+
+func runDLQ(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: zenglow dlq <list|inspect|requeue|delete> [flags]")
+	}
+
+	action, rest := args[0], args[1:]
+	fs := flag.NewFlagSet("dlq "+action, flag.ContinueOnError)
+	backend := fs.String("backend", "redis", "store backend: redis or postgres")
+	addr := fs.String("addr", "localhost:6379", "redis address, used when -backend=redis")
+	databaseURL := fs.String("database-url", "", "Postgres connection string, used when -backend=postgres")
+	table := fs.String("table", "", "Postgres table name, used when -backend=postgres")
+	queue := fs.String("queue", "", "dead-letter queue name")
+	id := fs.String("id", "", "entry id")
+	limit := fs.Int("limit", 50, "max entries to list")
+	republishBus := fs.String("republish-bus", "", "if set, republish the requeued entry's payload onto this bus backend instead of just removing it from the dead-letter queue: redis-streams, nats, or kafka")
+	republishAddr := fs.String("republish-addr", "localhost:6379", "broker address for -republish-bus=redis-streams or nats")
+	republishBrokers := fs.String("republish-brokers", "localhost:9092", "comma-separated broker list for -republish-bus=kafka")
+	republishTopic := fs.String("republish-topic", "", "topic or queue name to republish onto, used with -republish-bus (defaults to -queue)")
+	if err := fs.Parse(rest); err != nil {
+		return err
+	}
+	if *queue == "" {
+		return fmt.Errorf("zenglow dlq %s: -queue is required", action)
+	}
+
+	store, err := dlqStore(*backend, *addr, *databaseURL, *table)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch action {
+	case "list":
+		entries, err := store.List(ctx, *queue, *limit)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			fmt.Printf("%s\tattempts=%d\tlast_failed=%s\treason=%s\n", e.ID, e.Attempts, e.LastFailedAt.Format("2006-01-02T15:04:05Z07:00"), e.Reason)
+		}
+		return nil
+
+	case "inspect":
+		if *id == "" {
+			return fmt.Errorf("zenglow dlq inspect: -id is required")
+		}
+		e, err := store.Get(ctx, *queue, *id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("id: %s\nqueue: %s\nattempts: %d\nreason: %s\nfirst_failed_at: %s\nlast_failed_at: %s\npayload: %s\n",
+			e.ID, e.Queue, e.Attempts, e.Reason, e.FirstFailedAt, e.LastFailedAt, e.Payload)
+		return nil
+
+	case "requeue":
+		if *id == "" {
+			return fmt.Errorf("zenglow dlq requeue: -id is required")
+		}
+		e, err := store.Requeue(ctx, *queue, *id)
+		if err != nil {
+			return err
+		}
+		if *republishBus == "" {
+			fmt.Printf("requeued %s: %d byte(s) of payload removed from the dead-letter queue\n", e.ID, len(e.Payload))
+			return nil
+		}
+
+		publisher, err := busPublisher(*republishBus, *republishAddr, *republishBrokers)
+		if err != nil {
+			return err
+		}
+		topic := *republishTopic
+		if topic == "" {
+			topic = *queue
+		}
+		if err := publisher.Publish(ctx, topic, e.Payload); err != nil {
+			return fmt.Errorf("zenglow dlq requeue: republishing %s onto %s: %w", e.ID, topic, err)
+		}
+		fmt.Printf("requeued %s: %d byte(s) of payload republished onto %s via -republish-bus=%s\n", e.ID, len(e.Payload), topic, *republishBus)
+		return nil
+
+	case "delete":
+		if *id == "" {
+			return fmt.Errorf("zenglow dlq delete: -id is required")
+		}
+		if err := store.Delete(ctx, *queue, *id); err != nil {
+			return err
+		}
+		fmt.Printf("deleted %s\n", *id)
+		return nil
+
+	default:
+		return fmt.Errorf("zenglow dlq: unknown action %q", action)
+	}
+}
+
+func dlqStore(backend, addr, databaseURL, table string) (dlq.Store, error) {
+	switch backend {
+	case "redis":
+		return dlq.RedisStore{Addr: addr}, nil
+	case "postgres":
+		if databaseURL == "" {
+			return nil, fmt.Errorf("zenglow dlq: -database-url is required for -backend=postgres")
+		}
+		return dlq.PostgresStore{DatabaseURL: databaseURL, Table: table}, nil
+	default:
+		return nil, fmt.Errorf("zenglow dlq: unknown backend %q", backend)
+	}
+}
+
+func busPublisher(backend, addr, brokers string) (bus.Publisher, error) {
+	switch backend {
+	case "redis-streams":
+		return &bus.RedisStreams{Addr: addr}, nil
+	case "nats":
+		return &bus.NATS{Addr: addr}, nil
+	case "kafka":
+		return &bus.Kafka{Brokers: brokers}, nil
+	default:
+		return nil, fmt.Errorf("zenglow dlq: unknown -republish-bus %q", backend)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cgb808/ZenGlowNext/pkg/backup"
+	"github.com/cgb808/ZenGlowNext/pkg/pgbootstrap"
+)
+
+// runDR implements "zenglow dr", the disaster-recovery snapshot tool for
+// the vector index: it exports or restores the events table in
+// checksummed, resumable chunks small enough to bound memory, unlike
+// zenglow backup/restore's single-file events.csv dump.
+func runDR(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: zenglow dr <export|restore> [flags]")
+	}
+
+	action, rest := args[0], args[1:]
+	fs := flag.NewFlagSet("dr "+action, flag.ContinueOnError)
+	databaseURL := fs.String("database-url", "", "Postgres connection string (required)")
+	table := fs.String("table", "events", "events table name")
+	storeBackend := fs.String("store", "local", "object store backend: local or s3")
+	dir := fs.String("dir", "", "local snapshot directory, used when -store=local")
+	bucket := fs.String("bucket", "", "S3 bucket, used when -store=s3")
+	prefix := fs.String("prefix", "", "S3 key prefix, used when -store=s3")
+	chunkRows := fs.Int("chunk-rows", 50000, "rows per chunk, used when -store=export")
+	rebuildIndex := fs.Bool("rebuild-index", true, "rebuild the HNSW index after a restore")
+	if err := fs.Parse(rest); err != nil {
+		return err
+	}
+	if *databaseURL == "" {
+		return fmt.Errorf("zenglow dr %s: -database-url is required", action)
+	}
+
+	store, err := drObjectStore(*storeBackend, *dir, *bucket, *prefix)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch action {
+	case "export":
+		manifestPath, err := drManifestPath(*storeBackend, *dir)
+		if err != nil {
+			return err
+		}
+		manifest, err := backup.ExportEventsChunked(ctx, *databaseURL, *table, *chunkRows, store, manifestPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("exported %s in %d chunk(s) to -store=%s\n", *table, len(manifest.Chunks), *storeBackend)
+		return nil
+
+	case "restore":
+		manifestPath, err := drManifestPath(*storeBackend, *dir)
+		if err != nil {
+			return err
+		}
+		manifest, err := backup.LoadChunkManifest(manifestPath)
+		if err != nil {
+			return err
+		}
+		if len(manifest.Chunks) == 0 {
+			return fmt.Errorf("zenglow dr restore: no manifest found at %s", manifestPath)
+		}
+
+		statePath := manifestPath + ".restore-state"
+		if err := backup.ImportEventsChunked(ctx, *databaseURL, *table, manifest, store, statePath); err != nil {
+			return err
+		}
+		fmt.Printf("restored %d chunk(s) into %s\n", len(manifest.Chunks), *table)
+
+		if *rebuildIndex {
+			if err := pgbootstrap.RebuildIndex(ctx, *databaseURL, *table); err != nil {
+				return err
+			}
+			fmt.Printf("rebuilt HNSW index on %s\n", *table)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("zenglow dr: unknown action %q", action)
+	}
+}
+
+func drObjectStore(backend, dir, bucket, prefix string) (backup.ObjectStore, error) {
+	switch backend {
+	case "local":
+		if dir == "" {
+			return nil, fmt.Errorf("zenglow dr: -dir is required for -store=local")
+		}
+		return backup.LocalObjectStore{Dir: dir}, nil
+	case "s3":
+		if bucket == "" {
+			return nil, fmt.Errorf("zenglow dr: -bucket is required for -store=s3")
+		}
+		return backup.S3ObjectStore{Bucket: bucket, Prefix: prefix}, nil
+	default:
+		return nil, fmt.Errorf("zenglow dr: unknown -store %q", backend)
+	}
+}
+
+// drManifestPath returns where the chunk manifest lives. For -store=s3
+// it's kept alongside the snapshot's working files in -dir, since the
+// manifest itself is small enough not to need object storage's
+// durability and a caller resuming an export needs to read it locally
+// before it knows what's already been uploaded.
+func drManifestPath(backend, dir string) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("zenglow dr: -dir is required to track the snapshot manifest, even with -store=s3")
+	}
+	return filepath.Join(dir, "manifest.json"), nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/backup"
+	"github.com/cgb808/ZenGlowNext/pkg/logservice"
+)
+
+// runLogRetention implements "zenglow logretention", a single pass of
+// pkg/logservice.RunRetention meant to be invoked on a schedule (cron, a
+// systemd timer), the same division of labor as "zenglow querystats
+// rollup"/"prune".
+func runLogRetention(args []string) error {
+	fs := flag.NewFlagSet("logretention", flag.ContinueOnError)
+	dir := fs.String("dir", "", "logservice segment directory, or comma-separated list of them if LOG_DIR is sharded (see logservice.SplitDirs); one subdirectory per session under each (required)")
+	maxBytesPerSession := fs.Int64("max-bytes-per-session", 0, "expire a session's oldest segments once it exceeds this many bytes; 0 disables")
+	maxAge := fs.Duration("max-age", 0, "expire segments older than this; 0 disables")
+	compactBelowBytes := fs.Int64("compact-below-bytes", 0, "merge segments smaller than this together per session; 0 disables compaction")
+	archiveBackend := fs.String("archive", "", "archive expiring segments here before deleting them: \"\" (delete only), local, s3, or gcs")
+	archiveDir := fs.String("archive-dir", "", "local archive directory, used when -archive=local")
+	archiveBucket := fs.String("archive-bucket", "", "bucket, used when -archive=s3 or -archive=gcs")
+	archivePrefix := fs.String("archive-prefix", "", "key prefix, used when -archive=s3 or -archive=gcs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return fmt.Errorf("zenglow logretention: -dir is required")
+	}
+
+	archive, err := logRetentionArchive(*archiveBackend, *archiveDir, *archiveBucket, *archivePrefix)
+	if err != nil {
+		return err
+	}
+
+	// RunRetention's doc comment makes the same point RecoverOrphanedSegments's
+	// does: it has no notion of sharding any more than it does tenants,
+	// so a sharded LOG_DIR is run once per directory here and the
+	// reports summed.
+	dirs := logservice.SplitDirs(*dir)
+	var sessions, deleted, compacted int
+	for _, d := range dirs {
+		report, err := logservice.RunRetention(context.Background(), logservice.RetentionConfig{
+			Dir:                d,
+			MaxBytesPerSession: *maxBytesPerSession,
+			MaxAge:             *maxAge,
+			CompactBelowBytes:  *compactBelowBytes,
+			Archive:            archive,
+		})
+		if err != nil {
+			return fmt.Errorf("zenglow logretention: %s: %w", d, err)
+		}
+		sessions += report.Sessions
+		deleted += len(report.Deleted)
+		compacted += report.Compacted
+	}
+
+	fmt.Printf("scanned %d session(s) across %d directory(s): deleted %d segment(s), compacted %d segment(s) in %s\n",
+		sessions, len(dirs), deleted, compacted, time.Now().Format(time.RFC3339))
+	return nil
+}
+
+func logRetentionArchive(backend, dir, bucket, prefix string) (backup.ObjectStore, error) {
+	switch backend {
+	case "":
+		return nil, nil
+	case "local":
+		if dir == "" {
+			return nil, fmt.Errorf("zenglow logretention: -archive-dir is required for -archive=local")
+		}
+		return backup.LocalObjectStore{Dir: dir}, nil
+	case "s3":
+		if bucket == "" {
+			return nil, fmt.Errorf("zenglow logretention: -archive-bucket is required for -archive=s3")
+		}
+		return backup.S3ObjectStore{Bucket: bucket, Prefix: prefix}, nil
+	case "gcs":
+		if bucket == "" {
+			return nil, fmt.Errorf("zenglow logretention: -archive-bucket is required for -archive=gcs")
+		}
+		return backup.GCSObjectStore{Bucket: bucket, Prefix: prefix}, nil
+	default:
+		return nil, fmt.Errorf("zenglow logretention: unknown -archive %q", backend)
+	}
+}
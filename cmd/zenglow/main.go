@@ -0,0 +1,81 @@
+// Command zenglow is a single entry point for the service binaries that
+// used to ship as separate images (hrw, grpc-router, topk-client, and the
+// services still being added under pkg/). Each subcommand shares the same
+// config loading, telemetry registry, and auth verifier setup instead of
+// repeating that wiring per binary.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type subcommand struct {
+	summary string
+	run     func(args []string) error
+}
+
+var subcommands = map[string]subcommand{
+	"route":            {"route a single key with pkg/hashring", runRoute},
+	"topk":             {"query the top-k vector search service", runTopK},
+	"serve-router":     {"run the hashring-backed backend router", notStandalone("serve-router", "cmd/grpc-router, or \"zenglow route\" for a one-off lookup")},
+	"serve-logservice": {"run the log ingestion, write, and admin HTTP service", runServeLogService},
+	"serve-ingester":   {"run the frame ingester service", notStandalone("serve-ingester", "")},
+	"notify":           {"consume operator notification jobs off the async job queue", runNotify},
+	"bootstrap-db":     {"prepare a Postgres database for pgvector event storage", runBootstrapDB},
+	"backup":           {"snapshot log segments and export events", runBackup},
+	"restore":          {"restore log segments and import events from a backup", runRestore},
+	"dlq":              {"list, inspect, requeue, or delete dead-lettered messages", runDLQ},
+	"dr":               {"export or restore the events table as chunked, checksummed snapshots for disaster recovery", runDR},
+	"usage":            {"roll up per-tenant usage counters into Postgres, or export them as CSV", runUsage},
+	"delete-user-data": {"run the GDPR-style DeleteUserData workflow across Postgres, log segments, and caches", runDeleteUserData},
+	"saga":             {"inspect or resume a pkg/saga workflow run, e.g. the ingest persist/embed/index/notify saga", runSaga},
+	"querystats":       {"roll up or prune pkg/querystats TopK/router query sample rollups in Postgres", runQueryStats},
+	"logretention":     {"expire, archive, and compact pkg/logservice session segments past their retention bounds", runLogRetention},
+	"logverify":        {"scan pkg/logservice session segments for checksum and framing corruption", runLogVerify},
+	"logrecover":       {"finalize pkg/logservice \".logtmp\" segments orphaned by a crash mid-rotation and republish them", runLogRecover},
+	"logexport":        {"export pkg/logservice segment files to JSONL or CSV for ad-hoc debugging", runLogExport},
+}
+
+// notStandalone returns a subcommand body for a service this binary
+// doesn't run itself. alternative, if non-empty, names a command that
+// actually serves that role today; if empty, no such command exists yet
+// and the error says so instead of pointing at one that isn't there.
+func notStandalone(name, alternative string) func(args []string) error {
+	if alternative == "" {
+		return func(args []string) error {
+			return fmt.Errorf("zenglow %s: not implemented anywhere in this repo yet, standalone or otherwise", name)
+		}
+	}
+	return func(args []string) error {
+		return fmt.Errorf("zenglow %s: not wired up in this binary; use %s instead", name, alternative)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	cmd, ok := subcommands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "zenglow: unknown subcommand %q\n", name)
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "zenglow:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: zenglow <subcommand> [flags]")
+	fmt.Fprintln(os.Stderr, "subcommands:")
+	for name, cmd := range subcommands {
+		fmt.Fprintf(os.Stderr, "  %-18s %s\n", name, cmd.summary)
+	}
+}
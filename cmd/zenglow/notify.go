@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/cgb808/ZenGlowNext/pkg/bus"
+)
+
+// notification is the job payload operators and services publish onto
+// the notify topic, e.g. "replication lag exceeded threshold" or
+// "restore finished".
+type notification struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func runNotify(args []string) error {
+	fs := flag.NewFlagSet("notify", flag.ContinueOnError)
+	backend := fs.String("backend", "redis-streams", "async job queue backend: redis-streams, nats, or kafka")
+	addr := fs.String("addr", "localhost:6379", "broker address, used for -backend=redis-streams or nats")
+	brokers := fs.String("brokers", "localhost:9092", "comma-separated broker list, used for -backend=kafka")
+	topic := fs.String("topic", "zenglow.notify", "topic operator notifications are published to")
+	webhookURL := fs.String("webhook-url", "", "if set, POST each notification here as JSON in addition to printing it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	subscriber, err := notifySubscriber(*backend, *addr, *brokers)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	for {
+		msg, err := subscriber.Next(ctx, *topic)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintln(os.Stderr, "zenglow notify:", err)
+			continue
+		}
+
+		var n notification
+		if err := json.Unmarshal(msg.Payload, &n); err != nil {
+			fmt.Fprintf(os.Stderr, "zenglow notify: malformed job %s: %v\n", msg.ID, err)
+			continue
+		}
+		fmt.Printf("[%s] %s\n", n.Severity, n.Message)
+
+		if *webhookURL != "" {
+			if err := postWebhook(ctx, *webhookURL, n); err != nil {
+				fmt.Fprintf(os.Stderr, "zenglow notify: webhook: %v\n", err)
+			}
+		}
+	}
+}
+
+func postWebhook(ctx context.Context, url string, n notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func notifySubscriber(backend, addr, brokers string) (bus.Subscriber, error) {
+	switch backend {
+	case "redis-streams":
+		return &bus.RedisStreams{Addr: addr}, nil
+	case "nats":
+		return &bus.NATS{Addr: addr}, nil
+	case "kafka":
+		return &bus.Kafka{Brokers: brokers}, nil
+	default:
+		return nil, fmt.Errorf("zenglow notify: unknown -backend %q", backend)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/querystats"
+)
+
+func runQueryStats(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: zenglow querystats <rollup|prune> [flags]")
+	}
+
+	action, rest := args[0], args[1:]
+	fs := flag.NewFlagSet("querystats "+action, flag.ContinueOnError)
+	redisAddr := fs.String("redis-addr", "localhost:6379", "Redis address samples are recorded to")
+	databaseURL := fs.String("database-url", "", "Postgres connection string")
+	table := fs.String("table", "", "rollup table name (default query_stats_rollup)")
+	retention := fs.Duration("retention", 30*24*time.Hour, "how long to keep rolled-up rows, used with the prune action")
+	if err := fs.Parse(rest); err != nil {
+		return err
+	}
+	if *databaseURL == "" {
+		return fmt.Errorf("zenglow querystats %s: -database-url is required", action)
+	}
+
+	sink := querystats.PostgresSink{DatabaseURL: *databaseURL, Table: *table}
+
+	switch action {
+	case "rollup":
+		rollup := &querystats.Rollup{
+			Recorder: &querystats.RedisRecorder{Addr: *redisAddr},
+			Sink:     sink,
+		}
+		n, err := rollup.Run(context.Background())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("rolled up %d period(s) into Postgres\n", n)
+		return nil
+
+	case "prune":
+		cutoff := time.Now().Add(-*retention)
+		if err := sink.DeleteOlderThan(context.Background(), cutoff); err != nil {
+			return err
+		}
+		fmt.Printf("pruned rows older than %s\n", cutoff.Format(time.RFC3339))
+		return nil
+
+	default:
+		return fmt.Errorf("zenglow querystats: unknown action %q", action)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+)
+
+func runRoute(args []string) error {
+	fs := flag.NewFlagSet("route", flag.ContinueOnError)
+	key := fs.String("key", "", "key to route")
+	nodesArg := fs.String("nodes", "", "comma-separated id[:weight[:zone]] node list")
+	k := fs.Int("k", 1, "number of nodes to return")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *key == "" || *nodesArg == "" {
+		return fmt.Errorf("usage: zenglow route -key KEY -nodes id1,id2,... [-k N]")
+	}
+
+	var nodes []hashring.Node
+	for _, spec := range strings.Split(*nodesArg, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		nodes = append(nodes, hashring.Node{ID: spec, Weight: 1})
+	}
+
+	for _, id := range hashring.TopK(*key, nodes, *k) {
+		fmt.Println(id)
+	}
+	return nil
+}
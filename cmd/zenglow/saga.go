@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/cgb808/ZenGlowNext/pkg/client"
+	"github.com/cgb808/ZenGlowNext/pkg/saga"
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+func runSaga(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: zenglow saga <status|list|resume> [flags]")
+	}
+
+	action, rest := args[0], args[1:]
+	fs := flag.NewFlagSet("saga "+action, flag.ContinueOnError)
+	databaseURL := fs.String("database-url", "", "Postgres connection string backing the saga store (required)")
+	table := fs.String("table", "", "Postgres table name")
+	status := fs.String("status", "", "run status to list, used with the list action: running, completed, compensating, compensated, or failed")
+	id := fs.String("id", "", "run id, used with the status and resume actions")
+	ingesterAddr := fs.String("ingester-addr", "", "IngestionService address, used by the ingest Definition when resuming")
+	topkAddr := fs.String("topk-addr", "", "top-k search service address, used by the ingest Definition when resuming")
+	notifyBus := fs.String("notify-bus", "redis-streams", "bus backend the ingest Definition publishes completion notifications on: redis-streams, nats, or kafka")
+	notifyAddr := fs.String("notify-addr", "localhost:6379", "broker address for -notify-bus=redis-streams or nats")
+	notifyBrokers := fs.String("notify-brokers", "localhost:9092", "comma-separated broker list for -notify-bus=kafka")
+	notifyTopic := fs.String("notify-topic", "ingest.completed", "topic the ingest Definition publishes completion notifications on")
+	if err := fs.Parse(rest); err != nil {
+		return err
+	}
+	if *databaseURL == "" {
+		return fmt.Errorf("zenglow saga %s: -database-url is required", action)
+	}
+
+	store := saga.PostgresStore{DatabaseURL: *databaseURL, Table: *table}
+	ctx := context.Background()
+
+	switch action {
+	case "status":
+		if *id == "" {
+			return fmt.Errorf("zenglow saga status: -id is required")
+		}
+		run, err := store.Get(ctx, *id)
+		if err != nil {
+			return err
+		}
+		printRun(run)
+		return nil
+
+	case "list":
+		if *status == "" {
+			return fmt.Errorf("zenglow saga list: -status is required")
+		}
+		runs, err := store.ListByStatus(ctx, saga.Status(*status))
+		if err != nil {
+			return err
+		}
+		for _, run := range runs {
+			printRun(run)
+		}
+		return nil
+
+	case "resume":
+		if *id == "" {
+			return fmt.Errorf("zenglow saga resume: -id is required")
+		}
+		publisher, err := busPublisher(*notifyBus, *notifyAddr, *notifyBrokers)
+		if err != nil {
+			return err
+		}
+		def := saga.NewIngestDefinition(
+			client.NewIngestionClient(*ingesterAddr),
+			topkclient.New(*topkAddr),
+			publisher,
+			*notifyTopic,
+		)
+		coordinator := saga.NewCoordinator(store, def)
+		run, err := coordinator.Resume(ctx, *id)
+		printRun(run)
+		return err
+
+	default:
+		return fmt.Errorf("zenglow saga: unknown action %q", action)
+	}
+}
+
+func printRun(run saga.Run) {
+	fmt.Printf("id=%s definition=%s status=%s step=%d error=%q updated_at=%s\n",
+		run.ID, run.Definition, run.Status, run.StepIndex, run.Error, run.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"))
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/authn"
+	"github.com/cgb808/ZenGlowNext/pkg/authz"
+	"github.com/cgb808/ZenGlowNext/pkg/config"
+	"github.com/cgb808/ZenGlowNext/pkg/gateway"
+	"github.com/cgb808/ZenGlowNext/pkg/logservice"
+	"github.com/cgb808/ZenGlowNext/pkg/secrets"
+	"github.com/cgb808/ZenGlowNext/pkg/shutdown"
+	"github.com/cgb808/ZenGlowNext/pkg/spiffe"
+	"github.com/cgb808/ZenGlowNext/pkg/telemetry"
+)
+
+// runServeLogService implements "zenglow serve-logservice", the first
+// binary that actually mounts pkg/logservice's three http.Handlers
+// (Handler for reads, WriteStreamHandler for writes, AdminHandler for
+// runtime config and session control) behind a listener, rather than
+// leaving them to be wired up by some future caller that never
+// materialized. WriterConfig is loaded through pkg/config (its `env`
+// tags were already written for this; nothing before this command ever
+// called config.Load), with -dir overriding LOG_DIR the way a flag
+// outranks an env var everywhere else in this binary.
+//
+// Every route runs behind an authn.Verifier and an authz.Policy: set
+// exactly one of -static-tokens, -jwt-hmac-secret/-jwt-jwks-url, or
+// -spiffe-cert/-spiffe-key/-spiffe-bundle to choose how callers
+// authenticate (bearer token, JWT, or mTLS via pkg/spiffe), and
+// -route-roles to say which roles may reach which route group. There is
+// no flagless default that serves AdminHandler unauthenticated; a
+// deployment that wants that has to say so explicitly by... there being
+// no such flag, so it can't.
+func runServeLogService(args []string) error {
+	fs := flag.NewFlagSet("serve-logservice", flag.ContinueOnError)
+	addr := fs.String("addr", ":8443", "listen address")
+	dir := fs.String("dir", "", "logservice segment directory, overrides LOG_DIR; see logservice.SplitDirs for sharding (required, here or via LOG_DIR)")
+	envFile := fs.String("env-file", "", "lower-priority .env-style file for WriterConfig's env-tagged fields, passed to config.Load")
+	configFile := fs.String("config-file", "", "YAML-subset file hot-reloaded into the running config on SIGHUP or change (see logservice.WatchConfigFile); disabled if empty")
+	keyPrefix := fs.String("key-prefix", "", "pkg/secrets name prefix for resolving segment encryption keys, e.g. logservice/segment-key/; required if -encrypt-key-id is set or any existing segment under -dir is encrypted")
+	encryptKeyID := fs.String("encrypt-key-id", "", "key ID (resolved via -key-prefix) to encrypt newly-rotated segments under; leave empty to write unencrypted segments")
+	ackEvery := fs.Int("ack-every", 1, "WriteStreamHandler: send a write ack after this many frames")
+
+	staticTokens := fs.String("static-tokens", "", "comma-separated token=subject:role entries for authn.StaticVerifier, e.g. svc-a-tok=svc-a:writer")
+	jwtHMACSecret := fs.String("jwt-hmac-secret", "", "shared secret verifying HS256 JWTs via authn.JWTVerifier")
+	jwtJWKSURL := fs.String("jwt-jwks-url", "", "JWKS URL verifying RS256 JWTs via authn.JWTVerifier/JWKSSource")
+	jwtRoleClaim := fs.String("jwt-role-claim", "", "JWT claim mapped to Identity.Role (default \"role\")")
+
+	spiffeCert := fs.String("spiffe-cert", "", "SVID certificate file; set with -spiffe-key and -spiffe-bundle to authenticate callers by mTLS instead of a bearer token")
+	spiffeKey := fs.String("spiffe-key", "", "SVID private key file")
+	spiffeBundle := fs.String("spiffe-bundle", "", "trust bundle file used to verify client certificates")
+	spiffeRoles := fs.String("spiffe-roles", "", "comma-separated spiffe-id=role entries mapping a client certificate's SPIFFE ID to a Role; an ID missing here authenticates with an empty Role")
+
+	routeRoles := fs.String("route-roles", "admin=admin|write|read,writer=write|read,reader=read", "comma-separated role=method1|method2 authz.Policy entries; methods are \"admin\", \"write\", and \"read\" (see logserviceRouteMethod)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// -dir outranks LOG_DIR the way a flag outranks env everywhere else
+	// in this binary; since Dir is `env:"LOG_DIR,required"`, the only
+	// way to give it that priority without config.Load failing first on
+	// a LOG_DIR that was never going to be used is to set it before
+	// calling Load, rather than overwriting cfg.Dir after.
+	if *dir != "" {
+		os.Setenv("LOG_DIR", *dir)
+	}
+	var cfg logservice.WriterConfig
+	if err := config.Load(&cfg, *envFile); err != nil {
+		return fmt.Errorf("zenglow serve-logservice: %w", err)
+	}
+
+	reg := telemetry.NewRegistry()
+	metrics := logservice.NewMetrics(reg)
+	cfg.Metrics = metrics
+
+	var keys logservice.KeyProvider
+	if *keyPrefix != "" {
+		provider := logservice.SecretsKeyProvider{Resolver: secrets.New(secrets.EnvSource{}, 0), Prefix: *keyPrefix}
+		keys = provider
+		if *encryptKeyID != "" {
+			key, err := provider.Key(context.Background(), *encryptKeyID)
+			if err != nil {
+				return fmt.Errorf("zenglow serve-logservice: resolving -encrypt-key-id %s: %w", *encryptKeyID, err)
+			}
+			cfg.EncryptKeyID = *encryptKeyID
+			cfg.EncryptKey = key
+		}
+	} else if *encryptKeyID != "" {
+		return fmt.Errorf("zenglow serve-logservice: -encrypt-key-id requires -key-prefix")
+	}
+
+	store := logservice.NewConfigStore(cfg)
+	live := logservice.NewWriterRegistry()
+	live.SetMetrics(reg)
+
+	policy, err := logserviceRoutePolicy(*routeRoles)
+	if err != nil {
+		return fmt.Errorf("zenglow serve-logservice: %w", err)
+	}
+
+	var tlsConfig *tls.Config
+	var authenticate func(http.Handler) http.Handler
+	switch {
+	case *spiffeCert != "" || *spiffeKey != "" || *spiffeBundle != "":
+		source, err := spiffe.NewFileSource(*spiffeCert, *spiffeKey, *spiffeBundle)
+		if err != nil {
+			return fmt.Errorf("zenglow serve-logservice: loading SPIFFE identity: %w", err)
+		}
+		tlsConfig = spiffe.ServerConfig(source)
+		go source.Watch(context.Background(), 30*time.Second, func() {
+			log.Printf("zenglow serve-logservice: rotated SPIFFE SVID/trust bundle")
+		}, func(err error) {
+			log.Printf("zenglow serve-logservice: SPIFFE rotation check failed: %v", err)
+		})
+
+		roles, err := logserviceParsePairs(*spiffeRoles)
+		if err != nil {
+			return fmt.Errorf("zenglow serve-logservice: -spiffe-roles: %w", err)
+		}
+		authenticate = func(next http.Handler) http.Handler {
+			return authn.MiddlewareMTLS(authn.RoleResolver(roles), next)
+		}
+	case *staticTokens != "":
+		verifier, err := logserviceStaticVerifier(*staticTokens)
+		if err != nil {
+			return fmt.Errorf("zenglow serve-logservice: -static-tokens: %w", err)
+		}
+		authenticate = func(next http.Handler) http.Handler { return authn.Middleware(verifier, next) }
+	case *jwtHMACSecret != "" || *jwtJWKSURL != "":
+		v := &authn.JWTVerifier{RoleClaim: *jwtRoleClaim}
+		if *jwtHMACSecret != "" {
+			v.HMACSecret = []byte(*jwtHMACSecret)
+		}
+		if *jwtJWKSURL != "" {
+			v.Keys = authn.NewJWKSSource(*jwtJWKSURL)
+		}
+		authenticate = func(next http.Handler) http.Handler { return authn.Middleware(v, next) }
+	default:
+		return fmt.Errorf("zenglow serve-logservice: no auth configured; set -static-tokens, -jwt-hmac-secret/-jwt-jwks-url, or -spiffe-cert/-spiffe-key/-spiffe-bundle")
+	}
+
+	// protect applies the one auth layer gateway.Mux's doc comment
+	// describes: authn first (authenticate, whichever of the three
+	// mechanisms -static-tokens/-jwt-.../-spiffe-... selected), then
+	// authz on top, identically for every route registered below —
+	// Mux.Register itself has no opinion on auth, so it's applied here
+	// before a Route ever reaches it, the same way cmd/grpc-router wraps
+	// its /route handler before Register.
+	protect := func(routeMethod string, next http.Handler) http.Handler {
+		guarded := authz.Middleware(policy, logserviceRouteMethod(routeMethod), next)
+		return telemetry.Middleware(reg, "logservice_"+routeMethod, authenticate(guarded))
+	}
+
+	gw := gateway.New()
+	gw.Register(gateway.Route{Service: "LogService", Method: "*", Path: "/admin/", Summary: "Runtime config and session admin", Handler: protect("admin", logservice.AdminHandler(store, live))})
+	gw.Register(gateway.Route{Service: "LogService", Method: "*", Path: "/stream", Summary: "WebSocket frame write stream", Handler: protect("write", logservice.WriteStreamHandler(store, *ackEvery, live))})
+	gw.Register(gateway.Route{Service: "LogService", Method: "POST", Path: "/write", Summary: "Unary batch frame write", Handler: protect("write", logservice.WriteBatchHandler(store, live))})
+	gw.Register(gateway.Route{Service: "LogService", Method: "GET", Path: "/", Summary: "Segment/frame read, replay, and tail", Handler: protect("read", logservice.Handler(cfg.Dir, keys, live, metrics))})
+
+	mux := http.NewServeMux()
+	mux.Handle("/", gw)
+	mux.Handle("/metrics", telemetry.Handler(reg))
+
+	if *configFile != "" {
+		go logservice.WatchConfigFile(context.Background(), *configFile, store, nil)
+	}
+
+	server := &http.Server{Addr: *addr, Handler: mux, TLSConfig: tlsConfig}
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			log.Printf("zenglow serve-logservice: listening on %s (mTLS), dir=%s", *addr, cfg.Dir)
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			log.Printf("zenglow serve-logservice: listening on %s, dir=%s", *addr, cfg.Dir)
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	runner := shutdown.New()
+	runner.Register(shutdown.Component{
+		Name:    "logservice writers",
+		Timeout: 10 * time.Second,
+		Stop:    live.Drain,
+	})
+	runner.Register(shutdown.Component{
+		Name:    "http server",
+		Timeout: 15 * time.Second,
+		Stop:    server.Shutdown,
+	})
+	runner.Wait(func(component string, err error) {
+		log.Printf("zenglow serve-logservice: %s: %v", component, err)
+	})
+	return nil
+}
+
+// logserviceRouteMethod returns the methodFor function authz.Middleware
+// needs for routeMethod ("admin", "write", or "read"): every request
+// behind that route group resolves to the same method name regardless
+// of its specific path, since -route-roles grants access per group
+// rather than per individual admin sub-route.
+func logserviceRouteMethod(routeMethod string) func(*http.Request) string {
+	return func(*http.Request) string { return routeMethod }
+}
+
+// logserviceRoutePolicy parses spec ("role=method1|method2,...", as
+// documented on -route-roles) into an authz.Policy.
+func logserviceRoutePolicy(spec string) (authz.Policy, error) {
+	allowed := make(map[string][]string)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		role, methods, ok := strings.Cut(entry, "=")
+		if !ok || role == "" || methods == "" {
+			return nil, fmt.Errorf("malformed entry %q, want role=method1|method2", entry)
+		}
+		allowed[role] = strings.Split(methods, "|")
+	}
+	return authz.NewPolicy(allowed), nil
+}
+
+// logserviceStaticVerifier parses spec ("token=subject:role,...", as
+// documented on -static-tokens) into an authn.StaticVerifier.
+func logserviceStaticVerifier(spec string) (authn.StaticVerifier, error) {
+	v := make(authn.StaticVerifier)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		token, identity, ok := strings.Cut(entry, "=")
+		if !ok || token == "" {
+			return nil, fmt.Errorf("malformed entry %q, want token=subject:role", entry)
+		}
+		subject, role, _ := strings.Cut(identity, ":")
+		if subject == "" {
+			return nil, fmt.Errorf("malformed entry %q, want token=subject:role", entry)
+		}
+		v[token] = authn.Identity{Subject: subject, Role: role}
+	}
+	return v, nil
+}
+
+// logserviceParsePairs parses spec ("key=value,...", as documented on
+// -spiffe-roles) into a plain map, shared by any flag with that shape.
+func logserviceParsePairs(spec string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("malformed entry %q, want key=value", entry)
+		}
+		out[key] = value
+	}
+	return out, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+func runTopK(args []string) error {
+	fs := flag.NewFlagSet("topk", flag.ContinueOnError)
+	addr := fs.String("addr", "http://localhost:8092", "topk service address")
+	query := fs.String("query", "", "query text")
+	k := fs.Int("k", 10, "number of results")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *query == "" {
+		return fmt.Errorf("usage: zenglow topk -query TEXT [-k N] [-addr URL]")
+	}
+
+	client := topkclient.New(*addr)
+	result, err := client.Search(context.Background(), topkclient.Query{Text: *query, K: *k})
+	if err != nil {
+		return err
+	}
+	for _, m := range result.Matches {
+		fmt.Fprintf(os.Stdout, "%-40s %.4f\n", m.ID, m.Score)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cgb808/ZenGlowNext/pkg/metering"
+)
+
+func runUsage(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: zenglow usage <rollup|export> [flags]")
+	}
+
+	action, rest := args[0], args[1:]
+	fs := flag.NewFlagSet("usage "+action, flag.ContinueOnError)
+	redisAddr := fs.String("redis-addr", "localhost:6379", "Redis address counters are recorded to")
+	databaseURL := fs.String("database-url", "", "Postgres connection string")
+	table := fs.String("table", "", "usage table name (default tenant_usage)")
+	if err := fs.Parse(rest); err != nil {
+		return err
+	}
+	if *databaseURL == "" {
+		return fmt.Errorf("zenglow usage %s: -database-url is required", action)
+	}
+
+	switch action {
+	case "rollup":
+		rollup := &metering.Rollup{
+			Recorder: &metering.RedisRecorder{Addr: *redisAddr},
+			Sink:     metering.PostgresSink{DatabaseURL: *databaseURL, Table: *table},
+		}
+		n, err := rollup.Run(context.Background())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("rolled up %d period(s) into Postgres\n", n)
+		return nil
+
+	case "export":
+		return exportUsageCSV(*databaseURL, *table)
+
+	default:
+		return fmt.Errorf("zenglow usage: unknown action %q", action)
+	}
+}
+
+// exportUsageCSV writes the usage table to stdout as CSV via psql's
+// \copy, the same approach pkg/backup's ExportEvents uses.
+func exportUsageCSV(databaseURL, table string) error {
+	if table == "" {
+		table = "tenant_usage"
+	}
+	sql := fmt.Sprintf(`\copy (SELECT tenant, period, metric, count FROM %s ORDER BY period, tenant, metric) TO STDOUT WITH (FORMAT csv, HEADER true)`, table)
+	cmd := exec.Command("psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", sql)
+	cmd.Stdout = os.Stdout
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("zenglow usage export: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Package admin is the optional, localhost-only diagnostics listener
+// every long-running service in this repo can start alongside its real
+// listener: pprof profiles, a goroutine dump, and build info, so
+// diagnosing something like the logservice writer goroutine growth
+// doesn't require killing the process first.
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	rpprof "runtime/pprof"
+)
+
+// Handler returns an http.Handler exposing pprof under /debug/pprof/,
+// a goroutine dump at /debug/goroutines, and build info at
+// /debug/buildinfo. It is meant to be served on a separate,
+// localhost-only listener (see ListenAndServe) rather than mounted
+// alongside a service's public routes.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/goroutines", writeGoroutines)
+	mux.HandleFunc("/debug/buildinfo", writeBuildInfo)
+	return mux
+}
+
+func writeGoroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "goroutines: %d\n\n", runtime.NumGoroutine())
+	rpprof.Lookup("goroutine").WriteTo(w, 1)
+}
+
+func writeBuildInfo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		fmt.Fprintln(w, "build info unavailable")
+		return
+	}
+	fmt.Fprintln(w, info.String())
+}
+
+// ListenAndServe starts the admin handler on addr, which should be a
+// loopback address (e.g. "127.0.0.1:6060") since the admin endpoints
+// have no auth of their own. It blocks until the listener fails and is
+// meant to be run in its own goroutine, the same way callers already run
+// telemetry.Handler.
+func ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, Handler())
+}
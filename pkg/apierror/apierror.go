@@ -0,0 +1,125 @@
+// Package apierror is the shared domain error taxonomy for
+// CanonicalService, IngestionService, and RouterService, so a caller
+// anywhere in the client SDK sees the same small set of error kinds and
+// can make one retriable-vs-not decision instead of string-matching
+// service-specific error text. Since those services talk HTTP/JSON
+// rather than real gRPC (see pkg/gateway), Code mirrors the canonical
+// gRPC status codes this repo would map onto if it ever grew a real
+// gRPC transport, and ToHTTPStatus renders the HTTP equivalent for the
+// gateway to use today.
+package apierror
+
+import "fmt"
+
+// Code is a domain error kind, named and numbered to match
+// google.golang.org/grpc/codes so adopting a real gRPC transport later
+// is a transport-layer change, not a call-site rewrite.
+type Code int
+
+const (
+	// CodeNotFound means the requested resource does not exist.
+	CodeNotFound Code = 5
+	// CodeConflict means the request contradicts existing state, e.g. a
+	// write with a stale version (gRPC's AlreadyExists/Aborted family).
+	CodeConflict Code = 6
+	// CodeQuotaExceeded means the caller has exhausted a rate or
+	// resource limit (gRPC's ResourceExhausted).
+	CodeQuotaExceeded Code = 8
+	// CodeDependencyUnavailable means a downstream dependency is
+	// temporarily unreachable (gRPC's Unavailable).
+	CodeDependencyUnavailable Code = 14
+	// CodeUnauthenticated means the request carried no valid credentials
+	// (gRPC's Unauthenticated).
+	CodeUnauthenticated Code = 16
+)
+
+// Retryable reports whether a client should retry an error with this
+// code. Quota and dependency errors are retryable (after backoff);
+// not-found and conflict are not, since retrying without changing the
+// request will fail again.
+func (c Code) Retryable() bool {
+	switch c {
+	case CodeQuotaExceeded, CodeDependencyUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// ToHTTPStatus maps Code to the HTTP status pkg/gateway handlers should
+// return.
+func (c Code) ToHTTPStatus() int {
+	switch c {
+	case CodeNotFound:
+		return 404
+	case CodeConflict:
+		return 409
+	case CodeQuotaExceeded:
+		return 429
+	case CodeDependencyUnavailable:
+		return 503
+	case CodeUnauthenticated:
+		return 401
+	default:
+		return 500
+	}
+}
+
+// Unauthenticated builds a CodeUnauthenticated error.
+func Unauthenticated(message string) *Error {
+	return &Error{Code: CodeUnauthenticated, Message: message}
+}
+
+// Error is a domain error carrying a Code, a human-readable Message, and
+// optional Details for structured context (e.g. the resource ID for a
+// NotFound).
+type Error struct {
+	Code    Code
+	Message string
+	Details map[string]string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NotFound builds a CodeNotFound error.
+func NotFound(resource, id string) *Error {
+	return &Error{
+		Code:    CodeNotFound,
+		Message: fmt.Sprintf("%s %s not found", resource, id),
+		Details: map[string]string{"resource": resource, "id": id},
+	}
+}
+
+// Conflict builds a CodeConflict error.
+func Conflict(message string) *Error {
+	return &Error{Code: CodeConflict, Message: message}
+}
+
+// QuotaExceeded builds a CodeQuotaExceeded error.
+func QuotaExceeded(message string) *Error {
+	return &Error{Code: CodeQuotaExceeded, Message: message}
+}
+
+// DependencyUnavailable builds a CodeDependencyUnavailable error for a
+// failed downstream dependency.
+func DependencyUnavailable(dependency string, cause error) *Error {
+	e := &Error{
+		Code:    CodeDependencyUnavailable,
+		Message: fmt.Sprintf("%s unavailable", dependency),
+		Details: map[string]string{"dependency": dependency},
+	}
+	if cause != nil {
+		e.Details["cause"] = cause.Error()
+	}
+	return e
+}
+
+// As reports whether err is an *Error, returning it if so. It exists
+// alongside the standard errors.As so callers that only need the common
+// case can avoid importing errors just for this.
+func As(err error) (*Error, bool) {
+	e, ok := err.(*Error)
+	return e, ok
+}
@@ -0,0 +1,43 @@
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// wireError is the JSON shape an Error is sent and received as over the
+// gateway's HTTP/JSON transport.
+type wireError struct {
+	Code    Code              `json:"code"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// WriteHTTP writes err to w as a JSON body with err.Code's HTTP status.
+// If err is not an *Error it falls back to 500 with a generic message,
+// so handlers can call this unconditionally.
+func WriteHTTP(w http.ResponseWriter, err error) {
+	e, ok := As(err)
+	if !ok {
+		e = &Error{Code: 13, Message: "internal error"} // mirrors gRPC's Internal
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Code.ToHTTPStatus())
+	json.NewEncoder(w).Encode(wireError{Code: e.Code, Message: e.Message, Details: e.Details})
+}
+
+// FromHTTPResponse decodes a gateway error response back into an *Error.
+// ok is false if resp's status is a success (2xx) or its body isn't a
+// wireError, in which case the caller should treat resp normally.
+func FromHTTPResponse(resp *http.Response) (err *Error, ok bool) {
+	if resp.StatusCode < 400 {
+		return nil, false
+	}
+
+	var wire wireError
+	if decodeErr := json.NewDecoder(resp.Body).Decode(&wire); decodeErr != nil || wire.Message == "" {
+		return &Error{Code: Code(2), Message: resp.Status}, true // mirrors gRPC's Unknown
+	}
+	return &Error{Code: wire.Code, Message: wire.Message, Details: wire.Details}, true
+}
@@ -0,0 +1,47 @@
+// Package apiversion lets clients and servers roll out independently by
+// tagging every request with an API version and letting the server
+// up-convert older request shapes (e.g. a v1 TopK request with no
+// filters field) to the current one before handling them, rather than
+// requiring lockstep deploys.
+package apiversion
+
+import (
+	"context"
+	"net/http"
+)
+
+// Header carries the caller's API version. A missing header is treated
+// as Oldest.
+const Header = "X-API-Version"
+
+// Oldest is the version assumed for requests with no Header set, i.e.
+// clients that predate this convention entirely.
+const Oldest = "v1"
+
+type versionKey struct{}
+
+// FromContext returns the request's API version, defaulting to Oldest.
+func FromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(versionKey{}).(string); ok && v != "" {
+		return v
+	}
+	return Oldest
+}
+
+// Converter up-converts a request body from an older version to the
+// current shape. Registered per method via a Middleware's Converters map.
+type Converter func(body []byte, fromVersion string) ([]byte, error)
+
+// Middleware wraps next, attaching the caller's API version to the
+// request context so handlers (or a per-method Converter run earlier in
+// the chain) can branch on it.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := r.Header.Get(Header)
+		if version == "" {
+			version = Oldest
+		}
+		ctx := context.WithValue(r.Context(), versionKey{}, version)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
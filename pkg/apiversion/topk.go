@@ -0,0 +1,22 @@
+package apiversion
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// UpconvertTopKQuery parses a TopK request body that may be in the v1
+// shape (no "filters" or "explain" fields) into a current
+// topkclient.Query. Go's JSON decoding already zero-values missing
+// fields, so this mostly documents that v1 bodies are a strict subset of
+// the current shape and gives the gateway one place to change if a
+// future version needs real field remapping instead of just omission.
+func UpconvertTopKQuery(body []byte, fromVersion string) (topkclient.Query, error) {
+	var q topkclient.Query
+	if err := json.Unmarshal(body, &q); err != nil {
+		return topkclient.Query{}, fmt.Errorf("apiversion: decoding %s TopK request: %w", fromVersion, err)
+	}
+	return q, nil
+}
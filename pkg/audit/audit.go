@@ -0,0 +1,112 @@
+// Package audit records who called which mutating RPC, with what key
+// fields, and what happened, for household data compliance. Records are
+// buffered and flushed to Postgres in batches rather than one write per
+// request, since a synchronous write per mutation would tie request
+// latency to the audit table's write throughput.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Record is one audited call.
+type Record struct {
+	Time    time.Time
+	Method  string
+	Subject string // caller identity, from authn.Identity.Subject
+	Fields  map[string]interface{}
+	Outcome string // "ok" or an error message
+}
+
+// Sink persists a batch of Records. PostgresSink is the production
+// implementation; tests can supply their own.
+type Sink interface {
+	Write(ctx context.Context, records []Record) error
+}
+
+// Logger buffers Records and flushes them to a Sink on an interval or
+// when the buffer fills, whichever comes first.
+type Logger struct {
+	sink          Sink
+	flushInterval time.Duration
+	batchSize     int
+
+	mu      sync.Mutex
+	pending []Record
+
+	flushNow     chan struct{}
+	OnFlushError func(error)
+}
+
+// New returns a Logger that flushes to sink every flushInterval or after
+// batchSize records, whichever happens first. Call Run in its own
+// goroutine to start the flush loop.
+func New(sink Sink, flushInterval time.Duration, batchSize int) *Logger {
+	return &Logger{
+		sink:          sink,
+		flushInterval: flushInterval,
+		batchSize:     batchSize,
+		flushNow:      make(chan struct{}, 1),
+	}
+}
+
+// Record appends r to the pending batch, triggering an immediate flush
+// if the batch is now full.
+func (l *Logger) Record(r Record) {
+	l.mu.Lock()
+	l.pending = append(l.pending, r)
+	full := len(l.pending) >= l.batchSize
+	l.mu.Unlock()
+
+	if full {
+		select {
+		case l.flushNow <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run flushes pending records to the Sink on l.flushInterval, or
+// immediately when Record fills a batch, until ctx is done. Any
+// remaining records are flushed once more before Run returns.
+func (l *Logger) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.flush(context.Background())
+			return
+		case <-ticker.C:
+			l.flush(ctx)
+		case <-l.flushNow:
+			l.flush(ctx)
+		}
+	}
+}
+
+func (l *Logger) flush(ctx context.Context) {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+	if err := l.sink.Write(ctx, batch); err != nil && l.OnFlushError != nil {
+		l.OnFlushError(err)
+	}
+}
+
+func encodeFields(fields map[string]interface{}) (string, error) {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/authn"
+)
+
+// Middleware wraps next, recording a Record with l for every request:
+// the method (r.URL.Path), the caller's identity from authn.FromContext
+// if present, and the outcome inferred from the response status code.
+// Intended for the mutating RPCs (ingester writes, canonical writes,
+// router admin APIs) that need an audit trail, not read-only endpoints.
+func Middleware(l *Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		subject := ""
+		if id, ok := authn.FromContext(r.Context()); ok {
+			subject = id.Subject
+		}
+
+		outcome := "ok"
+		if rec.status >= 400 {
+			outcome = fmt.Sprintf("http %d", rec.status)
+		}
+
+		l.Record(Record{
+			Time:    time.Now(),
+			Method:  r.URL.Path,
+			Subject: subject,
+			Fields:  map[string]interface{}{"query": r.URL.RawQuery},
+			Outcome: outcome,
+		})
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// PostgresSink writes batches of Records to Table via psql, the same
+// approach pkg/pgbootstrap uses to avoid depending on a Go Postgres
+// driver this repo can't fetch offline.
+type PostgresSink struct {
+	DatabaseURL string
+	Table       string
+}
+
+// Write implements Sink by inserting every record in one multi-row
+// INSERT statement.
+func (s PostgresSink) Write(ctx context.Context, records []Record) error {
+	table := s.Table
+	if table == "" {
+		table = "audit_log"
+	}
+
+	var rows []string
+	for _, r := range records {
+		fields, err := encodeFields(r.Fields)
+		if err != nil {
+			return fmt.Errorf("audit: encoding fields: %w", err)
+		}
+		rows = append(rows, fmt.Sprintf(
+			"(%s, %s, %s, %s::jsonb, %s)",
+			quoteLiteral(r.Time.Format("2006-01-02T15:04:05.000Z07:00")),
+			quoteLiteral(r.Method),
+			quoteLiteral(r.Subject),
+			quoteLiteral(fields),
+			quoteLiteral(r.Outcome),
+		))
+	}
+
+	sql := fmt.Sprintf(
+		"INSERT INTO %s (occurred_at, method, subject, fields, outcome) VALUES %s;",
+		table, strings.Join(rows, ", "),
+	)
+
+	cmd := exec.CommandContext(ctx, "psql", s.DatabaseURL, "-v", "ON_ERROR_STOP=1", "-c", sql)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("audit: inserting %d record(s): %w: %s", len(records), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// quoteLiteral escapes s as a single-quoted SQL string literal.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
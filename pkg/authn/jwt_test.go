@@ -0,0 +1,98 @@
+package authn
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func signHS256(t *testing.T, secret []byte, signingInput string) string {
+	t.Helper()
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func hs256Token(t *testing.T, secret []byte, claims map[string]interface{}) string {
+	t.Helper()
+	header := encodeSegment(t, map[string]string{"alg": "HS256", "typ": "JWT"})
+	payload := encodeSegment(t, claims)
+	signingInput := header + "." + payload
+	return signingInput + "." + signHS256(t, secret, signingInput)
+}
+
+func TestJWTVerifierHS256RoundTrip(t *testing.T) {
+	secret := []byte("test-secret")
+	v := &JWTVerifier{HMACSecret: secret}
+
+	token := hs256Token(t, secret, map[string]interface{}{"sub": "svc-a", "role": "admin"})
+	id, err := v.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if id.Subject != "svc-a" || id.Role != "admin" {
+		t.Fatalf("Identity = %+v, want {svc-a admin}", id)
+	}
+}
+
+func TestJWTVerifierRejectsTamperedSignature(t *testing.T) {
+	secret := []byte("test-secret")
+	v := &JWTVerifier{HMACSecret: secret}
+
+	token := hs256Token(t, secret, map[string]interface{}{"sub": "svc-a"})
+	if _, err := v.Verify(context.Background(), token+"x"); err == nil {
+		t.Fatal("Verify accepted a tampered token")
+	}
+}
+
+func TestJWTVerifierRejectsWrongSecret(t *testing.T) {
+	v := &JWTVerifier{HMACSecret: []byte("correct-secret")}
+	token := hs256Token(t, []byte("wrong-secret"), map[string]interface{}{"sub": "svc-a"})
+	if _, err := v.Verify(context.Background(), token); err == nil {
+		t.Fatal("Verify accepted a token signed with a different secret")
+	}
+}
+
+func TestJWTVerifierRejectsExpiredToken(t *testing.T) {
+	secret := []byte("test-secret")
+	v := &JWTVerifier{HMACSecret: secret}
+
+	past := time.Now().Add(-time.Hour).Unix()
+	token := hs256Token(t, secret, map[string]interface{}{"sub": "svc-a", "exp": past})
+	if _, err := v.Verify(context.Background(), token); err == nil {
+		t.Fatal("Verify accepted an expired token")
+	}
+}
+
+func TestJWTVerifierRejectsMissingSubject(t *testing.T) {
+	secret := []byte("test-secret")
+	v := &JWTVerifier{HMACSecret: secret}
+
+	token := hs256Token(t, secret, map[string]interface{}{"role": "admin"})
+	if _, err := v.Verify(context.Background(), token); err == nil {
+		t.Fatal("Verify accepted a token with no sub claim")
+	}
+}
+
+func TestJWTVerifierRejectsUnsupportedAlgorithm(t *testing.T) {
+	v := &JWTVerifier{HMACSecret: []byte("secret")}
+	header := encodeSegment(t, map[string]string{"alg": "none", "typ": "JWT"})
+	payload := encodeSegment(t, map[string]interface{}{"sub": "svc-a"})
+	token := header + "." + payload + "."
+	if _, err := v.Verify(context.Background(), token); err == nil {
+		t.Fatal("Verify accepted alg=none")
+	}
+}
@@ -0,0 +1,46 @@
+package authn
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cgb808/ZenGlowNext/pkg/spiffe"
+)
+
+// RoleResolver maps an authenticated caller's identity to a Role for
+// authz.Policy to check. The string key is a caller-defined identity
+// (e.g. a SPIFFE ID's string form); callers not present get "".
+type RoleResolver map[string]string
+
+// MiddlewareMTLS wraps next, rejecting any request that didn't arrive
+// over a connection whose peer presented a client certificate with a
+// spiffe:// URI SAN (see pkg/spiffe.LeafID), and otherwise attaching an
+// Identity to the request context the same way Middleware does for a
+// bearer token โ€” so authz.Middleware can sit behind either one without
+// caring which authenticated the caller. roles maps the certificate's
+// SPIFFE ID to a Role; an ID missing from roles is authenticated with
+// an empty Role, which authz.Policy treats as "calls nothing" unless a
+// policy explicitly allows it.
+//
+// This intentionally doesn't re-implement certificate verification:
+// pkg/spiffe.ServerConfig's tls.Config already requires and verifies
+// the peer chain during the handshake, so by the time a handler sees
+// the request, r.TLS.PeerCertificates[0] is already a verified leaf.
+func MiddlewareMTLS(roles RoleResolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			http.Error(w, "authn: no client certificate presented", http.StatusUnauthorized)
+			return
+		}
+
+		id, err := spiffe.LeafID(r.TLS.PeerCertificates[0])
+		if err != nil {
+			http.Error(w, "authn: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		identity := Identity{Subject: id.String(), Role: roles[id.String()]}
+		ctx := context.WithValue(r.Context(), identityKey{}, identity)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,67 @@
+// Package authz enforces per-method role-based authorization on top of
+// pkg/authn's identity verification: a Policy maps each role to the
+// method names it may call, and Middleware rejects any request whose
+// caller's role isn't allowed to call the method it's hitting.
+package authz
+
+import (
+	"net/http"
+
+	"github.com/cgb808/ZenGlowNext/pkg/authn"
+)
+
+// Policy maps a role name to the set of full method names (as used by
+// Middleware's methodFor function, typically the request path) that role
+// may call.
+type Policy map[string]map[string]bool
+
+// NewPolicy builds a Policy from a role -> method list map, the form
+// it's most convenient to write in config.
+func NewPolicy(allowed map[string][]string) Policy {
+	p := make(Policy, len(allowed))
+	for role, methods := range allowed {
+		set := make(map[string]bool, len(methods))
+		for _, m := range methods {
+			set[m] = true
+		}
+		p[role] = set
+	}
+	return p
+}
+
+// Allows reports whether role may call method.
+func (p Policy) Allows(role, method string) bool {
+	methods, ok := p[role]
+	if !ok {
+		return false
+	}
+	return methods[method]
+}
+
+// Middleware wraps next, requiring that an authn.Identity is already
+// attached to the request context (by authn.Middleware, which must run
+// first) and that p allows that identity's Role to call methodFor(r).
+func Middleware(p Policy, methodFor func(*http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := authn.FromContext(r.Context())
+		if !ok {
+			http.Error(w, "authz: no authenticated identity on request", http.StatusUnauthorized)
+			return
+		}
+
+		method := methodFor(r)
+		if !p.Allows(id.Role, method) {
+			http.Error(w, "authz: role "+id.Role+" may not call "+method, http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// MethodFromPath is the common methodFor implementation: the method is
+// simply the request's URL path, matching how routes are registered with
+// pkg/gateway.
+func MethodFromPath(r *http.Request) string {
+	return r.URL.Path
+}
@@ -0,0 +1,37 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ExportEvents dumps table from databaseURL into destFile using psql's
+// \copy, the same psql-shelling approach pkg/pgbootstrap and pkg/audit
+// use elsewhere in this repo.
+func ExportEvents(ctx context.Context, databaseURL, table, destFile string) error {
+	sql := fmt.Sprintf(`\copy %s TO '%s' WITH (FORMAT csv, HEADER true)`, table, destFile)
+	return runPsql(ctx, databaseURL, sql)
+}
+
+// ImportEvents loads srcFile into table in databaseURL via psql's \copy.
+func ImportEvents(ctx context.Context, databaseURL, table, srcFile string) error {
+	if _, err := os.Stat(srcFile); err != nil {
+		return fmt.Errorf("backup: events export %s: %w", srcFile, err)
+	}
+	sql := fmt.Sprintf(`\copy %s FROM '%s' WITH (FORMAT csv, HEADER true)`, table, srcFile)
+	return runPsql(ctx, databaseURL, sql)
+}
+
+func runPsql(ctx context.Context, databaseURL, sql string) error {
+	cmd := exec.CommandContext(ctx, "psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", sql)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
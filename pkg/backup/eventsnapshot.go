@@ -0,0 +1,311 @@
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ChunkEntry describes one page of the events table captured by a
+// chunked snapshot.
+type ChunkEntry struct {
+	Name   string // object name, e.g. "events-000001.csv.gz"
+	Rows   int
+	SHA256 string
+}
+
+// ChunkManifest records the chunks a vector-index snapshot has produced
+// so far, in order, so ExportEventsChunked can resume after a crash
+// instead of re-exporting chunks an ObjectStore already has durably, and
+// so ImportEventsChunked can verify each one before loading it.
+//
+// Chunks are gzip-compressed CSV, the same \copy format pkg/backup's
+// ExportEvents already uses, rather than real Parquet: a columnar
+// encoder is out of scope for a repo with no way to vendor one, and CSV
+// chunks checksummed and paged the way this file does gives the same
+// bounded-memory, resumable, verifiable properties the DR use case
+// actually needs.
+type ChunkManifest struct {
+	CreatedAt time.Time
+	Table     string
+	ChunkRows int
+	Chunks    []ChunkEntry
+}
+
+// LoadChunkManifest reads a ChunkManifest from path. A missing file
+// returns an empty ChunkManifest rather than an error, since a fresh
+// export has nothing to resume from.
+func LoadChunkManifest(path string) (ChunkManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ChunkManifest{}, nil
+		}
+		return ChunkManifest{}, err
+	}
+	var m ChunkManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return ChunkManifest{}, err
+	}
+	return m, nil
+}
+
+// Save writes m to path as indented JSON.
+func (m ChunkManifest) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (m ChunkManifest) rowsExported() int {
+	total := 0
+	for _, c := range m.Chunks {
+		total += c.Rows
+	}
+	return total
+}
+
+// ExportEventsChunked exports table from databaseURL in chunkRows-row
+// pages ordered by id, uploading each page to store as gzip-compressed
+// CSV and recording it in manifestPath before moving on to the next
+// page. Export memory is bounded by one page rather than the whole
+// table, and a crash mid-export can be resumed by calling
+// ExportEventsChunked again with the same manifestPath: already-recorded
+// chunks are not re-exported.
+func ExportEventsChunked(ctx context.Context, databaseURL, table string, chunkRows int, store ObjectStore, manifestPath string) (ChunkManifest, error) {
+	manifest, err := LoadChunkManifest(manifestPath)
+	if err != nil {
+		return ChunkManifest{}, err
+	}
+	manifest.Table = table
+	manifest.ChunkRows = chunkRows
+	if manifest.CreatedAt.IsZero() {
+		manifest.CreatedAt = time.Now()
+	}
+
+	offset := manifest.rowsExported()
+	for index := len(manifest.Chunks) + 1; ; index++ {
+		tmp, err := os.CreateTemp("", "events-chunk-*.csv")
+		if err != nil {
+			return manifest, err
+		}
+		tmpPath := tmp.Name()
+		tmp.Close()
+		defer os.Remove(tmpPath)
+
+		rows, err := exportPage(ctx, databaseURL, table, offset, chunkRows, tmpPath)
+		if err != nil {
+			return manifest, err
+		}
+		if rows == 0 {
+			break
+		}
+
+		name := fmt.Sprintf("%s-%06d.csv.gz", table, index)
+		sum, compressedPath, err := compressAndChecksum(tmpPath)
+		if err != nil {
+			return manifest, err
+		}
+		defer os.Remove(compressedPath)
+
+		if err := store.Put(ctx, name, compressedPath); err != nil {
+			return manifest, fmt.Errorf("backup: uploading %s: %w", name, err)
+		}
+
+		manifest.Chunks = append(manifest.Chunks, ChunkEntry{Name: name, Rows: rows, SHA256: sum})
+		if err := manifest.Save(manifestPath); err != nil {
+			return manifest, fmt.Errorf("backup: saving manifest after %s: %w", name, err)
+		}
+
+		offset += rows
+		if rows < chunkRows {
+			break // short page: that was the last one
+		}
+	}
+	return manifest, nil
+}
+
+// ImportEventsChunked loads every chunk in manifest into table in
+// databaseURL, verifying each chunk's checksum before loading it and
+// recording progress in statePath so a retry after a crash skips chunks
+// already imported.
+func ImportEventsChunked(ctx context.Context, databaseURL, table string, manifest ChunkManifest, store ObjectStore, statePath string) error {
+	applied, err := loadRestoreState(statePath)
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range manifest.Chunks {
+		if applied[chunk.Name] {
+			continue
+		}
+
+		compressedPath, err := os.CreateTemp("", "events-chunk-*.csv.gz")
+		if err != nil {
+			return err
+		}
+		compressedPath.Close()
+		defer os.Remove(compressedPath.Name())
+
+		if err := store.Get(ctx, chunk.Name, compressedPath.Name()); err != nil {
+			return fmt.Errorf("backup: downloading %s: %w", chunk.Name, err)
+		}
+		sum, err := sha256File(compressedPath.Name())
+		if err != nil {
+			return err
+		}
+		if sum != chunk.SHA256 {
+			return fmt.Errorf("backup: %s failed checksum verification: got %s, want %s", chunk.Name, sum, chunk.SHA256)
+		}
+
+		csvPath, err := decompress(compressedPath.Name())
+		if err != nil {
+			return err
+		}
+		defer os.Remove(csvPath)
+
+		if err := ImportEvents(ctx, databaseURL, table, csvPath); err != nil {
+			return fmt.Errorf("backup: importing %s: %w", chunk.Name, err)
+		}
+
+		applied[chunk.Name] = true
+		if err := saveRestoreState(statePath, applied); err != nil {
+			return fmt.Errorf("backup: saving restore state after %s: %w", chunk.Name, err)
+		}
+	}
+	return nil
+}
+
+// exportPage dumps rows [offset, offset+limit) of table, ordered by id,
+// to destFile as CSV, returning how many rows it wrote.
+func exportPage(ctx context.Context, databaseURL, table string, offset, limit int, destFile string) (int, error) {
+	sql := fmt.Sprintf(
+		`\copy (SELECT * FROM %s ORDER BY id OFFSET %d LIMIT %d) TO '%s' WITH (FORMAT csv, HEADER true)`,
+		table, offset, limit, destFile,
+	)
+	if err := runPsql(ctx, databaseURL, sql); err != nil {
+		return 0, err
+	}
+	return countCSVRows(destFile)
+}
+
+func countCSVRows(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	lines := strings.Count(string(data), "\n")
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		lines++
+	}
+	if lines == 0 {
+		return 0, nil
+	}
+	return lines - 1, nil // minus the header row
+}
+
+// compressAndChecksum gzip-compresses srcPath to a new temp file and
+// returns its SHA-256, computed over the compressed bytes so a later Get
+// can verify the object store delivered the same bytes Put shipped.
+func compressAndChecksum(srcPath string) (sha256Hex, compressedPath string, err error) {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return "", "", err
+	}
+	defer src.Close()
+
+	out, err := os.CreateTemp("", "events-chunk-*.csv.gz")
+	if err != nil {
+		return "", "", err
+	}
+	defer out.Close()
+
+	hash := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(out, hash))
+	if _, err := io.Copy(gz, src); err != nil {
+		return "", "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), out.Name(), nil
+}
+
+func decompress(srcPath string) (string, error) {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	gz, err := gzip.NewReader(src)
+	if err != nil {
+		return "", err
+	}
+	defer gz.Close()
+
+	out, err := os.CreateTemp("", "events-chunk-*.csv")
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, gz); err != nil {
+		return "", err
+	}
+	return out.Name(), nil
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func loadRestoreState(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]bool{}, nil
+		}
+		return nil, err
+	}
+	var applied []string
+	if err := json.Unmarshal(data, &applied); err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(applied))
+	for _, name := range applied {
+		set[name] = true
+	}
+	return set, nil
+}
+
+func saveRestoreState(path string, applied map[string]bool) error {
+	names := make([]string, 0, len(applied))
+	for name := range applied {
+		names = append(names, name)
+	}
+	data, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
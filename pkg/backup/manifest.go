@@ -0,0 +1,66 @@
+// Package backup implements zenglow's backup/restore tooling: snapshotting
+// a log segment directory with a manifest of what was copied, and
+// exporting/importing the Postgres events table alongside it, with an
+// incremental mode that skips segments already captured by an earlier
+// manifest.
+package backup
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// SegmentEntry describes one segment file captured by a snapshot.
+type SegmentEntry struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Manifest records what a snapshot captured, so a later incremental
+// backup can diff against it.
+type Manifest struct {
+	CreatedAt time.Time
+	Segments  []SegmentEntry
+}
+
+// LoadManifest reads a Manifest from path. A missing file returns an
+// empty Manifest rather than an error, since the first backup has
+// nothing to diff against.
+func LoadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Manifest{}, nil
+		}
+		return Manifest{}, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Manifest{}, err
+	}
+	return m, nil
+}
+
+// Save writes m to path as indented JSON.
+func (m Manifest) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Known reports whether name was already captured at the given size by a
+// previous manifest, which is all an incremental backup needs to decide
+// whether to skip re-copying a segment (rotated segments are immutable
+// once closed, so same name + same size means same content).
+func (m Manifest) Known(name string, size int64) bool {
+	for _, e := range m.Segments {
+		if e.Name == name && e.Size == size {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ObjectStore puts and gets named blobs for a chunked snapshot,
+// abstracting over local disk and an S3-compatible bucket the same way
+// pkg/dlq abstracts a Store over Postgres and Redis.
+type ObjectStore interface {
+	Put(ctx context.Context, name, localPath string) error
+	Get(ctx context.Context, name, localPath string) error
+}
+
+// LocalObjectStore stores blobs as files under Dir, for snapshots kept
+// on a local or already-mounted network filesystem.
+type LocalObjectStore struct {
+	Dir string
+}
+
+// Put implements ObjectStore by copying localPath into Dir.
+func (s LocalObjectStore) Put(ctx context.Context, name, localPath string) error {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("backup: creating %s: %w", s.Dir, err)
+	}
+	return copyFile(localPath, filepath.Join(s.Dir, name))
+}
+
+// Get implements ObjectStore by copying name out of Dir.
+func (s LocalObjectStore) Get(ctx context.Context, name, localPath string) error {
+	return copyFile(filepath.Join(s.Dir, name), localPath)
+}
+
+// S3ObjectStore stores blobs under s3://Bucket/Prefix/, shelling out to
+// the aws CLI the same way pkg/dlq's PostgresStore shells out to psql,
+// since this repo has no AWS SDK to vendor.
+type S3ObjectStore struct {
+	Bucket string
+	Prefix string
+}
+
+// URL returns the s3:// URL name resolves to.
+func (s S3ObjectStore) URL(name string) string {
+	key := strings.TrimPrefix(strings.TrimSuffix(s.Prefix, "/")+"/"+name, "/")
+	return fmt.Sprintf("s3://%s/%s", s.Bucket, key)
+}
+
+// Put implements ObjectStore via `aws s3 cp`.
+func (s S3ObjectStore) Put(ctx context.Context, name, localPath string) error {
+	return runAWSCP(ctx, localPath, s.URL(name))
+}
+
+// Get implements ObjectStore via `aws s3 cp`.
+func (s S3ObjectStore) Get(ctx context.Context, name, localPath string) error {
+	return runAWSCP(ctx, s.URL(name), localPath)
+}
+
+func runAWSCP(ctx context.Context, src, dest string) error {
+	cmd := exec.CommandContext(ctx, "aws", "s3", "cp", src, dest)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("backup: aws s3 cp %s %s: %w: %s", src, dest, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// GCSObjectStore stores blobs under gs://Bucket/Prefix/, shelling out to
+// the gsutil CLI for the same reason S3ObjectStore shells out to aws.
+type GCSObjectStore struct {
+	Bucket string
+	Prefix string
+}
+
+// URL returns the gs:// URL name resolves to.
+func (s GCSObjectStore) URL(name string) string {
+	key := strings.TrimPrefix(strings.TrimSuffix(s.Prefix, "/")+"/"+name, "/")
+	return fmt.Sprintf("gs://%s/%s", s.Bucket, key)
+}
+
+// Put implements ObjectStore via `gsutil cp`.
+func (s GCSObjectStore) Put(ctx context.Context, name, localPath string) error {
+	return runGsutilCP(ctx, localPath, s.URL(name))
+}
+
+// Get implements ObjectStore via `gsutil cp`.
+func (s GCSObjectStore) Get(ctx context.Context, name, localPath string) error {
+	return runGsutilCP(ctx, s.URL(name), localPath)
+}
+
+func runGsutilCP(ctx context.Context, src, dest string) error {
+	cmd := exec.CommandContext(ctx, "gsutil", "cp", src, dest)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("backup: gsutil cp %s %s: %w: %s", src, dest, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// URLObjectStore is an ObjectStore that can also report the durable URL
+// a name resolves to, for callers that need to publish a blob's location
+// (e.g. logservice's segment-rotation queue) rather than just move
+// bytes. LocalObjectStore deliberately doesn't implement it: a local
+// path isn't durable off-host, so publishing it as if it were would
+// defeat the point of uploading in the first place.
+type URLObjectStore interface {
+	ObjectStore
+	URL(name string) string
+}
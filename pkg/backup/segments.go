@@ -0,0 +1,80 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SnapshotSegments copies every file in srcDir into destDir, skipping
+// files already present in prevManifest when incremental is true, and
+// returns the Manifest describing what destDir now contains.
+func SnapshotSegments(srcDir, destDir string, prevManifest Manifest, incremental bool) (Manifest, error) {
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return Manifest{}, fmt.Errorf("backup: creating %s: %w", destDir, err)
+	}
+
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("backup: reading %s: %w", srcDir, err)
+	}
+
+	manifest := Manifest{CreatedAt: time.Now()}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return Manifest{}, err
+		}
+
+		if incremental && prevManifest.Known(entry.Name(), info.Size()) {
+			manifest.Segments = append(manifest.Segments, SegmentEntry{
+				Name: entry.Name(), Size: info.Size(), ModTime: info.ModTime(),
+			})
+			continue
+		}
+
+		if err := copyFile(filepath.Join(srcDir, entry.Name()), filepath.Join(destDir, entry.Name())); err != nil {
+			return Manifest{}, fmt.Errorf("backup: copying %s: %w", entry.Name(), err)
+		}
+		manifest.Segments = append(manifest.Segments, SegmentEntry{
+			Name: entry.Name(), Size: info.Size(), ModTime: info.ModTime(),
+		})
+	}
+	return manifest, nil
+}
+
+// RestoreSegments copies every segment listed in manifest from srcDir
+// (a snapshot directory) into destDir (the live segment directory).
+func RestoreSegments(manifest Manifest, srcDir, destDir string) error {
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return fmt.Errorf("backup: creating %s: %w", destDir, err)
+	}
+	for _, e := range manifest.Segments {
+		if err := copyFile(filepath.Join(srcDir, e.Name), filepath.Join(destDir, e.Name)); err != nil {
+			return fmt.Errorf("backup: restoring %s: %w", e.Name, err)
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -0,0 +1,42 @@
+// Package bus is the shared publish/subscribe abstraction for the
+// segment-rotation queue, the dead-letter queue's requeue path, and the
+// operator notification job queue, so a deployment picks its broker
+// (Redis Streams, NATS, or Kafka) without those callers changing. It
+// mirrors pkg/dlq's shape: one interface, several concrete
+// implementations, none pulling in a client library this repo has no
+// way to fetch offline.
+package bus
+
+import "context"
+
+// Message is one message published to or received from a topic.
+type Message struct {
+	ID      string
+	Payload []byte
+}
+
+// Publisher sends messages to a topic.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// Subscriber receives messages from a topic one at a time, starting
+// after whatever position the implementation considers already
+// delivered.
+type Subscriber interface {
+	// Next blocks until a message is available on topic or ctx is done.
+	Next(ctx context.Context, topic string) (Message, error)
+	// Ack marks msg as processed so a restart does not redeliver it.
+	// Implementations without a server-side delivery cursor to advance
+	// (core NATS, Kafka's auto-commit consumer groups) accept it as a
+	// no-op so callers can treat every backend uniformly.
+	Ack(ctx context.Context, topic string, msg Message) error
+}
+
+// Bus is the combination callers that both publish and consume need,
+// e.g. the pipeline coordinator forwarding segments into the ingester
+// queue.
+type Bus interface {
+	Publisher
+	Subscriber
+}
@@ -0,0 +1,299 @@
+package bus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JetStream is a Bus backed by NATS JetStream, reusing the same
+// handshake as NATS (JetStream is a set of subjects layered on core
+// NATS, not a separate wire protocol) but adding the durability NATS's
+// doc comment says core NATS lacks: Publish blocks for the server's
+// per-message ack before returning, and Next/Ack round-trip through a
+// durable pull consumer instead of a fire-and-forget SUB, giving
+// at-least-once delivery across restarts the same way Kafka's
+// consumer groups do for Kafka.
+//
+// Every topic gets its own stream and durable consumer, both named
+// after the topic and created lazily on first use; a stream that
+// already exists (ensureStream's JS API call returning "stream name
+// already in use") is treated as success rather than an error.
+type JetStream struct {
+	Addr string
+
+	mu       sync.Mutex
+	streams  map[string]bool
+	inboxSeq int
+}
+
+// Publish implements Publisher with a synchronous JetStream publish:
+// it sends the message with a reply subject and waits for the
+// server's ack (or error) on it, so a nil return means the message is
+// durably stored, not just written to the socket the way NATS.Publish
+// is.
+func (b *JetStream) Publish(ctx context.Context, topic string, payload []byte) error {
+	if err := b.ensureStream(topic); err != nil {
+		return fmt.Errorf("bus: publishing to %s: %w", topic, err)
+	}
+
+	ack, err := b.request(topic, payload)
+	if err != nil {
+		return fmt.Errorf("bus: publishing to %s: %w", topic, err)
+	}
+	var parsed struct {
+		Error *jsAPIError `json:"error"`
+	}
+	if err := json.Unmarshal(ack, &parsed); err != nil {
+		return fmt.Errorf("bus: publishing to %s: decoding ack: %w", topic, err)
+	}
+	if parsed.Error != nil {
+		return fmt.Errorf("bus: publishing to %s: %s", topic, parsed.Error.Description)
+	}
+	return nil
+}
+
+// Next implements Subscriber by pulling a single message off topic's
+// durable consumer, blocking until one arrives or ctx is done. The
+// returned Message's ID is the consumer-specific ack subject the
+// server attached as this delivery's reply-to, which Ack publishes an
+// acknowledgement to.
+func (b *JetStream) Next(ctx context.Context, topic string) (Message, error) {
+	if err := b.ensureStream(topic); err != nil {
+		return Message{}, fmt.Errorf("bus: reading %s: %w", topic, err)
+	}
+	consumer := consumerName(topic)
+	if err := b.ensureConsumer(topic, consumer); err != nil {
+		return Message{}, fmt.Errorf("bus: reading %s: %w", topic, err)
+	}
+
+	deadline := 30 * time.Second
+	if d, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(d); remaining > 0 && remaining < deadline {
+			deadline = remaining
+		}
+	}
+	pullReq, err := json.Marshal(map[string]int64{
+		"batch":   1,
+		"expires": deadline.Nanoseconds(),
+	})
+	if err != nil {
+		return Message{}, fmt.Errorf("bus: reading %s: %w", topic, err)
+	}
+
+	msg, replyTo, err := b.requestWithReply(
+		fmt.Sprintf("$JS.API.CONSUMER.MSG.NEXT.%s.%s", streamName(topic), consumer),
+		pullReq,
+	)
+	if err != nil {
+		return Message{}, fmt.Errorf("bus: reading %s: %w", topic, err)
+	}
+	return Message{ID: replyTo, Payload: msg}, nil
+}
+
+// Ack implements Subscriber by publishing to msg.ID, the per-delivery
+// ack subject JetStream attached to the message Next returned.
+// Acking after Next's deadline has already redelivered the message is
+// harmless: JetStream accepts a late ack for a delivery it has
+// already redelivered, same as it does for any other at-least-once
+// consumer.
+func (b *JetStream) Ack(ctx context.Context, topic string, msg Message) error {
+	if msg.ID == "" {
+		return nil
+	}
+	conn, _, err := dialNATS(b.Addr)
+	if err != nil {
+		return fmt.Errorf("bus: acking %s: %w", topic, err)
+	}
+	defer conn.Close()
+	if _, err := fmt.Fprintf(conn, "PUB %s %d\r\n+ACK\r\n", msg.ID, len("+ACK")); err != nil {
+		return fmt.Errorf("bus: acking %s: %w", topic, err)
+	}
+	return nil
+}
+
+type jsAPIError struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+// ensureStream creates a stream named after topic bound to topic's
+// subject if one doesn't already exist.
+func (b *JetStream) ensureStream(topic string) error {
+	b.mu.Lock()
+	if b.streams[topic] {
+		b.mu.Unlock()
+		return nil
+	}
+	b.mu.Unlock()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"name":     streamName(topic),
+		"subjects": []string{topic},
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := b.request("$JS.API.STREAM.CREATE."+streamName(topic), body)
+	if err != nil {
+		return err
+	}
+	var parsed struct {
+		Error *jsAPIError `json:"error"`
+	}
+	if err := json.Unmarshal(resp, &parsed); err != nil {
+		return fmt.Errorf("decoding stream create response: %w", err)
+	}
+	if parsed.Error != nil && !strings.Contains(parsed.Error.Description, "already in use") {
+		return fmt.Errorf("creating stream %s: %s", streamName(topic), parsed.Error.Description)
+	}
+
+	b.mu.Lock()
+	if b.streams == nil {
+		b.streams = make(map[string]bool)
+	}
+	b.streams[topic] = true
+	b.mu.Unlock()
+	return nil
+}
+
+// ensureConsumer creates a durable pull consumer on topic's stream if
+// one doesn't already exist.
+func (b *JetStream) ensureConsumer(topic, consumer string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"stream_name": streamName(topic),
+		"config": map[string]interface{}{
+			"durable_name": consumer,
+			"ack_policy":   "explicit",
+		},
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := b.request(fmt.Sprintf("$JS.API.CONSUMER.DURABLE.CREATE.%s.%s", streamName(topic), consumer), body)
+	if err != nil {
+		return err
+	}
+	var parsed struct {
+		Error *jsAPIError `json:"error"`
+	}
+	if err := json.Unmarshal(resp, &parsed); err != nil {
+		return fmt.Errorf("decoding consumer create response: %w", err)
+	}
+	if parsed.Error != nil && !strings.Contains(parsed.Error.Description, "already in use") {
+		return fmt.Errorf("creating consumer %s: %s", consumer, parsed.Error.Description)
+	}
+	return nil
+}
+
+// request sends payload to subject with a fresh inbox as its reply
+// subject and returns the first reply's payload, discarding the
+// reply-to captured alongside it. It is used for JS API calls and for
+// Publish, where the ack itself is the useful half of the reply.
+func (b *JetStream) request(subject string, payload []byte) ([]byte, error) {
+	msg, _, err := b.requestWithReply(subject, payload)
+	return msg, err
+}
+
+// requestWithReply is request, additionally returning the reply
+// frame's own reply-to subject — the JetStream per-delivery ack
+// subject when the reply is a pulled message, empty for plain API
+// replies.
+func (b *JetStream) requestWithReply(subject string, payload []byte) ([]byte, string, error) {
+	conn, r, err := dialNATS(b.Addr)
+	if err != nil {
+		return nil, "", err
+	}
+	defer conn.Close()
+
+	inbox := fmt.Sprintf("_INBOX.%s.%d", streamName(subject), b.nextInboxSeq())
+	if _, err := fmt.Fprintf(conn, "SUB %s 1\r\n", inbox); err != nil {
+		return nil, "", err
+	}
+
+	if _, err := fmt.Fprintf(conn, "PUB %s %s %d\r\n", subject, inbox, len(payload)); err != nil {
+		return nil, "", err
+	}
+	framed := make([]byte, 0, len(payload)+2)
+	framed = append(framed, payload...)
+	framed = append(framed, '\r', '\n')
+	if _, err := conn.Write(framed); err != nil {
+		return nil, "", err
+	}
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			if _, err := conn.Write([]byte("PONG\r\n")); err != nil {
+				return nil, "", err
+			}
+		case strings.HasPrefix(line, "MSG "):
+			return readJetStreamMessage(r, line)
+		}
+	}
+}
+
+// readJetStreamMessage parses a MSG frame's payload and, if present,
+// its own reply-to field (the ack subject for a pulled delivery).
+func readJetStreamMessage(r *bufio.Reader, header string) ([]byte, string, error) {
+	// MSG <subject> <sid> [reply-to] <#bytes>
+	fields := strings.Fields(header)
+	if len(fields) < 4 {
+		return nil, "", fmt.Errorf("malformed MSG frame: %q", header)
+	}
+	n, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return nil, "", fmt.Errorf("malformed MSG frame: %q", header)
+	}
+	var replyTo string
+	if len(fields) == 5 {
+		replyTo = fields[3]
+	}
+
+	buf := make([]byte, n+2) // payload + trailing \r\n
+	if _, err := readFullNATS(r, buf); err != nil {
+		return nil, "", err
+	}
+	return buf[:n], replyTo, nil
+}
+
+func (b *JetStream) nextInboxSeq() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.inboxSeq++
+	return b.inboxSeq
+}
+
+// streamName and consumerName sanitize a subject into a name JetStream
+// accepts (alphanumeric, '-', '_' only; no '.', which JS reserves as
+// the token separator for subjects like its own $JS.API.* subjects).
+func streamName(topic string) string {
+	return sanitizeJSName(topic)
+}
+
+func consumerName(topic string) string {
+	return sanitizeJSName(topic) + "-consumer"
+}
+
+func sanitizeJSName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,165 @@
+package bus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// NATS is a Bus backed by core NATS pub/sub (no JetStream, so delivery
+// is at-most-once and Ack is a no-op), talking the wire protocol
+// directly the same way pkg/respwire does for Redis, since this repo has
+// no NATS client library to vendor.
+type NATS struct {
+	Addr string
+
+	mu   sync.Mutex
+	subs map[string]*natsSub
+}
+
+type natsSub struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// Publish implements Publisher by dialing, handshaking, and issuing a
+// single PUB frame. NATS publish has no server acknowledgement, so a
+// successful write is the only confirmation available.
+func (b *NATS) Publish(ctx context.Context, topic string, payload []byte) error {
+	conn, _, err := dialNATS(b.Addr)
+	if err != nil {
+		return fmt.Errorf("bus: publishing to %s: %w", topic, err)
+	}
+	defer conn.Close()
+
+	if _, err := fmt.Fprintf(conn, "PUB %s %d\r\n", topic, len(payload)); err != nil {
+		return fmt.Errorf("bus: publishing to %s: %w", topic, err)
+	}
+	framed := make([]byte, 0, len(payload)+2)
+	framed = append(framed, payload...)
+	framed = append(framed, '\r', '\n')
+	if _, err := conn.Write(framed); err != nil {
+		return fmt.Errorf("bus: publishing to %s: %w", topic, err)
+	}
+	return nil
+}
+
+// Next implements Subscriber by reading the next MSG frame off topic's
+// subscription, opening one (and sending SUB once) on first use.
+func (b *NATS) Next(ctx context.Context, topic string) (Message, error) {
+	sub, err := b.subscription(topic)
+	if err != nil {
+		return Message{}, fmt.Errorf("bus: subscribing to %s: %w", topic, err)
+	}
+
+	for {
+		line, err := sub.r.ReadString('\n')
+		if err != nil {
+			b.dropSubscription(topic)
+			return Message{}, fmt.Errorf("bus: reading %s: %w", topic, err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			if _, err := sub.conn.Write([]byte("PONG\r\n")); err != nil {
+				return Message{}, fmt.Errorf("bus: reading %s: %w", topic, err)
+			}
+		case strings.HasPrefix(line, "MSG "):
+			return readNATSMessage(sub.r, line)
+		}
+	}
+}
+
+func readNATSMessage(r *bufio.Reader, header string) (Message, error) {
+	// MSG <subject> <sid> [reply-to] <#bytes>
+	fields := strings.Fields(header)
+	if len(fields) < 4 {
+		return Message{}, fmt.Errorf("malformed MSG frame: %q", header)
+	}
+	n, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return Message{}, fmt.Errorf("malformed MSG frame: %q", header)
+	}
+
+	payload := make([]byte, n+2) // payload + trailing \r\n
+	if _, err := readFullNATS(r, payload); err != nil {
+		return Message{}, err
+	}
+	// Core NATS has no per-message ID; ID is left empty.
+	return Message{Payload: payload[:n]}, nil
+}
+
+func readFullNATS(r *bufio.Reader, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := r.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (b *NATS) subscription(topic string) (*natsSub, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if sub, ok := b.subs[topic]; ok {
+		return sub, nil
+	}
+
+	conn, r, err := dialNATS(b.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(conn, "SUB %s 1\r\n", topic); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	sub := &natsSub{conn: conn, r: r}
+	if b.subs == nil {
+		b.subs = make(map[string]*natsSub)
+	}
+	b.subs[topic] = sub
+	return sub, nil
+}
+
+func (b *NATS) dropSubscription(topic string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if sub, ok := b.subs[topic]; ok {
+		sub.conn.Close()
+		delete(b.subs, topic)
+	}
+}
+
+// dialNATS connects to addr, discards the server's INFO greeting, and
+// sends an anonymous CONNECT, leaving the connection ready for PUB/SUB.
+func dialNATS(addr string) (net.Conn, *bufio.Reader, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dialing nats: %w", err)
+	}
+
+	r := bufio.NewReader(conn)
+	if _, err := r.ReadString('\n'); err != nil { // INFO {...}
+		conn.Close()
+		return nil, nil, fmt.Errorf("reading nats info: %w", err)
+	}
+	if _, err := conn.Write([]byte("CONNECT {\"verbose\":false}\r\n")); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("connecting to nats: %w", err)
+	}
+	return conn, r, nil
+}
+
+// Ack implements Subscriber as a no-op; see NATS's doc comment.
+func (b *NATS) Ack(ctx context.Context, topic string, msg Message) error {
+	return nil
+}
@@ -0,0 +1,156 @@
+package bus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/respwire"
+)
+
+// RedisStreams is a Bus backed by a Redis Stream per topic (key
+// "bus:<topic>"), publishing with XADD and consuming by polling XRANGE
+// from the last ID returned, since respwire has no XREADGROUP/XACK
+// support for server-tracked consumer groups. Ack is therefore a no-op;
+// redelivery on restart is avoided only within a single RedisStreams
+// instance's lifetime, via its in-memory cursor.
+type RedisStreams struct {
+	Addr         string
+	PollInterval time.Duration
+
+	mu      sync.Mutex
+	cursors map[string]string // topic -> last ID returned by Next
+}
+
+func (b *RedisStreams) key(topic string) string {
+	return "bus:" + topic
+}
+
+func (b *RedisStreams) dial() (net.Conn, *bufio.Reader, error) {
+	conn, err := net.Dial("tcp", b.Addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bus: dialing redis: %w", err)
+	}
+	return conn, bufio.NewReader(conn), nil
+}
+
+// Publish implements Publisher by XADDing payload to topic's stream
+// with an auto-generated ID.
+func (b *RedisStreams) Publish(ctx context.Context, topic string, payload []byte) error {
+	conn, r, err := b.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(respwire.EncodeCommand("XADD", b.key(topic), "*", "payload", string(payload))); err != nil {
+		return fmt.Errorf("bus: publishing to %s: %w", topic, err)
+	}
+	_, _, err = respwire.ReadBulkString(r)
+	if err != nil {
+		return fmt.Errorf("bus: publishing to %s: %w", topic, err)
+	}
+	return nil
+}
+
+// Next implements Subscriber by polling XRANGE for the first entry after
+// this topic's cursor until one arrives or ctx is done.
+func (b *RedisStreams) Next(ctx context.Context, topic string) (Message, error) {
+	interval := b.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	for {
+		msg, ok, err := b.tryRead(topic)
+		if err != nil {
+			return Message{}, err
+		}
+		if ok {
+			return msg, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return Message{}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (b *RedisStreams) tryRead(topic string) (Message, bool, error) {
+	b.mu.Lock()
+	cursor := b.cursorFor(topic)
+	b.mu.Unlock()
+
+	conn, r, err := b.dial()
+	if err != nil {
+		return Message{}, false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(respwire.EncodeCommand("XRANGE", b.key(topic), "("+cursor, "+", "COUNT", "1")); err != nil {
+		return Message{}, false, fmt.Errorf("bus: reading %s: %w", topic, err)
+	}
+	reply, err := respwire.ReadReply(r)
+	if err != nil {
+		return Message{}, false, fmt.Errorf("bus: reading %s: %w", topic, err)
+	}
+
+	entries, _ := reply.([]respwire.Reply)
+	if len(entries) == 0 {
+		return Message{}, false, nil
+	}
+
+	msg, err := entryToMessage(entries[0])
+	if err != nil {
+		return Message{}, false, fmt.Errorf("bus: reading %s: %w", topic, err)
+	}
+
+	b.mu.Lock()
+	b.cursors[topic] = msg.ID
+	b.mu.Unlock()
+	return msg, true, nil
+}
+
+func (b *RedisStreams) cursorFor(topic string) string {
+	if b.cursors == nil {
+		b.cursors = make(map[string]string)
+	}
+	if cursor, ok := b.cursors[topic]; ok {
+		return cursor
+	}
+	b.cursors[topic] = "0"
+	return "0"
+}
+
+// entryToMessage decodes a single XRANGE entry, shaped as
+// [id, [field1, value1, field2, value2, ...]].
+func entryToMessage(entry respwire.Reply) (Message, error) {
+	fields, ok := entry.([]respwire.Reply)
+	if !ok || len(fields) != 2 {
+		return Message{}, fmt.Errorf("malformed stream entry: %#v", entry)
+	}
+	id, _ := fields[0].(string)
+
+	kv, ok := fields[1].([]respwire.Reply)
+	if !ok {
+		return Message{}, fmt.Errorf("malformed stream entry fields: %#v", fields[1])
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		field, _ := kv[i].(string)
+		if field == "payload" {
+			value, _ := kv[i+1].(string)
+			return Message{ID: id, Payload: []byte(value)}, nil
+		}
+	}
+	return Message{}, fmt.Errorf("stream entry %s has no payload field", id)
+}
+
+// Ack implements Subscriber as a no-op; see RedisStreams's doc comment.
+func (b *RedisStreams) Ack(ctx context.Context, topic string, msg Message) error {
+	return nil
+}
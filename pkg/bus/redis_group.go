@@ -0,0 +1,252 @@
+package bus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/respwire"
+)
+
+// RedisStreamGroup is a Bus backed by a Redis Stream per topic, like
+// RedisStreams, but consumes through a real server-side consumer group
+// (XGROUP/XREADGROUP/XACK) instead of RedisStreams' in-memory XRANGE
+// cursor. Multiple RedisStreamGroup instances sharing Group, each with
+// a distinct Consumer, split a topic's entries between them the way
+// RedisStreams' single-process cursor can't, and Ack is real: an
+// unacked entry sits in the group's pending-entries list until
+// MinIdleTime elapses, at which point Next reclaims it via XAUTOCLAIM
+// instead of it being lost to a crashed worker.
+type RedisStreamGroup struct {
+	Addr     string
+	Group    string
+	Consumer string
+	// MinIdleTime is how long an entry must sit unacked in another
+	// consumer's pending list before Next reclaims it for Consumer.
+	// Zero means never reclaim: only ever read fresh entries.
+	MinIdleTime time.Duration
+	// BlockFor bounds how long a single XREADGROUP call waits for a
+	// new entry before Next loops to recheck ctx. Defaults to one
+	// second.
+	BlockFor time.Duration
+
+	mu      sync.Mutex
+	ensured map[string]bool
+}
+
+func (b *RedisStreamGroup) key(topic string) string {
+	return "bus:" + topic
+}
+
+func (b *RedisStreamGroup) dial() (net.Conn, *bufio.Reader, error) {
+	conn, err := net.Dial("tcp", b.Addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bus: dialing redis: %w", err)
+	}
+	return conn, bufio.NewReader(conn), nil
+}
+
+// Publish implements Publisher by XADDing payload to topic's stream,
+// identically to RedisStreams.Publish.
+func (b *RedisStreamGroup) Publish(ctx context.Context, topic string, payload []byte) error {
+	conn, r, err := b.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(respwire.EncodeCommand("XADD", b.key(topic), "*", "payload", string(payload))); err != nil {
+		return fmt.Errorf("bus: publishing to %s: %w", topic, err)
+	}
+	if _, _, err := respwire.ReadBulkString(r); err != nil {
+		return fmt.Errorf("bus: publishing to %s: %w", topic, err)
+	}
+	return nil
+}
+
+// Next implements Subscriber by first reclaiming a stale pending entry
+// for topic's group if MinIdleTime allows one, then reading a fresh
+// entry with XREADGROUP, blocking and retrying until one arrives or
+// ctx is done.
+func (b *RedisStreamGroup) Next(ctx context.Context, topic string) (Message, error) {
+	if err := b.ensureGroup(topic); err != nil {
+		return Message{}, fmt.Errorf("bus: reading %s: %w", topic, err)
+	}
+
+	block := b.BlockFor
+	if block <= 0 {
+		block = time.Second
+	}
+
+	for {
+		if b.MinIdleTime > 0 {
+			msg, ok, err := b.tryReclaim(topic)
+			if err != nil {
+				return Message{}, fmt.Errorf("bus: reclaiming %s: %w", topic, err)
+			}
+			if ok {
+				return msg, nil
+			}
+		}
+
+		msg, ok, err := b.tryRead(topic, block)
+		if err != nil {
+			return Message{}, fmt.Errorf("bus: reading %s: %w", topic, err)
+		}
+		if ok {
+			return msg, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return Message{}, ctx.Err()
+		default:
+		}
+	}
+}
+
+// tryRead issues one blocking XREADGROUP call for a fresh (">")
+// entry.
+func (b *RedisStreamGroup) tryRead(topic string, block time.Duration) (Message, bool, error) {
+	conn, r, err := b.dial()
+	if err != nil {
+		return Message{}, false, err
+	}
+	defer conn.Close()
+
+	ms := strconv.FormatInt(block.Milliseconds(), 10)
+	cmd := respwire.EncodeCommand("XREADGROUP", "GROUP", b.Group, b.Consumer,
+		"COUNT", "1", "BLOCK", ms, "STREAMS", b.key(topic), ">")
+	if _, err := conn.Write(cmd); err != nil {
+		return Message{}, false, err
+	}
+	reply, err := respwire.ReadReply(r)
+	if err != nil {
+		return Message{}, false, err
+	}
+	if reply == nil {
+		return Message{}, false, nil // BLOCK timed out: nil multi-bulk
+	}
+
+	streams, ok := reply.([]respwire.Reply)
+	if !ok || len(streams) == 0 {
+		return Message{}, false, nil
+	}
+	msg, err := streamEntryToMessage(streams[0])
+	if err != nil {
+		return Message{}, false, err
+	}
+	return msg, true, nil
+}
+
+// tryReclaim runs XAUTOCLAIM to take ownership of one pending entry
+// idle for at least MinIdleTime, for pending-entry recovery after a
+// consumer crashes mid-processing.
+func (b *RedisStreamGroup) tryReclaim(topic string) (Message, bool, error) {
+	conn, r, err := b.dial()
+	if err != nil {
+		return Message{}, false, err
+	}
+	defer conn.Close()
+
+	ms := strconv.FormatInt(b.MinIdleTime.Milliseconds(), 10)
+	cmd := respwire.EncodeCommand("XAUTOCLAIM", b.key(topic), b.Group, b.Consumer, ms, "0-0", "COUNT", "1")
+	if _, err := conn.Write(cmd); err != nil {
+		return Message{}, false, err
+	}
+	reply, err := respwire.ReadReply(r)
+	if err != nil {
+		return Message{}, false, err
+	}
+
+	// XAUTOCLAIM replies [next-cursor, [entries...], [deleted-ids...]].
+	parts, ok := reply.([]respwire.Reply)
+	if !ok || len(parts) < 2 {
+		return Message{}, false, fmt.Errorf("malformed XAUTOCLAIM reply: %#v", reply)
+	}
+	entries, ok := parts[1].([]respwire.Reply)
+	if !ok || len(entries) == 0 {
+		return Message{}, false, nil
+	}
+	msg, err := entryToMessage(entries[0])
+	if err != nil {
+		return Message{}, false, err
+	}
+	return msg, true, nil
+}
+
+// streamEntryToMessage decodes a single XREADGROUP stream entry,
+// shaped as [stream_key, [[id, [field, value, ...]], ...]].
+func streamEntryToMessage(streamReply respwire.Reply) (Message, error) {
+	stream, ok := streamReply.([]respwire.Reply)
+	if !ok || len(stream) != 2 {
+		return Message{}, fmt.Errorf("malformed stream reply: %#v", streamReply)
+	}
+	entries, ok := stream[1].([]respwire.Reply)
+	if !ok || len(entries) == 0 {
+		return Message{}, fmt.Errorf("stream reply has no entries: %#v", stream[1])
+	}
+	return entryToMessage(entries[0])
+}
+
+// ensureGroup creates topic's consumer group (and its stream, via
+// MKSTREAM) starting from the beginning of the stream, ignoring the
+// BUSYGROUP error a group that already exists returns.
+func (b *RedisStreamGroup) ensureGroup(topic string) error {
+	b.mu.Lock()
+	if b.ensured[topic] {
+		b.mu.Unlock()
+		return nil
+	}
+	b.mu.Unlock()
+
+	conn, r, err := b.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	cmd := respwire.EncodeCommand("XGROUP", "CREATE", b.key(topic), b.Group, "0", "MKSTREAM")
+	if _, err := conn.Write(cmd); err != nil {
+		return err
+	}
+	if _, err := respwire.ReadReply(r); err != nil {
+		if errReply, ok := err.(*respwire.ErrReply); ok && strings.HasPrefix(errReply.Message, "BUSYGROUP") {
+			// already exists: fall through to mark it ensured
+		} else {
+			return err
+		}
+	}
+
+	b.mu.Lock()
+	if b.ensured == nil {
+		b.ensured = make(map[string]bool)
+	}
+	b.ensured[topic] = true
+	b.mu.Unlock()
+	return nil
+}
+
+// Ack implements Subscriber with a real XACK, removing msg.ID from
+// topic's group's pending-entries list.
+func (b *RedisStreamGroup) Ack(ctx context.Context, topic string, msg Message) error {
+	conn, r, err := b.dial()
+	if err != nil {
+		return fmt.Errorf("bus: acking %s: %w", topic, err)
+	}
+	defer conn.Close()
+
+	cmd := respwire.EncodeCommand("XACK", b.key(topic), b.Group, msg.ID)
+	if _, err := conn.Write(cmd); err != nil {
+		return fmt.Errorf("bus: acking %s: %w", topic, err)
+	}
+	if _, err := respwire.ReadInteger(r); err != nil {
+		return fmt.Errorf("bus: acking %s: %w", topic, err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Package chaos is an opt-in fault-injection middleware for testing how
+// clients and the retry/circuit-breaker logic elsewhere in this repo
+// (cmd/topk-client's retry and loadtest modes) behave under latency and
+// errors. It is never enabled by default: a Policy with no rules for a
+// method is a no-op.
+package chaos
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Rule injects faults into some percentage of calls to a method.
+type Rule struct {
+	Percent      float64 // 0-100
+	Latency      time.Duration
+	ErrorStatus  int // HTTP status to return instead of calling through, 0 means no error injection
+	AbortPartial bool
+}
+
+// Policy maps method name (as returned by methodFor) to the Rule applied
+// to it.
+type Policy map[string]Rule
+
+// Middleware wraps next, applying p's rule for methodFor(r) to each
+// request.
+func Middleware(p Policy, methodFor func(*http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rule, ok := p[methodFor(r)]
+		if !ok || rand.Float64()*100 >= rule.Percent {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if rule.Latency > 0 {
+			time.Sleep(rule.Latency)
+		}
+
+		if rule.ErrorStatus != 0 {
+			http.Error(w, "chaos: injected fault", rule.ErrorStatus)
+			return
+		}
+
+		if rule.AbortPartial {
+			if flusher, ok := w.(http.Flusher); ok {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte{})
+				flusher.Flush()
+			}
+			if hijacker, ok := w.(http.Hijacker); ok {
+				if conn, _, err := hijacker.Hijack(); err == nil {
+					conn.Close()
+					return
+				}
+			}
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,18 @@
+package client
+
+import (
+	"bytes"
+	"io"
+)
+
+// bytesReader returns an io.Reader over body, or nil if body is nil —
+// http.NewRequestWithContext treats a nil io.Reader as "no body", which
+// bytes.NewReader(nil) does not (it still implies a Content-Length of 0
+// but a non-nil reader, so callers that checked req.Body == nil would be
+// surprised).
+func bytesReader(body []byte) io.Reader {
+	if body == nil {
+		return nil
+	}
+	return bytes.NewReader(body)
+}
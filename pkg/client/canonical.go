@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cgb808/ZenGlowNext/pkg/apierror"
+)
+
+// CanonicalClient talks to CanonicalService and implements
+// pipeline.CanonicalChecker, so it can be handed straight to a
+// pipeline.Coordinator.
+type CanonicalClient struct {
+	*Dialer
+}
+
+// NewCanonicalClient returns a CanonicalClient dialing addr.
+func NewCanonicalClient(addr string) *CanonicalClient {
+	return &CanonicalClient{Dialer: NewDialer(addr)}
+}
+
+// NewCanonicalClientWithTLS is NewCanonicalClient dialing addr over the
+// given *tls.Config, e.g. one built with pkg/spiffe.ClientConfig.
+func NewCanonicalClientWithTLS(addr string, tlsConfig *tls.Config) *CanonicalClient {
+	return &CanonicalClient{Dialer: NewDialerWithTLS(addr, tlsConfig)}
+}
+
+// Visible implements pipeline.CanonicalChecker.
+func (c *CanonicalClient) Visible(ctx context.Context, eventIDs []string) (bool, error) {
+	body, err := json.Marshal(map[string]interface{}{"event_ids": eventIDs})
+	if err != nil {
+		return false, err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/visible", body)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return false, fmt.Errorf("client: canonical.Visible: %w", err)
+	}
+	defer resp.Body.Close()
+	if apiErr, ok := apierror.FromHTTPResponse(resp); ok {
+		return false, apiErr
+	}
+
+	var respBody struct {
+		Visible bool `json:"visible"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return false, fmt.Errorf("client: canonical.Visible: decoding response: %w", err)
+	}
+	return respBody.Visible, nil
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cgb808/ZenGlowNext/pkg/apierror"
+	"github.com/cgb808/ZenGlowNext/pkg/pipeline"
+)
+
+// IngestionClient talks to IngestionService and implements
+// pipeline.FrameSink, so it can be handed straight to a
+// pipeline.Coordinator.
+type IngestionClient struct {
+	*Dialer
+}
+
+// NewIngestionClient returns an IngestionClient dialing addr.
+func NewIngestionClient(addr string) *IngestionClient {
+	return &IngestionClient{Dialer: NewDialer(addr)}
+}
+
+// NewIngestionClientWithTLS is NewIngestionClient dialing addr over the
+// given *tls.Config, e.g. one built with pkg/spiffe.ClientConfig.
+func NewIngestionClientWithTLS(addr string, tlsConfig *tls.Config) *IngestionClient {
+	return &IngestionClient{Dialer: NewDialerWithTLS(addr, tlsConfig)}
+}
+
+// Ingest implements pipeline.FrameSink by posting seg to IngestionService
+// and returning the event IDs it produced.
+func (c *IngestionClient) Ingest(ctx context.Context, seg pipeline.Segment) ([]string, error) {
+	body, err := json.Marshal(seg)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/ingest", body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("client: ingestion.Ingest: %w", err)
+	}
+	defer resp.Body.Close()
+	if apiErr, ok := apierror.FromHTTPResponse(resp); ok {
+		return nil, apiErr
+	}
+
+	var respBody struct {
+		EventIDs []string `json:"event_ids"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return nil, fmt.Errorf("client: ingestion.Ingest: decoding response: %w", err)
+	}
+	return respBody.EventIDs, nil
+}
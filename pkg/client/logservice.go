@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cgb808/ZenGlowNext/pkg/apierror"
+	"github.com/cgb808/ZenGlowNext/pkg/logservice"
+)
+
+// LogServiceClient talks to LogService: WriteLogStream appends a batch
+// of frames, and ListSegments/ReadSession read them back from
+// pkg/logservice.
+type LogServiceClient struct {
+	*Dialer
+}
+
+// NewLogServiceClient returns a LogServiceClient dialing addr.
+func NewLogServiceClient(addr string) *LogServiceClient {
+	return &LogServiceClient{Dialer: NewDialer(addr)}
+}
+
+// NewLogServiceClientWithTLS is NewLogServiceClient dialing addr over the
+// given *tls.Config, e.g. one built with pkg/spiffe.ClientConfig.
+func NewLogServiceClientWithTLS(addr string, tlsConfig *tls.Config) *LogServiceClient {
+	return &LogServiceClient{Dialer: NewDialerWithTLS(addr, tlsConfig)}
+}
+
+// WriteLogStream appends a batch of log lines to tenantID's sessionID and
+// returns the segment ID they were written to. Despite its name, this is
+// the unary RPC (logservice.WriteBatchHandler, POST /write) rather than
+// the WebSocket one (see pkg/logclient for that) — it's named for the
+// stream of lines a producer hands over in one call, not the transport.
+// tenantID may be "" for the flat, single-tenant layout.
+func (c *LogServiceClient) WriteLogStream(ctx context.Context, tenantID, sessionID string, lines []string) (segmentID string, err error) {
+	body, err := json.Marshal(map[string]interface{}{"lines": lines})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/write?session_id="+sessionID+"&tenant_id="+tenantID, body)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("client: logservice.WriteLogStream: %w", err)
+	}
+	defer resp.Body.Close()
+	if apiErr, ok := apierror.FromHTTPResponse(resp); ok {
+		return "", apiErr
+	}
+
+	var respBody struct {
+		SegmentID string `json:"segment_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return "", fmt.Errorf("client: logservice.WriteLogStream: decoding response: %w", err)
+	}
+	return respBody.SegmentID, nil
+}
+
+// ListSegments lists tenantID's sessionID's rotated segments so a
+// downstream consumer can decide which ones to fetch with ReadSession,
+// instead of scraping the filesystem. tenantID may be "" for the flat,
+// single-tenant layout.
+func (c *LogServiceClient) ListSegments(ctx context.Context, tenantID, sessionID string) ([]logservice.SegmentInfo, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/segments?session_id="+sessionID+"&tenant_id="+tenantID, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("client: logservice.ListSegments: %w", err)
+	}
+	defer resp.Body.Close()
+	if apiErr, ok := apierror.FromHTTPResponse(resp); ok {
+		return nil, apiErr
+	}
+
+	var body struct {
+		Segments []logservice.SegmentInfo `json:"segments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("client: logservice.ListSegments: decoding response: %w", err)
+	}
+	return body.Segments, nil
+}
+
+// ReadSession streams tenantID's sessionID's frames in [fromSeq, toSeq]
+// back, in order, across however many segments that range spans.
+// tenantID may be "" for the flat, single-tenant layout.
+func (c *LogServiceClient) ReadSession(ctx context.Context, tenantID, sessionID string, fromSeq, toSeq int64) ([]logservice.Frame, error) {
+	path := "/frames?session_id=" + sessionID + "&tenant_id=" + tenantID +
+		"&from_seq=" + strconv.FormatInt(fromSeq, 10) +
+		"&to_seq=" + strconv.FormatInt(toSeq, 10)
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("client: logservice.ReadSession: %w", err)
+	}
+	defer resp.Body.Close()
+	if apiErr, ok := apierror.FromHTTPResponse(resp); ok {
+		return nil, apiErr
+	}
+
+	var body struct {
+		Frames []logservice.Frame `json:"frames"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("client: logservice.ReadSession: decoding response: %w", err)
+	}
+	return body.Frames, nil
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cgb808/ZenGlowNext/pkg/apierror"
+)
+
+// RouterClient talks to RouterService (cmd/grpc-router).
+type RouterClient struct {
+	*Dialer
+}
+
+// NewRouterClient returns a RouterClient dialing addr.
+func NewRouterClient(addr string) *RouterClient {
+	return &RouterClient{Dialer: NewDialer(addr)}
+}
+
+// NewRouterClientWithTLS is NewRouterClient dialing addr over the given
+// *tls.Config, e.g. one built with pkg/spiffe.ClientConfig.
+func NewRouterClientWithTLS(addr string, tlsConfig *tls.Config) *RouterClient {
+	return &RouterClient{Dialer: NewDialerWithTLS(addr, tlsConfig)}
+}
+
+// Route asks RouterService which node a key routes to.
+func (c *RouterClient) Route(ctx context.Context, key string) (string, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/route?key="+key, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("client: router.Route: %w", err)
+	}
+	defer resp.Body.Close()
+	if apiErr, ok := apierror.FromHTTPResponse(resp); ok {
+		return "", apiErr
+	}
+
+	var body struct {
+		Node string `json:"node"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("client: router.Route: decoding response: %w", err)
+	}
+	return body.Node, nil
+}
@@ -0,0 +1,142 @@
+// Package config is the shared configuration loader for every ZenGlowNext
+// binary: each service defines its own typed struct with `env` tags and
+// calls Load to populate it from the process environment, with an
+// optional .env-style file as a lower-priority source. Keeping this in
+// one place means every binary agrees on precedence (flags > env > file >
+// default) and on how missing required fields are reported.
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Load populates dst (a pointer to a struct) from environment variables
+// named by each field's `env` tag, falling back to envFile for any
+// variable not set in the process environment. A field tagged
+// `env:"NAME,required"` causes Load to return an error if NAME is unset
+// everywhere. Supported field types are string, bool, int, int64,
+// float64, and time.Duration.
+func Load(dst interface{}, envFile string) error {
+	fileVars, err := parseEnvFile(envFile)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: Load requires a pointer to a struct, got %T", dst)
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("env")
+		if tag == "" {
+			continue
+		}
+		name, required := parseTag(tag)
+
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			raw, ok = fileVars[name]
+		}
+		if !ok {
+			if required {
+				return fmt.Errorf("config: required environment variable %s is not set", name)
+			}
+			continue
+		}
+
+		if err := setField(v.Field(i), raw); err != nil {
+			return fmt.Errorf("config: %s=%q: %w", name, raw, err)
+		}
+	}
+	return nil
+}
+
+func parseTag(tag string) (name string, required bool) {
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	for _, opt := range parts[1:] {
+		if opt == "required" {
+			required = true
+		}
+	}
+	return name, required
+}
+
+func setField(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		if field.Type() == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				return err
+			}
+			field.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field kind %s", field.Kind())
+	}
+	return nil
+}
+
+// parseEnvFile reads "KEY=VALUE" lines from path, ignoring blank lines and
+// lines starting with '#'. A missing path is not an error: file-backed
+// config is optional.
+func parseEnvFile(path string) (map[string]string, error) {
+	vars := map[string]string{}
+	if path == "" {
+		return vars, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return vars, nil
+		}
+		return nil, fmt.Errorf("config: reading %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		vars[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return vars, scanner.Err()
+}
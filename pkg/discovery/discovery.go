@@ -0,0 +1,47 @@
+// Package discovery lets services register themselves with a heartbeat
+// and lets callers (the router, the pipeline coordinator) resolve the
+// live instances of a service by name instead of hardcoding addresses.
+// The registry is a Redis hash per service name, keyed by instance
+// address with a TTL refreshed by each heartbeat; an instance that stops
+// heartbeating ages out on its own.
+package discovery
+
+import (
+	"context"
+	"time"
+)
+
+// Instance is one live instance of a service.
+type Instance struct {
+	Addr string
+	Zone string
+}
+
+// Registry registers and resolves service instances.
+type Registry interface {
+	Register(ctx context.Context, service string, instance Instance, ttl time.Duration) error
+	Resolve(ctx context.Context, service string) ([]Instance, error)
+}
+
+// Heartbeat calls registry.Register for instance on every interval until
+// ctx is done, so instance's entry never expires while the process is
+// alive. onErr is called (without stopping) if a heartbeat fails.
+func Heartbeat(ctx context.Context, registry Registry, service string, instance Instance, ttl, interval time.Duration, onErr func(error)) {
+	register := func() {
+		if err := registry.Register(ctx, service, instance, ttl); err != nil && onErr != nil {
+			onErr(err)
+		}
+	}
+
+	register()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			register()
+		}
+	}
+}
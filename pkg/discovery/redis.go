@@ -0,0 +1,108 @@
+package discovery
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/respwire"
+)
+
+// RedisRegistry stores each instance under key
+// "<Prefix><service>:<addr>" with a value of "<zone>" and a TTL, so an
+// instance that stops heartbeating simply expires out of Resolve's
+// results.
+type RedisRegistry struct {
+	Addr    string
+	Prefix  string
+	Timeout time.Duration
+}
+
+func (r *RedisRegistry) dial() (net.Conn, error) {
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return net.DialTimeout("tcp", r.Addr, timeout)
+}
+
+func (r *RedisRegistry) key(service, addr string) string {
+	return fmt.Sprintf("%s%s:%s", r.Prefix, service, addr)
+}
+
+// Register implements Registry with a SET ... EX command.
+func (r *RedisRegistry) Register(ctx context.Context, service string, instance Instance, ttl time.Duration) error {
+	conn, err := r.dial()
+	if err != nil {
+		return fmt.Errorf("discovery: dial redis %s: %w", r.Addr, err)
+	}
+	defer conn.Close()
+
+	seconds := int(ttl.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	cmd := respwire.EncodeCommand("SET", r.key(service, instance.Addr), instance.Zone, "EX", strconv.Itoa(seconds))
+	if _, err := conn.Write(cmd); err != nil {
+		return fmt.Errorf("discovery: write SET: %w", err)
+	}
+
+	if _, err := respwire.ReadSimpleString(bufio.NewReader(conn)); err != nil {
+		return fmt.Errorf("discovery: read SET reply: %w", err)
+	}
+	return nil
+}
+
+// Resolve lists every live instance of service by scanning keys under
+// its prefix and fetching each one's zone.
+func (r *RedisRegistry) Resolve(ctx context.Context, service string) ([]Instance, error) {
+	conn, err := r.dial()
+	if err != nil {
+		return nil, fmt.Errorf("discovery: dial redis %s: %w", r.Addr, err)
+	}
+	defer conn.Close()
+
+	pattern := r.key(service, "*")
+	if _, err := conn.Write(respwire.EncodeCommand("KEYS", pattern)); err != nil {
+		return nil, fmt.Errorf("discovery: write KEYS: %w", err)
+	}
+	reader := bufio.NewReader(conn)
+	keys, err := respwire.ReadArray(reader)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: read KEYS reply: %w", err)
+	}
+
+	instances := make([]Instance, 0, len(keys))
+	for _, key := range keys {
+		zone, addr, err := r.fetchInstance(key, service)
+		if err != nil {
+			continue
+		}
+		instances = append(instances, Instance{Addr: addr, Zone: zone})
+	}
+	return instances, nil
+}
+
+func (r *RedisRegistry) fetchInstance(key, service string) (zone, addr string, err error) {
+	addr = strings.TrimPrefix(key, r.key(service, ""))
+
+	conn, err := r.dial()
+	if err != nil {
+		return "", "", err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(respwire.EncodeCommand("GET", key)); err != nil {
+		return "", "", err
+	}
+	value, ok, err := respwire.ReadBulkString(bufio.NewReader(conn))
+	if err != nil || !ok {
+		return "", "", fmt.Errorf("discovery: key %s expired or unreadable", key)
+	}
+	return value, addr, nil
+}
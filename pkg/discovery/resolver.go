@@ -0,0 +1,23 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+)
+
+// Nodes resolves service's live instances and converts them to
+// hashring.Node values, so a resolver can feed straight into
+// pkg/hashring's routing functions instead of the router keeping its own
+// hardcoded node list.
+func Nodes(ctx context.Context, registry Registry, service string) ([]hashring.Node, error) {
+	instances, err := registry.Resolve(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]hashring.Node, len(instances))
+	for i, inst := range instances {
+		nodes[i] = hashring.Node{ID: inst.Addr, Weight: 1, Zone: inst.Zone}
+	}
+	return nodes, nil
+}
@@ -0,0 +1,46 @@
+// Package dlq is the shared dead-letter queue abstraction used by the
+// ingester pipeline and the router's async job mode whenever a message
+// exhausts its retries. It defines a Store interface with two
+// implementations, one backed by Postgres (for durable, queryable
+// history) and one backed by Redis (for low-latency local queues), so
+// callers can pick whichever matches the durability they need.
+package dlq
+
+import (
+	"context"
+	"time"
+)
+
+// Entry is one failed message parked in a dead-letter queue.
+type Entry struct {
+	ID            string
+	Queue         string
+	Payload       []byte
+	Reason        string
+	Attempts      int
+	FirstFailedAt time.Time
+	LastFailedAt  time.Time
+}
+
+// Store records, lists, and requeues dead-lettered entries. Queue
+// namespaces entries so, e.g., ingester and router jobs can share one
+// Store without colliding.
+type Store interface {
+	// Push records a new dead-lettered entry, or increments Attempts and
+	// updates LastFailedAt if one with the same ID and Queue exists.
+	Push(ctx context.Context, e Entry) error
+
+	// List returns up to limit entries for queue, most recently failed
+	// first.
+	List(ctx context.Context, queue string, limit int) ([]Entry, error)
+
+	// Get returns a single entry by ID.
+	Get(ctx context.Context, queue, id string) (Entry, error)
+
+	// Requeue removes the entry from the dead-letter queue and returns
+	// it so the caller can resubmit its Payload to the original queue.
+	Requeue(ctx context.Context, queue, id string) (Entry, error)
+
+	// Delete discards an entry without resubmitting it.
+	Delete(ctx context.Context, queue, id string) error
+}
@@ -0,0 +1,159 @@
+package dlq
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PostgresStore persists entries to Table via psql, the same approach
+// pkg/pgbootstrap and pkg/audit use to avoid depending on a Go Postgres
+// driver this repo can't fetch offline. Payload is stored base64-encoded
+// since it may be arbitrary binary data.
+type PostgresStore struct {
+	DatabaseURL string
+	Table       string
+}
+
+func (s PostgresStore) table() string {
+	if s.Table == "" {
+		return "dlq_entries"
+	}
+	return s.Table
+}
+
+// Push implements Store.
+func (s PostgresStore) Push(ctx context.Context, e Entry) error {
+	now := e.LastFailedAt
+	if now.IsZero() {
+		now = time.Now()
+	}
+	sql := fmt.Sprintf(
+		`INSERT INTO %s (id, queue, payload, reason, attempts, first_failed_at, last_failed_at)
+		 VALUES (%s, %s, %s, %s, 1, %s, %s)
+		 ON CONFLICT (queue, id) DO UPDATE SET
+		   reason = EXCLUDED.reason,
+		   attempts = %s.attempts + 1,
+		   last_failed_at = EXCLUDED.last_failed_at;`,
+		s.table(),
+		quoteLiteral(e.ID), quoteLiteral(e.Queue), quoteLiteral(base64.StdEncoding.EncodeToString(e.Payload)),
+		quoteLiteral(e.Reason), quoteLiteral(now.Format(timeLayout)), quoteLiteral(now.Format(timeLayout)),
+		s.table(),
+	)
+	return runPsql(ctx, s.DatabaseURL, sql)
+}
+
+// List implements Store.
+func (s PostgresStore) List(ctx context.Context, queue string, limit int) ([]Entry, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	sql := fmt.Sprintf(
+		"SELECT id, queue, payload, reason, attempts, first_failed_at, last_failed_at FROM %s WHERE queue = %s ORDER BY last_failed_at DESC LIMIT %d",
+		s.table(), quoteLiteral(queue), limit,
+	)
+	records, err := runPsqlCSV(ctx, s.DatabaseURL, sql)
+	if err != nil {
+		return nil, fmt.Errorf("dlq: listing %s: %w", queue, err)
+	}
+
+	entries := make([]Entry, 0, len(records))
+	for _, r := range records {
+		e, err := entryFromRecord(r)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Get implements Store.
+func (s PostgresStore) Get(ctx context.Context, queue, id string) (Entry, error) {
+	sql := fmt.Sprintf(
+		"SELECT id, queue, payload, reason, attempts, first_failed_at, last_failed_at FROM %s WHERE queue = %s AND id = %s",
+		s.table(), quoteLiteral(queue), quoteLiteral(id),
+	)
+	records, err := runPsqlCSV(ctx, s.DatabaseURL, sql)
+	if err != nil {
+		return Entry{}, fmt.Errorf("dlq: getting %s/%s: %w", queue, id, err)
+	}
+	if len(records) == 0 {
+		return Entry{}, fmt.Errorf("dlq: no entry %s/%s", queue, id)
+	}
+	return entryFromRecord(records[0])
+}
+
+// Requeue implements Store.
+func (s PostgresStore) Requeue(ctx context.Context, queue, id string) (Entry, error) {
+	e, err := s.Get(ctx, queue, id)
+	if err != nil {
+		return Entry{}, err
+	}
+	if err := s.Delete(ctx, queue, id); err != nil {
+		return Entry{}, err
+	}
+	return e, nil
+}
+
+// Delete implements Store.
+func (s PostgresStore) Delete(ctx context.Context, queue, id string) error {
+	sql := fmt.Sprintf("DELETE FROM %s WHERE queue = %s AND id = %s;", s.table(), quoteLiteral(queue), quoteLiteral(id))
+	return runPsql(ctx, s.DatabaseURL, sql)
+}
+
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+func entryFromRecord(r []string) (Entry, error) {
+	if len(r) < 7 {
+		return Entry{}, fmt.Errorf("dlq: malformed row: %v", r)
+	}
+	payload, err := base64.StdEncoding.DecodeString(r[2])
+	if err != nil {
+		return Entry{}, fmt.Errorf("dlq: decoding payload: %w", err)
+	}
+	attempts, _ := strconv.Atoi(r[4])
+	firstFailedAt, _ := time.Parse("2006-01-02 15:04:05", r[5])
+	lastFailedAt, _ := time.Parse("2006-01-02 15:04:05", r[6])
+	return Entry{
+		ID:            r[0],
+		Queue:         r[1],
+		Payload:       payload,
+		Reason:        r[3],
+		Attempts:      attempts,
+		FirstFailedAt: firstFailedAt,
+		LastFailedAt:  lastFailedAt,
+	}, nil
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func runPsql(ctx context.Context, databaseURL, sql string) error {
+	cmd := exec.CommandContext(ctx, "psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", sql)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func runPsqlCSV(ctx context.Context, databaseURL, sql string) ([][]string, error) {
+	copySQL := fmt.Sprintf(`\copy (%s) TO STDOUT WITH (FORMAT csv)`, sql)
+	cmd := exec.CommandContext(ctx, "psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", copySQL)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return csv.NewReader(&stdout).ReadAll()
+}
@@ -0,0 +1,163 @@
+package dlq
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/respwire"
+)
+
+// RedisStore persists entries as a Redis hash per queue (key
+// "dlq:<queue>", field is the entry ID, value is the entry JSON-encoded).
+// A hash rather than a true Redis Stream, since it gives O(1) Get/Delete
+// by ID without needing the nested multi-bulk replies XRANGE returns,
+// which respwire doesn't parse; ordering for List is done client-side.
+type RedisStore struct {
+	Addr string
+}
+
+func (s RedisStore) key(queue string) string {
+	return "dlq:" + queue
+}
+
+func (s RedisStore) dial() (net.Conn, *bufio.Reader, error) {
+	conn, err := net.Dial("tcp", s.Addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dlq: dialing redis: %w", err)
+	}
+	return conn, bufio.NewReader(conn), nil
+}
+
+// Push implements Store.
+func (s RedisStore) Push(ctx context.Context, e Entry) error {
+	conn, r, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	existing, err := s.get(conn, r, e.Queue, e.ID)
+	if err == nil {
+		e.Attempts = existing.Attempts + 1
+		e.FirstFailedAt = existing.FirstFailedAt
+	} else {
+		e.Attempts = 1
+		if e.FirstFailedAt.IsZero() {
+			e.FirstFailedAt = time.Now()
+		}
+	}
+	if e.LastFailedAt.IsZero() {
+		e.LastFailedAt = time.Now()
+	}
+
+	encoded, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("dlq: encoding entry: %w", err)
+	}
+
+	if _, err := conn.Write(respwire.EncodeCommand("HSET", s.key(e.Queue), e.ID, string(encoded))); err != nil {
+		return fmt.Errorf("dlq: pushing: %w", err)
+	}
+	_, err = respwire.ReadInteger(r)
+	return err
+}
+
+// List implements Store.
+func (s RedisStore) List(ctx context.Context, queue string, limit int) ([]Entry, error) {
+	conn, r, err := s.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(respwire.EncodeCommand("HGETALL", s.key(queue))); err != nil {
+		return nil, fmt.Errorf("dlq: listing %s: %w", queue, err)
+	}
+	fields, err := respwire.ReadArray(r)
+	if err != nil {
+		return nil, fmt.Errorf("dlq: listing %s: %w", queue, err)
+	}
+
+	var entries []Entry
+	for i := 0; i+1 < len(fields); i += 2 {
+		var e Entry
+		if err := json.Unmarshal([]byte(fields[i+1]), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	sortByLastFailedAtDesc(entries)
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// Get implements Store.
+func (s RedisStore) Get(ctx context.Context, queue, id string) (Entry, error) {
+	conn, r, err := s.dial()
+	if err != nil {
+		return Entry{}, err
+	}
+	defer conn.Close()
+	return s.get(conn, r, queue, id)
+}
+
+func (s RedisStore) get(conn net.Conn, r *bufio.Reader, queue, id string) (Entry, error) {
+	if _, err := conn.Write(respwire.EncodeCommand("HGET", s.key(queue), id)); err != nil {
+		return Entry{}, fmt.Errorf("dlq: getting %s/%s: %w", queue, id, err)
+	}
+	value, ok, err := respwire.ReadBulkString(r)
+	if err != nil {
+		return Entry{}, fmt.Errorf("dlq: getting %s/%s: %w", queue, id, err)
+	}
+	if !ok {
+		return Entry{}, fmt.Errorf("dlq: no entry %s/%s", queue, id)
+	}
+
+	var e Entry
+	if err := json.Unmarshal([]byte(value), &e); err != nil {
+		return Entry{}, fmt.Errorf("dlq: decoding entry %s/%s: %w", queue, id, err)
+	}
+	return e, nil
+}
+
+// Requeue implements Store.
+func (s RedisStore) Requeue(ctx context.Context, queue, id string) (Entry, error) {
+	e, err := s.Get(ctx, queue, id)
+	if err != nil {
+		return Entry{}, err
+	}
+	if err := s.Delete(ctx, queue, id); err != nil {
+		return Entry{}, err
+	}
+	return e, nil
+}
+
+// Delete implements Store.
+func (s RedisStore) Delete(ctx context.Context, queue, id string) error {
+	conn, r, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(respwire.EncodeCommand("HDEL", s.key(queue), id)); err != nil {
+		return fmt.Errorf("dlq: deleting %s/%s: %w", queue, id, err)
+	}
+	_, err = respwire.ReadInteger(r)
+	return err
+}
+
+func sortByLastFailedAtDesc(entries []Entry) {
+	for i := 1; i < len(entries); i++ {
+		for j := i; j > 0 && entries[j].LastFailedAt.After(entries[j-1].LastFailedAt); j-- {
+			entries[j], entries[j-1] = entries[j-1], entries[j]
+		}
+	}
+}
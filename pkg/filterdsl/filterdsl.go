@@ -0,0 +1,57 @@
+// Package filterdsl defines the filter expression grammar shared by
+// every service that lets a caller narrow a set of events: field
+// comparisons ("user = \"alice\""), time ranges ("created_at >=
+// \"2024-01-01T00:00:00Z\""), and boolean combinators (AND, OR, NOT,
+// parenthesized grouping). Parse turns an expression string into an
+// Expr tree; ToSQL and ToPredicate translate that tree into a SQL WHERE
+// clause fragment (for CanonicalService's Postgres queries) or an
+// in-memory predicate over a string-keyed row (for the ingester's
+// transforms and log read filtering), so both translations agree on
+// what the syntax means.
+package filterdsl
+
+// Op is a comparison operator between a field and a literal value.
+type Op string
+
+const (
+	Eq  Op = "="
+	Ne  Op = "!="
+	Gt  Op = ">"
+	Gte Op = ">="
+	Lt  Op = "<"
+	Lte Op = "<="
+)
+
+// Expr is a node in a parsed filter expression.
+type Expr interface {
+	isExpr()
+}
+
+// Cmp compares Field against Value using Op. Value is always the raw
+// literal text from the expression; ToSQL and ToPredicate each decide
+// how to interpret it (as a string, number, or RFC3339 timestamp).
+type Cmp struct {
+	Field string
+	Op    Op
+	Value string
+}
+
+// And is the conjunction of two expressions.
+type And struct {
+	Left, Right Expr
+}
+
+// Or is the disjunction of two expressions.
+type Or struct {
+	Left, Right Expr
+}
+
+// Not negates an expression.
+type Not struct {
+	Expr Expr
+}
+
+func (Cmp) isExpr() {}
+func (And) isExpr() {}
+func (Or) isExpr()  {}
+func (Not) isExpr() {}
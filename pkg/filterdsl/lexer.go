@@ -0,0 +1,116 @@
+package filterdsl
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// lex splits input into the tokens parser consumes: bare identifiers
+// (field names and the AND/OR/NOT keywords, case-insensitive), double-
+// quoted string literals, bare numbers, parens, and the comparison
+// operators (=, !=, >, >=, <, <=).
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "("})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")"})
+			i++
+
+		case r == '"':
+			j := i + 1
+			var sb strings.Builder
+			for j < len(runes) && runes[j] != '"' {
+				sb.WriteRune(runes[j])
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("filterdsl: unterminated string literal")
+			}
+			tokens = append(tokens, token{tokString, sb.String()})
+			i = j + 1
+
+		case r == '!' || r == '>' || r == '<' || r == '=':
+			j := i + 1
+			if j < len(runes) && runes[j] == '=' {
+				j++
+			}
+			op := string(runes[i:j])
+			if op == "!" {
+				return nil, fmt.Errorf("filterdsl: unexpected character %q", op)
+			}
+			tokens = append(tokens, token{tokOp, op})
+			i = j
+
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[i:j])})
+			i = j
+
+		case isIdentStart(r):
+			j := i + 1
+			for j < len(runes) && isIdentPart(runes[j]) {
+				j++
+			}
+			word := string(runes[i:j])
+			switch strings.ToUpper(word) {
+			case "AND":
+				tokens = append(tokens, token{tokAnd, word})
+			case "OR":
+				tokens = append(tokens, token{tokOr, word})
+			case "NOT":
+				tokens = append(tokens, token{tokNot, word})
+			default:
+				tokens = append(tokens, token{tokIdent, word})
+			}
+			i = j
+
+		default:
+			return nil, fmt.Errorf("filterdsl: unexpected character %q", r)
+		}
+	}
+	tokens = append(tokens, token{tokEOF, ""})
+	return tokens, nil
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
@@ -0,0 +1,120 @@
+package filterdsl
+
+import "fmt"
+
+// Parse parses a filter expression into an Expr tree. Grammar, loosest
+// to tightest binding:
+//
+//	expr   = or
+//	or     = and (OR and)*
+//	and    = unary (AND unary)*
+//	unary  = NOT unary | cmp | "(" or ")"
+//	cmp    = IDENT op (STRING | NUMBER)
+//	op     = "=" | "!=" | ">" | ">=" | "<" | "<="
+func Parse(input string) (Expr, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("filterdsl: unexpected token %q", p.peek().text)
+	}
+	return expr, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: inner}, nil
+	}
+	if p.peek().kind == tokLParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, fmt.Errorf("filterdsl: expected ) but found %q", p.peek().text)
+		}
+		p.next()
+		return inner, nil
+	}
+	return p.parseCmp()
+}
+
+func (p *parser) parseCmp() (Expr, error) {
+	field := p.next()
+	if field.kind != tokIdent {
+		return nil, fmt.Errorf("filterdsl: expected field name but found %q", field.text)
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokOp {
+		return nil, fmt.Errorf("filterdsl: expected comparison operator but found %q", opTok.text)
+	}
+
+	value := p.next()
+	if value.kind != tokString && value.kind != tokNumber {
+		return nil, fmt.Errorf("filterdsl: expected value but found %q", value.text)
+	}
+
+	return Cmp{Field: field.text, Op: Op(opTok.text), Value: value.text}, nil
+}
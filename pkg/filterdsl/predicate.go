@@ -0,0 +1,111 @@
+package filterdsl
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Predicate reports whether a row (column name to string value, the
+// same shape pkg/replication.Row.Columns and pkg/pushgateway.Event.Columns
+// use) satisfies a parsed filter expression.
+type Predicate func(row map[string]string) bool
+
+// ToPredicate translates expr into a Predicate, for filtering rows
+// in-memory (the ingester's transforms, log read filtering) without a
+// round trip through SQL.
+func ToPredicate(expr Expr) (Predicate, error) {
+	switch e := expr.(type) {
+	case Cmp:
+		return cmpPredicate(e)
+	case And:
+		left, err := ToPredicate(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ToPredicate(e.Right)
+		if err != nil {
+			return nil, err
+		}
+		return func(row map[string]string) bool { return left(row) && right(row) }, nil
+	case Or:
+		left, err := ToPredicate(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ToPredicate(e.Right)
+		if err != nil {
+			return nil, err
+		}
+		return func(row map[string]string) bool { return left(row) || right(row) }, nil
+	case Not:
+		inner, err := ToPredicate(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return func(row map[string]string) bool { return !inner(row) }, nil
+	default:
+		return nil, fmt.Errorf("filterdsl: unknown expression type %T", expr)
+	}
+}
+
+func cmpPredicate(c Cmp) (Predicate, error) {
+	return func(row map[string]string) bool {
+		actual, ok := row[c.Field]
+		if !ok {
+			return false
+		}
+		return compare(actual, c.Value, c.Op)
+	}, nil
+}
+
+// compare evaluates op between actual and want, trying a timestamp
+// comparison first (both sides parse as RFC3339), then numeric, falling
+// back to a string comparison for "=" and "!=" only (ordering operators
+// on non-numeric, non-timestamp values are always false).
+func compare(actual, want string, op Op) bool {
+	if at, err := time.Parse(time.RFC3339, actual); err == nil {
+		if wt, err := time.Parse(time.RFC3339, want); err == nil {
+			return compareOrdered(at.Compare(wt), op)
+		}
+	}
+	if an, err := strconv.ParseFloat(actual, 64); err == nil {
+		if wn, err := strconv.ParseFloat(want, 64); err == nil {
+			switch {
+			case an < wn:
+				return compareOrdered(-1, op)
+			case an > wn:
+				return compareOrdered(1, op)
+			default:
+				return compareOrdered(0, op)
+			}
+		}
+	}
+	switch op {
+	case Eq:
+		return actual == want
+	case Ne:
+		return actual != want
+	default:
+		return false
+	}
+}
+
+func compareOrdered(cmp int, op Op) bool {
+	switch op {
+	case Eq:
+		return cmp == 0
+	case Ne:
+		return cmp != 0
+	case Gt:
+		return cmp > 0
+	case Gte:
+		return cmp >= 0
+	case Lt:
+		return cmp < 0
+	case Lte:
+		return cmp <= 0
+	default:
+		return false
+	}
+}
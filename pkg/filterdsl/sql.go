@@ -0,0 +1,58 @@
+package filterdsl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ToSQL renders expr as a SQL boolean expression suitable for a WHERE
+// clause, the same string-literal quoting pkg/backup and pkg/metering
+// use when shelling filter values to psql. Field names are emitted
+// verbatim, so callers should only run Parse on input from trusted
+// callers or validate field names against an allowlist first — ToSQL
+// itself does not know which fields a given table actually has.
+func ToSQL(expr Expr) (string, error) {
+	switch e := expr.(type) {
+	case Cmp:
+		return fmt.Sprintf("%s %s %s", e.Field, e.Op, sqlLiteral(e.Value)), nil
+	case And:
+		left, err := ToSQL(e.Left)
+		if err != nil {
+			return "", err
+		}
+		right, err := ToSQL(e.Right)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s AND %s)", left, right), nil
+	case Or:
+		left, err := ToSQL(e.Left)
+		if err != nil {
+			return "", err
+		}
+		right, err := ToSQL(e.Right)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s OR %s)", left, right), nil
+	case Not:
+		inner, err := ToSQL(e.Expr)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("NOT (%s)", inner), nil
+	default:
+		return "", fmt.Errorf("filterdsl: unknown expression type %T", expr)
+	}
+}
+
+// sqlLiteral renders value as a SQL literal: unquoted if it parses as a
+// number, single-quoted (with embedded quotes escaped) otherwise, which
+// covers both plain strings and RFC3339 timestamps.
+func sqlLiteral(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,35 @@
+package flags
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// EnvSource reads flag values from environment variables named
+// Prefix+FLAG_NAME (upper-cased), e.g. with Prefix "FLAG_", the flag
+// "hybrid_search" is read from FLAG_HYBRID_SEARCH.
+type EnvSource struct {
+	Prefix string
+	Names  []string
+}
+
+// Load implements Source by reading each configured flag's environment
+// variable, treating an unset or unparsable value as false.
+func (s EnvSource) Load(ctx context.Context) (map[string]bool, error) {
+	values := make(map[string]bool, len(s.Names))
+	for _, name := range s.Names {
+		varName := s.Prefix + strings.ToUpper(name)
+		raw, ok := os.LookupEnv(varName)
+		if !ok {
+			continue
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			continue
+		}
+		values[name] = b
+	}
+	return values, nil
+}
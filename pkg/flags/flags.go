@@ -0,0 +1,74 @@
+// Package flags evaluates boolean feature flags (hybrid search,
+// batching, new segment format, ...) per request, backed by either the
+// process environment or a Redis hash, with an in-memory cache and a
+// change-watch loop so flags can be flipped without a redeploy.
+package flags
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Source resolves the current value of every known flag in one call, the
+// same shape pkg/nodeset.Source uses for node sets: cheap to poll, and
+// callers that want change notifications layer Watch on top.
+type Source interface {
+	Load(ctx context.Context) (map[string]bool, error)
+}
+
+// Flags evaluates feature flags from a Source, caching the last
+// successful load so Enabled never blocks on the backing store.
+type Flags struct {
+	mu       sync.RWMutex
+	src      Source
+	values   map[string]bool
+	fallback bool
+}
+
+// New returns a Flags backed by src. defaultValue is returned by Enabled
+// for any flag not present in the most recent load.
+func New(src Source, defaultValue bool) *Flags {
+	return &Flags{src: src, values: map[string]bool{}, fallback: defaultValue}
+}
+
+// Enabled reports whether name is currently on.
+func (f *Flags) Enabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	v, ok := f.values[name]
+	if !ok {
+		return f.fallback
+	}
+	return v
+}
+
+// Refresh loads the current flag values from the Source and swaps them
+// in atomically.
+func (f *Flags) Refresh(ctx context.Context) error {
+	values, err := f.src.Load(ctx)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.values = values
+	f.mu.Unlock()
+	return nil
+}
+
+// Watch calls Refresh on interval until ctx is done, reporting errors to
+// onErr without stopping the loop.
+func (f *Flags) Watch(ctx context.Context, interval time.Duration, onErr func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := f.Refresh(ctx); err != nil && onErr != nil {
+				onErr(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package flags
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/respwire"
+)
+
+// RedisSource reads flag values from a Redis hash (HGETALL Key), where
+// each field is a flag name and its value is "true"/"false". This is the
+// backing store operators flip flags against in production, since it
+// doesn't require a redeploy the way EnvSource does.
+type RedisSource struct {
+	Addr    string
+	Key     string
+	Timeout time.Duration
+}
+
+// Load implements Source.
+func (s RedisSource) Load(ctx context.Context) (map[string]bool, error) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	conn, err := net.DialTimeout("tcp", s.Addr, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("flags: dial redis %s: %w", s.Addr, err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(respwire.EncodeCommand("HGETALL", s.Key)); err != nil {
+		return nil, fmt.Errorf("flags: write HGETALL: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	fields, err := respwire.ReadArray(reader)
+	if err != nil {
+		return nil, fmt.Errorf("flags: read HGETALL reply: %w", err)
+	}
+
+	values := make(map[string]bool, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		values[fields[i]] = strings.EqualFold(fields[i+1], "true")
+	}
+	return values, nil
+}
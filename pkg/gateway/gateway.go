@@ -0,0 +1,68 @@
+// Package gateway is the shared REST/JSON front door for LogService,
+// IngestionService, RouterService, and CanonicalService. Those services
+// expose their RPCs over the plain HTTP/JSON transport used throughout
+// this repo (see pkg/topkclient and cmd/grpc-router) rather than real
+// gRPC, so "gateway" here means a single mux that each service registers
+// its routes on, plus an OpenAPI document describing them — there is no
+// protobuf to generate a gateway or spec from.
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Route describes one HTTP endpoint a service exposes, enough detail to
+// both mount it and describe it in OpenAPI.
+type Route struct {
+	Service string
+	Method  string // HTTP method, e.g. "POST"
+	Path    string
+	Summary string
+	Handler http.Handler
+}
+
+// Mux is a shared HTTP mux that accumulates Routes from multiple
+// services so they can be served behind one listener with one auth
+// layer, and describes itself as an OpenAPI document. "One auth layer"
+// describes how a caller is meant to use Mux, not something Mux itself
+// enforces: Register mounts r.Handler exactly as given, so authenticating
+// every route the same way means wrapping each Handler with
+// authn.Middleware/authn.MiddlewareMTLS (and, for authorization,
+// authz.Middleware) before passing it to Register — see cmd/grpc-router
+// and cmd/zenglow's serve-logservice for that pattern. A Handler
+// registered unwrapped is served unauthenticated; Mux has no way to
+// detect that and does not try to.
+type Mux struct {
+	mux    *http.ServeMux
+	routes []Route
+}
+
+// New returns an empty Mux.
+func New() *Mux {
+	return &Mux{mux: http.NewServeMux()}
+}
+
+// Register mounts r.Handler at r.Path and records r for the OpenAPI spec.
+// Services call this once per RPC they expose over the gateway, after
+// applying whatever auth middleware that RPC needs — see Mux's doc
+// comment.
+func (m *Mux) Register(r Route) {
+	m.mux.Handle(r.Path, r.Handler)
+	m.routes = append(m.routes, r)
+}
+
+// ServeHTTP implements http.Handler by dispatching to the registered
+// routes, and additionally serves the OpenAPI document at /openapi.json.
+func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/openapi.json" {
+		m.serveOpenAPI(w)
+		return
+	}
+	m.mux.ServeHTTP(w, r)
+}
+
+func (m *Mux) serveOpenAPI(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m.openAPIDocument())
+}
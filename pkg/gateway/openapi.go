@@ -0,0 +1,59 @@
+package gateway
+
+// openAPIDocument builds a minimal OpenAPI 3.0 document describing the
+// Mux's registered routes, grouped under each service's tag. It's hand
+// assembled from Route metadata rather than generated from protobuf,
+// since none of these services are actually defined in .proto files.
+func (m *Mux) openAPIDocument() map[string]interface{} {
+	paths := map[string]interface{}{}
+	tags := map[string]bool{}
+
+	for _, r := range m.routes {
+		tags[r.Service] = true
+
+		entry, ok := paths[r.Path].(map[string]interface{})
+		if !ok {
+			entry = map[string]interface{}{}
+			paths[r.Path] = entry
+		}
+		entry[methodKey(r.Method)] = map[string]interface{}{
+			"summary": r.Summary,
+			"tags":    []string{r.Service},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{"description": "OK"},
+			},
+		}
+	}
+
+	tagList := make([]map[string]string, 0, len(tags))
+	for name := range tags {
+		tagList = append(tagList, map[string]string{"name": name})
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "ZenGlowNext services",
+			"version": "1.0.0",
+		},
+		"tags":  tagList,
+		"paths": paths,
+	}
+}
+
+func methodKey(method string) string {
+	if method == "" {
+		return "get"
+	}
+	return toLower(method)
+}
+
+func toLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+		}
+	}
+	return string(b)
+}
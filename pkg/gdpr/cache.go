@@ -0,0 +1,41 @@
+package gdpr
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+
+	"github.com/cgb808/ZenGlowNext/pkg/respwire"
+)
+
+// invalidateCache deletes each key from the Redis instance at addr and
+// returns the ones that actually existed. respwire has no SCAN support,
+// so callers must supply the exact keys to delete (Workflow builds them
+// from CacheKeyPatterns) rather than a pattern to match.
+func invalidateCache(addr string, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("gdpr: dialing redis: %w", err)
+	}
+	defer conn.Close()
+	rdr := bufio.NewReader(conn)
+
+	var invalidated []string
+	for _, key := range keys {
+		if _, err := conn.Write(respwire.EncodeCommand("DEL", key)); err != nil {
+			return invalidated, fmt.Errorf("gdpr: deleting %s: %w", key, err)
+		}
+		n, err := respwire.ReadInteger(rdr)
+		if err != nil {
+			return invalidated, fmt.Errorf("gdpr: deleting %s: %w", key, err)
+		}
+		if n > 0 {
+			invalidated = append(invalidated, key)
+		}
+	}
+	return invalidated, nil
+}
@@ -0,0 +1,99 @@
+// Package gdpr implements the DeleteUserData workflow: given a subject
+// (the authenticated caller's identity, same as pkg/metering's tenant),
+// it deletes that subject's rows from the events table, rewrites log
+// segments to drop their frames, invalidates any Redis-cached results
+// keyed by subject, and returns a signed Report a requester can keep as
+// evidence the deletion ran. The four steps are independent best-effort
+// operations rather than a single transaction, since they span Postgres,
+// the segment filesystem, and Redis — Report records what each step
+// actually did so a partial failure is visible rather than silently
+// swallowed.
+package gdpr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/keys"
+)
+
+// Workflow runs DeleteUserData against a specific deployment's Postgres
+// database, segment directory, and cache.
+type Workflow struct {
+	DatabaseURL string
+	Table       string // events table, default "events"
+
+	SegmentDir string // directory of rotated log segments to tombstone
+
+	CacheAddr        string   // Redis address fronting cached query results, if any
+	CacheKeyPatterns []string // fmt patterns with one %s for the subject, e.g. "topk:%s"
+
+	// Keys signs the resulting Report with its current KEK so a
+	// requester can prove a report wasn't forged or altered after the
+	// fact. Optional: a nil Keys leaves Report.Signature empty.
+	Keys keys.Source
+}
+
+// DeleteUserData runs every configured deletion step for subject and
+// returns a Report describing what happened. It returns an error only
+// if every step failed to even attempt its work; a step that ran but
+// deleted nothing is not an error.
+func (w *Workflow) DeleteUserData(ctx context.Context, subject string) (Report, error) {
+	if subject == "" {
+		return Report{}, fmt.Errorf("gdpr: subject is required")
+	}
+
+	report := Report{
+		Subject:     subject,
+		RequestedAt: time.Now().UTC(),
+	}
+
+	if w.DatabaseURL != "" {
+		rows, err := deletePostgresRows(ctx, w.DatabaseURL, w.table(), subject)
+		if err != nil {
+			return report, fmt.Errorf("gdpr: deleting %s's events: %w", subject, err)
+		}
+		report.PostgresRowsDeleted = rows
+	}
+
+	if w.SegmentDir != "" {
+		rewritten, err := tombstoneSegments(w.SegmentDir, subject)
+		if err != nil {
+			return report, fmt.Errorf("gdpr: tombstoning %s's segments: %w", subject, err)
+		}
+		report.SegmentsRewritten = rewritten
+	}
+
+	if w.CacheAddr != "" && len(w.CacheKeyPatterns) > 0 {
+		cacheKeys := cacheKeysFor(w.CacheKeyPatterns, subject)
+		invalidated, err := invalidateCache(w.CacheAddr, cacheKeys)
+		if err != nil {
+			return report, fmt.Errorf("gdpr: invalidating %s's cache entries: %w", subject, err)
+		}
+		report.CacheKeysInvalidated = invalidated
+	}
+
+	if w.Keys != nil {
+		if err := report.Sign(ctx, w.Keys); err != nil {
+			return report, fmt.Errorf("gdpr: signing report for %s: %w", subject, err)
+		}
+	}
+
+	return report, nil
+}
+
+func (w *Workflow) table() string {
+	if w.Table == "" {
+		return "events"
+	}
+	return w.Table
+}
+
+func cacheKeysFor(patterns []string, subject string) []string {
+	out := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		out[i] = fmt.Sprintf(pattern, subject)
+	}
+	return out
+}
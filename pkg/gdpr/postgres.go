@@ -0,0 +1,48 @@
+package gdpr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// deletePostgresRows deletes every row in table whose subject column
+// matches subject, via psql the same way pkg/backup and pkg/metering do
+// to avoid depending on a Go Postgres driver this repo can't fetch
+// offline, and returns how many rows psql reports deleting.
+func deletePostgresRows(ctx context.Context, databaseURL, table, subject string) (int64, error) {
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE subject = %s;`, table, quoteLiteral(subject))
+
+	cmd := exec.CommandContext(ctx, "psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", sql)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return 0, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseDeleteTag(stdout.String())
+}
+
+// parseDeleteTag extracts the row count from psql's "DELETE <n>" command
+// tag, the only line it prints for a bare DELETE with -c.
+func parseDeleteTag(output string) (int64, error) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "DELETE" {
+			n, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("gdpr: malformed DELETE tag %q", line)
+			}
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("gdpr: no DELETE tag in psql output: %q", strings.TrimSpace(output))
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
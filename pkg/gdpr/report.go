@@ -0,0 +1,80 @@
+package gdpr
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/keys"
+)
+
+// Report records what DeleteUserData actually did for a subject, so a
+// deletion request can be answered with evidence rather than a bare
+// "done".
+type Report struct {
+	Subject     string    `json:"subject"`
+	RequestedAt time.Time `json:"requested_at"`
+
+	PostgresRowsDeleted  int64    `json:"postgres_rows_deleted"`
+	SegmentsRewritten    []string `json:"segments_rewritten"`
+	CacheKeysInvalidated []string `json:"cache_keys_invalidated"`
+
+	// SignedWithKEK is the KEK version Sign used, so Verify can fetch
+	// the same key back out of a Source even after rotation.
+	SignedWithKEK int    `json:"signed_with_kek,omitempty"`
+	Signature     string `json:"signature,omitempty"`
+}
+
+// Sign computes an HMAC-SHA256 over r's fields using src's current KEK,
+// the same key material pkg/keys uses to wrap segment encryption keys,
+// and records both the signature and the KEK version it was made with.
+func (r *Report) Sign(ctx context.Context, src keys.Source) error {
+	kek, err := src.Current(ctx)
+	if err != nil {
+		return fmt.Errorf("gdpr: loading signing key: %w", err)
+	}
+	r.SignedWithKEK = kek.Version
+	r.Signature = sign(kek.Key[:], r.signingPayload())
+	return nil
+}
+
+// Verify reports whether r's signature matches the KEK version it
+// claims to have been signed with.
+func (r *Report) Verify(ctx context.Context, src keys.Source) (bool, error) {
+	if r.Signature == "" {
+		return false, nil
+	}
+	kek, err := src.Version(ctx, r.SignedWithKEK)
+	if err != nil {
+		return false, fmt.Errorf("gdpr: loading verification key: %w", err)
+	}
+
+	want := r.Signature
+	unsigned := *r
+	unsigned.Signature = ""
+	got := sign(kek.Key[:], unsigned.signingPayload())
+	return hmac.Equal([]byte(want), []byte(got)), nil
+}
+
+// signingPayload renders the fields that make up the report's content,
+// excluding the signature itself, into a deterministic string to sign.
+func (r *Report) signingPayload() []byte {
+	payload := fmt.Sprintf(
+		"%s|%s|%d|%v|%v",
+		r.Subject,
+		r.RequestedAt.Format(time.RFC3339Nano),
+		r.PostgresRowsDeleted,
+		r.SegmentsRewritten,
+		r.CacheKeysInvalidated,
+	)
+	return []byte(payload)
+}
+
+func sign(key, payload []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
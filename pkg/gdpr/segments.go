@@ -0,0 +1,117 @@
+package gdpr
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// frame is the minimal shape this package needs from a segment's frames:
+// nothing in this repo yet defines a canonical on-disk frame format (the
+// only frame type, pipeline.FrameSink, hands a whole Segment's path to
+// the ingester and leaves decoding it up to that service), so
+// tombstoning treats a segment as newline-delimited JSON objects and
+// only requires each one to carry a "subject" field. Anything else in
+// the object round-trips unchanged via raw.
+type frame struct {
+	Subject string `json:"subject"`
+}
+
+// tombstoneSegments rewrites every regular file in dir that contains at
+// least one frame belonging to subject, dropping those frames, and
+// returns the names of the segments it rewrote. Segments with no
+// matching frame are left untouched.
+func tombstoneSegments(dir string, subject string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("gdpr: reading %s: %w", dir, err)
+	}
+
+	var rewritten []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		changed, err := tombstoneSegment(path, subject)
+		if err != nil {
+			return rewritten, fmt.Errorf("gdpr: tombstoning %s: %w", entry.Name(), err)
+		}
+		if changed {
+			rewritten = append(rewritten, entry.Name())
+		}
+	}
+	return rewritten, nil
+}
+
+// tombstoneSegment rewrites path in place with subject's frames removed,
+// reporting whether anything was actually dropped.
+func tombstoneSegment(path, subject string) (bool, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tombstone-*")
+	if err != nil {
+		return false, err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once renamed over path below
+
+	changed := false
+	writer := bufio.NewWriter(tmp)
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var f frame
+		if err := json.Unmarshal(line, &f); err != nil {
+			// Not a frame this package understands; keep it verbatim
+			// rather than risk dropping data it can't parse.
+			if _, err := writer.Write(line); err != nil {
+				tmp.Close()
+				return false, err
+			}
+			if err := writer.WriteByte('\n'); err != nil {
+				tmp.Close()
+				return false, err
+			}
+			continue
+		}
+		if f.Subject == subject {
+			changed = true
+			continue
+		}
+		if _, err := writer.Write(line); err != nil {
+			tmp.Close()
+			return false, err
+		}
+		if err := writer.WriteByte('\n'); err != nil {
+			tmp.Close()
+			return false, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+
+	if !changed {
+		return false, nil
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
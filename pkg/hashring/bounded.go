@@ -0,0 +1,54 @@
+package hashring
+
+// RouteBounded is RouteBoundedWithHash using the default hash.
+func RouteBounded(key string, nodes []Node, loads map[string]int, capacityFactor float64) string {
+	return RouteBoundedWithHash(HashFNV1a, "", key, nodes, loads, capacityFactor)
+}
+
+// RouteBoundedWithHash implements consistent hashing with bounded loads
+// (Mirrokni, Thorup, Zadimoghaddam): key prefers its normal HRW node, but
+// if that node is already carrying more than capacityFactor times its
+// fair share of load, the key spills over to the next-highest-scoring
+// node that still has headroom. This keeps any single node from being
+// overwhelmed when the key distribution is skewed, at the cost of some
+// keys not landing on their "natural" node.
+//
+// loads maps node ID to its current load (e.g. in-flight requests or
+// assigned key count); nodes missing from loads are treated as load 0.
+// capacityFactor must be > 1; a typical value is 1.25.
+func RouteBoundedWithHash(hf HashFunc, seed, key string, nodes []Node, loads map[string]int, capacityFactor float64) string {
+	if len(nodes) == 0 {
+		return ""
+	}
+	if capacityFactor <= 1 {
+		capacityFactor = 1.25
+	}
+
+	totalLoad := 0.0
+	totalWeight := 0.0
+	for _, n := range nodes {
+		totalLoad += float64(loads[n.ID])
+		totalWeight += effectiveWeight(n.Weight)
+	}
+	// Fair share scales with weight, same as the expected-count math used
+	// by the distribution subcommand.
+	avgLoadPerWeight := 0.0
+	if totalWeight > 0 {
+		avgLoadPerWeight = totalLoad / totalWeight
+	}
+
+	ranked := TopKWithHash(hf, seed, key, nodes, len(nodes))
+	weightByID := make(map[string]float64, len(nodes))
+	for _, n := range nodes {
+		weightByID[n.ID] = effectiveWeight(n.Weight)
+	}
+
+	for _, id := range ranked {
+		capacity := capacityFactor * avgLoadPerWeight * weightByID[id]
+		if float64(loads[id]) < capacity || capacity == 0 {
+			return id
+		}
+	}
+	// Every node is over capacity; fall back to the plain HRW choice.
+	return ranked[0]
+}
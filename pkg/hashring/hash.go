@@ -0,0 +1,50 @@
+package hashring
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash/crc32"
+	"hash/fnv"
+)
+
+// HashFunc selects which underlying hash family Route/TopK/Jump/Maglev use
+// to score a (key, node) pair. The default, HashFNV1a, is fast and has
+// good enough distribution for routing; the others exist for callers with
+// specific compatibility or cryptographic-strength requirements.
+type HashFunc int
+
+const (
+	HashFNV1a HashFunc = iota
+	HashCRC32
+	HashSHA256
+)
+
+// sum64 hashes seed and parts together under hf, returning a 64-bit digest.
+// seed lets callers run an independent hash ring (e.g. per shard or per
+// test) without the node/key sets colliding with another ring's layout.
+func sum64(hf HashFunc, seed string, parts ...[]byte) uint64 {
+	switch hf {
+	case HashCRC32:
+		table := crc32.MakeTable(crc32.Castagnoli)
+		crc := crc32.Checksum([]byte(seed), table)
+		for _, p := range parts {
+			crc ^= crc32.Checksum(p, table)
+		}
+		return uint64(crc)
+	case HashSHA256:
+		h := sha256.New()
+		h.Write([]byte(seed))
+		for _, p := range parts {
+			h.Write(p)
+		}
+		sum := h.Sum(nil)
+		return binary.BigEndian.Uint64(sum[:8])
+	default: // HashFNV1a
+		h := fnv.New64a()
+		h.Write([]byte(seed))
+		for _, p := range parts {
+			h.Write(p)
+		}
+		return h.Sum64()
+	}
+}
@@ -0,0 +1,279 @@
+// Package hashring implements Highest Random Weight (rendezvous) hashing
+// for routing keys to a set of nodes. It is the single source of truth for
+// routing decisions shared by the hrw CLI and the grpc-router service, so
+// the two never diverge on how a key maps to a backend.
+package hashring
+
+import (
+	"math"
+	"sort"
+)
+
+// Node is a routing target with an optional weight. A zero Weight is
+// treated as 1.0 (equal share).
+type Node struct {
+	ID     string
+	Weight float64
+	Zone   string // optional failure domain, e.g. availability zone
+}
+
+func effectiveWeight(w float64) float64 {
+	if w <= 0 {
+		return 1.0
+	}
+	return w
+}
+
+// score computes the HRW score of node for key under hf/seed: higher wins.
+// It combines a deterministic hash of (key, node ID) with the node's
+// weight so that heavier nodes win ties more often without destabilizing
+// the ranking of unrelated keys (the core HRW property).
+func score(hf HashFunc, seed, key string, n Node) float64 {
+	sum := sum64(hf, seed, []byte(key), []byte{0}, []byte(n.ID))
+
+	// Map the hash into (0,1) and feed it through -1/ln(x) so that the
+	// weight scales the score multiplicatively, per the standard weighted
+	// rendezvous hashing construction.
+	x := float64(sum) / float64(math.MaxUint64)
+	if x <= 0 {
+		x = math.SmallestNonzeroFloat64
+	}
+	return effectiveWeight(n.Weight) * (-1 / math.Log(x))
+}
+
+// Route returns the ID of the node that key hashes to. It returns "" if
+// nodes is empty. It uses the default hash (HashFNV1a, no seed); use
+// RouteWithHash to select a different hash function or seed.
+func Route(key string, nodes []Node) string {
+	return RouteWithHash(HashFNV1a, "", key, nodes)
+}
+
+// RouteWithHash is Route with an explicit hash function and seed.
+func RouteWithHash(hf HashFunc, seed, key string, nodes []Node) string {
+	best := ""
+	bestScore := math.Inf(-1)
+	for _, n := range nodes {
+		if s := score(hf, seed, key, n); s > bestScore {
+			bestScore = s
+			best = n.ID
+		}
+	}
+	return best
+}
+
+// RouteStrings is a convenience wrapper for unweighted node IDs.
+func RouteStrings(key string, nodeIDs []string) string {
+	return Route(key, toNodes(nodeIDs))
+}
+
+// TopK returns the k highest-scoring node IDs for key, in descending order
+// of score. If k is greater than len(nodes), all nodes are returned. It
+// uses the default hash; use TopKWithHash to select a different one.
+func TopK(key string, nodes []Node, k int) []string {
+	return TopKWithHash(HashFNV1a, "", key, nodes, k)
+}
+
+// TopKWithHash is TopK with an explicit hash function and seed.
+func TopKWithHash(hf HashFunc, seed, key string, nodes []Node, k int) []string {
+	type scored struct {
+		id string
+		s  float64
+	}
+	ranked := make([]scored, len(nodes))
+	for i, n := range nodes {
+		ranked[i] = scored{id: n.ID, s: score(hf, seed, key, n)}
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].s > ranked[j].s })
+
+	if k > len(ranked) {
+		k = len(ranked)
+	}
+	out := make([]string, k)
+	for i := 0; i < k; i++ {
+		out[i] = ranked[i].id
+	}
+	return out
+}
+
+// TopKZoneAware returns up to k node IDs for key, preferring the
+// highest-scoring node from each distinct zone before repeating a zone, so
+// replicas spread across failure domains instead of landing on the same
+// zone by chance of the hash. Nodes with an empty Zone are each treated as
+// their own singleton zone. If k exceeds the number of distinct zones, the
+// remaining picks fall back to the next-highest score regardless of zone.
+func TopKZoneAware(key string, nodes []Node, k int) []string {
+	type scored struct {
+		id   string
+		zone string
+		s    float64
+	}
+	ranked := make([]scored, len(nodes))
+	for i, n := range nodes {
+		zone := n.Zone
+		if zone == "" {
+			zone = n.ID
+		}
+		ranked[i] = scored{id: n.ID, zone: zone, s: score(HashFNV1a, "", key, n)}
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].s > ranked[j].s })
+
+	if k > len(ranked) {
+		k = len(ranked)
+	}
+
+	out := make([]string, 0, k)
+	usedZones := map[string]bool{}
+	usedIDs := map[string]bool{}
+
+	// First pass: best node per unused zone, in score order.
+	for _, r := range ranked {
+		if len(out) == k {
+			return out
+		}
+		if usedZones[r.zone] {
+			continue
+		}
+		usedZones[r.zone] = true
+		usedIDs[r.id] = true
+		out = append(out, r.id)
+	}
+
+	// Second pass: zones exhausted, fill remaining slots by score.
+	for _, r := range ranked {
+		if len(out) == k {
+			break
+		}
+		if usedIDs[r.id] {
+			continue
+		}
+		usedIDs[r.id] = true
+		out = append(out, r.id)
+	}
+	return out
+}
+
+// TopKStrings is the unweighted convenience form of TopK.
+func TopKStrings(key string, nodeIDs []string, k int) []string {
+	return TopK(key, toNodes(nodeIDs), k)
+}
+
+// Jump implements Google's "jump consistent hash" algorithm, mapping key
+// to a bucket in [0, numBuckets). Unlike Route, buckets are identified by
+// index rather than by ID: it uses O(1) memory and provably balances keys
+// across buckets, at the cost of only supporting append/remove at the end
+// of the bucket list (removing an arbitrary node reshuffles everything
+// after it).
+func Jump(key string, numBuckets int) int {
+	return JumpWithHash(HashFNV1a, "", key, numBuckets)
+}
+
+// JumpWithHash is Jump with an explicit hash function and seed.
+func JumpWithHash(hf HashFunc, seed, key string, numBuckets int) int {
+	if numBuckets <= 0 {
+		return -1
+	}
+
+	hashVal := sum64(hf, seed, []byte(key))
+
+	var b, j int64 = -1, 0
+	for j < int64(numBuckets) {
+		b = j
+		hashVal = hashVal*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((hashVal>>33)+1)))
+	}
+	return int(b)
+}
+
+// MaglevTable is a precomputed lookup table mapping hash buckets to node
+// IDs, per Google's Maglev paper. Once built, Lookup is an O(1) array
+// index, making it suitable for high-QPS routing where the cost of HRW's
+// O(nodes) scan per key is undesirable. Rebuilding is relatively cheap but
+// not free, so tables are built once per node-set change, not per key.
+type MaglevTable struct {
+	entries []string // len == table size; entries[i] is a node ID
+	size    int
+	hf      HashFunc
+	seed    string
+}
+
+// BuildMaglevTable constructs a lookup table of the given size (should be
+// prime for the best balance; callers are responsible for choosing one)
+// for nodes, using the default hash. Equal-weight nodes receive an
+// (almost) equal number of table entries.
+func BuildMaglevTable(nodes []Node, size int) *MaglevTable {
+	return BuildMaglevTableWithHash(HashFNV1a, "", nodes, size)
+}
+
+// BuildMaglevTableWithHash is BuildMaglevTable with an explicit hash
+// function and seed; Lookup on the returned table reuses the same hash.
+func BuildMaglevTableWithHash(hf HashFunc, seed string, nodes []Node, size int) *MaglevTable {
+	t := &MaglevTable{entries: make([]string, size), size: size, hf: hf, seed: seed}
+	if len(nodes) == 0 || size <= 0 {
+		return t
+	}
+
+	permutation := make([][]int, len(nodes))
+	next := make([]int, len(nodes))
+	for i, n := range nodes {
+		offset, skip := maglevOffsetAndSkip(hf, seed, n.ID, size)
+		perm := make([]int, size)
+		for j := 0; j < size; j++ {
+			perm[j] = (offset + j*skip) % size
+		}
+		permutation[i] = perm
+	}
+
+	for i := range t.entries {
+		t.entries[i] = ""
+	}
+
+	filled := 0
+	for filled < size {
+		for i, n := range nodes {
+			c := permutation[i][next[i]]
+			for t.entries[c] != "" {
+				next[i]++
+				c = permutation[i][next[i]]
+			}
+			t.entries[c] = n.ID
+			next[i]++
+			filled++
+			if filled == size {
+				break
+			}
+		}
+	}
+	return t
+}
+
+func maglevOffsetAndSkip(hf HashFunc, seed, nodeID string, size int) (offset, skip int) {
+	offset = int(sum64(hf, seed, []byte(nodeID), []byte("-offset")) % uint64(size))
+	skip = int(sum64(hf, seed, []byte(nodeID), []byte("-skip"))%uint64(size-1)) + 1
+	return offset, skip
+}
+
+// Lookup returns the node ID assigned to key's bucket, or "" for an empty
+// table.
+func (t *MaglevTable) Lookup(key string) string {
+	if t == nil || t.size == 0 {
+		return ""
+	}
+	idx := int(sum64(t.hf, t.seed, []byte(key)) % uint64(t.size))
+	return t.entries[idx]
+}
+
+// Entries returns a copy of the raw bucket->node assignments, e.g. for
+// JSON export.
+func (t *MaglevTable) Entries() []string {
+	out := make([]string, len(t.entries))
+	copy(out, t.entries)
+	return out
+}
+
+func toNodes(ids []string) []Node {
+	nodes := make([]Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = Node{ID: id, Weight: 1}
+	}
+	return nodes
+}
@@ -0,0 +1,69 @@
+// Package hotcache lets a replica that keeps an in-memory cache of hot
+// per-key vector search results warm that cache from a healthy peer
+// before it starts taking traffic, instead of serving every request
+// cold right after a scale-out. grpc-router is the first user: each
+// replica holds its own Store, and a newly started one streams a
+// Snapshot off an existing replica over HTTP before it marks itself
+// ready for the load balancer.
+package hotcache
+
+import "sync"
+
+// Entry is a single cached routing decision: the key it was computed
+// for, the vector it was scored against (if the caller wants to avoid
+// recomputing it), and the resulting score.
+type Entry struct {
+	Key    string    `json:"key"`
+	Vector []float32 `json:"vector,omitempty"`
+	Score  float64   `json:"score"`
+}
+
+// Store is a replica's local hot cache.
+type Store interface {
+	// Put records or overwrites the entry for e.Key.
+	Put(e Entry)
+	// Snapshot returns every entry currently cached, for streaming to
+	// a peer that's warming up.
+	Snapshot() []Entry
+	// Load merges entries into the store, as received from a peer's
+	// Snapshot.
+	Load(entries []Entry)
+}
+
+// MemStore is an in-memory Store guarded by a mutex.
+type MemStore struct {
+	mu      sync.RWMutex
+	entries map[string]Entry
+}
+
+// NewMemStore returns an empty MemStore ready for use.
+func NewMemStore() *MemStore {
+	return &MemStore{entries: make(map[string]Entry)}
+}
+
+// Put implements Store.
+func (s *MemStore) Put(e Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[e.Key] = e
+}
+
+// Snapshot implements Store.
+func (s *MemStore) Snapshot() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		out = append(out, e)
+	}
+	return out
+}
+
+// Load implements Store.
+func (s *MemStore) Load(entries []Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range entries {
+		s.entries[e.Key] = e
+	}
+}
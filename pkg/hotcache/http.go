@@ -0,0 +1,65 @@
+package hotcache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Handler serves store's current snapshot as newline-delimited JSON, so
+// a warming-up peer can start loading entries as they arrive instead of
+// waiting for the whole cache to be buffered and marshaled up front.
+func Handler(store Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		flusher, _ := w.(http.Flusher)
+		for _, e := range store.Snapshot() {
+			if err := enc.Encode(e); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	})
+}
+
+// FetchSnapshot streams peerAddr's hotcache snapshot over HTTP and loads
+// every entry into store, returning how many it loaded. peerAddr is the
+// peer's base URL, e.g. "http://router-1:8081".
+func FetchSnapshot(ctx context.Context, peerAddr string, store Store) (int, error) {
+	url := strings.TrimRight(peerAddr, "/") + "/hotcache/snapshot"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("hotcache: building request to %s: %w", peerAddr, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("hotcache: fetching snapshot from %s: %w", peerAddr, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("hotcache: fetching snapshot from %s: status %d", peerAddr, resp.StatusCode)
+	}
+
+	var entries []Entry
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var e Entry
+		if err := dec.Decode(&e); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return len(entries), fmt.Errorf("hotcache: decoding snapshot from %s: %w", peerAddr, err)
+		}
+		entries = append(entries, e)
+	}
+
+	store.Load(entries)
+	return len(entries), nil
+}
@@ -0,0 +1,40 @@
+package keys
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+// EnvSource loads a single KEK (version 1, no rotation) from a
+// base64-encoded environment variable. It's meant for local development
+// and tests, where FileSource's rotation machinery is unnecessary
+// ceremony.
+type EnvSource struct {
+	Var string
+}
+
+// Current implements Source.
+func (s EnvSource) Current(ctx context.Context) (KEK, error) {
+	return s.Version(ctx, 1)
+}
+
+// Version implements Source; only version 1 exists.
+func (s EnvSource) Version(ctx context.Context, version int) (KEK, error) {
+	if version != 1 {
+		return KEK{}, fmt.Errorf("keys: EnvSource only has version 1, got %d", version)
+	}
+	raw, ok := os.LookupEnv(s.Var)
+	if !ok {
+		return KEK{}, fmt.Errorf("keys: environment variable %s is not set", s.Var)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(decoded) != 32 {
+		return KEK{}, fmt.Errorf("keys: %s must be a base64-encoded 32-byte key", s.Var)
+	}
+	var key KEK
+	key.Version = 1
+	copy(key.Key[:], decoded)
+	return key, nil
+}
@@ -0,0 +1,134 @@
+// Package keys provides envelope encryption for data at rest (starting
+// with logservice segment encryption): a data encryption key (DEK)
+// encrypts the payload with AES-256-GCM, and the DEK itself is wrapped
+// with a versioned key encryption key (KEK) loaded from a Source. Key
+// versions let the KEK be rotated without re-encrypting every existing
+// payload — old payloads keep the KEK version they were wrapped with.
+package keys
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+)
+
+// KEK is a key encryption key at a specific version.
+type KEK struct {
+	Version int
+	Key     [32]byte // AES-256
+}
+
+// Source resolves the current KEK and any KEK by version (needed to
+// unwrap payloads encrypted under an older version after rotation).
+type Source interface {
+	Current(ctx context.Context) (KEK, error)
+	Version(ctx context.Context, version int) (KEK, error)
+}
+
+// Envelope is a payload encrypted with a one-time DEK, itself wrapped by
+// a KEK. KEKVersion records which KEK version wrapped it, so Open can
+// fetch the right one even after rotation.
+type Envelope struct {
+	KEKVersion int
+	WrappedDEK []byte
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+// Seal generates a fresh DEK, encrypts plaintext with it, wraps the DEK
+// with src's current KEK, and returns the resulting Envelope.
+func Seal(ctx context.Context, src Source, plaintext []byte) (Envelope, error) {
+	kek, err := src.Current(ctx)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("keys: loading current KEK: %w", err)
+	}
+
+	var dek [32]byte
+	if _, err := rand.Read(dek[:]); err != nil {
+		return Envelope{}, fmt.Errorf("keys: generating DEK: %w", err)
+	}
+
+	ciphertext, nonce, err := encrypt(dek[:], plaintext)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("keys: encrypting payload: %w", err)
+	}
+
+	wrappedDEK, wrapNonce, err := encrypt(kek.Key[:], dek[:])
+	if err != nil {
+		return Envelope{}, fmt.Errorf("keys: wrapping DEK: %w", err)
+	}
+
+	return Envelope{
+		KEKVersion: kek.Version,
+		WrappedDEK: append(wrapNonce, wrappedDEK...),
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+// Open unwraps env's DEK using the KEK version it was sealed with, then
+// decrypts its ciphertext.
+func Open(ctx context.Context, src Source, env Envelope) ([]byte, error) {
+	kek, err := src.Version(ctx, env.KEKVersion)
+	if err != nil {
+		return nil, fmt.Errorf("keys: loading KEK version %d: %w", env.KEKVersion, err)
+	}
+
+	nonceSize, err := gcmNonceSize()
+	if err != nil {
+		return nil, err
+	}
+	if len(env.WrappedDEK) < nonceSize {
+		return nil, fmt.Errorf("keys: wrapped DEK too short")
+	}
+	wrapNonce, wrappedDEK := env.WrappedDEK[:nonceSize], env.WrappedDEK[nonceSize:]
+
+	dek, err := decrypt(kek.Key[:], wrapNonce, wrappedDEK)
+	if err != nil {
+		return nil, fmt.Errorf("keys: unwrapping DEK: %w", err)
+	}
+
+	return decrypt(dek, env.Nonce, env.Ciphertext)
+}
+
+func encrypt(key, plaintext []byte) (ciphertext, nonce []byte, err error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return gcm.Seal(nil, nonce, plaintext, nil), nonce, nil
+}
+
+func decrypt(key, nonce, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func gcmNonceSize() (int, error) {
+	block, err := aes.NewCipher(make([]byte, 32))
+	if err != nil {
+		return 0, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return 0, err
+	}
+	return gcm.NonceSize(), nil
+}
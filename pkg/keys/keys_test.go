@@ -0,0 +1,123 @@
+package keys
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"testing"
+)
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	var kek [32]byte
+	rand.Read(kek[:])
+	src := staticSource{kek: KEK{Version: 1, Key: kek}}
+
+	plaintext := []byte("hello envelope encryption")
+	env, err := Seal(context.Background(), src, plaintext)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	got, err := Open(context.Background(), src, env)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if string(got) != string(plaintext) {
+		t.Fatalf("Open = %q, want %q", got, plaintext)
+	}
+}
+
+func TestOpenAfterRotationUsesOriginalKEKVersion(t *testing.T) {
+	var kekV1, kekV2 [32]byte
+	rand.Read(kekV1[:])
+	rand.Read(kekV2[:])
+	src := versionedSource{versions: map[int]KEK{
+		1: {Version: 1, Key: kekV1},
+		2: {Version: 2, Key: kekV2},
+	}, current: 1}
+
+	env, err := Seal(context.Background(), src, []byte("sealed under v1"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	src.current = 2 // rotate
+
+	got, err := Open(context.Background(), src, env)
+	if err != nil {
+		t.Fatalf("Open after rotation: %v", err)
+	}
+	if string(got) != "sealed under v1" {
+		t.Fatalf("Open = %q, want original plaintext", got)
+	}
+}
+
+func TestOpenWrongKEKVersionFails(t *testing.T) {
+	var kekV1, kekV2 [32]byte
+	rand.Read(kekV1[:])
+	rand.Read(kekV2[:])
+	src := versionedSource{versions: map[int]KEK{
+		1: {Version: 1, Key: kekV1},
+		2: {Version: 2, Key: kekV2},
+	}, current: 1}
+
+	env, err := Seal(context.Background(), src, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	env.KEKVersion = 2 // tampered/wrong version
+
+	if _, err := Open(context.Background(), src, env); err == nil {
+		t.Fatal("Open with wrong KEK version succeeded, want error")
+	}
+}
+
+func TestFileSourceCurrentRaceWithRotate(t *testing.T) {
+	src := &FileSource{
+		Path:     t.TempDir() + "/keys.json",
+		current:  1,
+		versions: map[int][32]byte{1: {}},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			src.Current(context.Background())
+		}()
+		go func() {
+			defer wg.Done()
+			var newKey [32]byte
+			rand.Read(newKey[:])
+			src.Rotate(newKey)
+		}()
+	}
+	wg.Wait()
+}
+
+type staticSource struct {
+	kek KEK
+}
+
+func (s staticSource) Current(ctx context.Context) (KEK, error) { return s.kek, nil }
+func (s staticSource) Version(ctx context.Context, version int) (KEK, error) {
+	return s.kek, nil
+}
+
+type versionedSource struct {
+	versions map[int]KEK
+	current  int
+}
+
+func (s versionedSource) Current(ctx context.Context) (KEK, error) {
+	return s.Version(ctx, s.current)
+}
+
+func (s versionedSource) Version(ctx context.Context, version int) (KEK, error) {
+	kek, ok := s.versions[version]
+	if !ok {
+		return KEK{}, context.DeadlineExceeded
+	}
+	return kek, nil
+}
@@ -0,0 +1,26 @@
+package keys
+
+import (
+	"context"
+	"fmt"
+)
+
+// KMSSource is a Source backed by a cloud KMS. It is not implemented:
+// this repo has no way to fetch a cloud provider's SDK offline, and each
+// provider's envelope-unwrap API differs enough that filling this in
+// with a real client needs to happen alongside picking that provider.
+// Current and Version both return an error so a misconfiguration fails
+// loudly instead of silently falling back to a weaker Source.
+type KMSSource struct {
+	KeyID string
+}
+
+// Current implements Source.
+func (s KMSSource) Current(ctx context.Context) (KEK, error) {
+	return KEK{}, fmt.Errorf("keys: KMSSource for key %s is not implemented yet; use FileSource or EnvSource", s.KeyID)
+}
+
+// Version implements Source.
+func (s KMSSource) Version(ctx context.Context, version int) (KEK, error) {
+	return KEK{}, fmt.Errorf("keys: KMSSource for key %s is not implemented yet; use FileSource or EnvSource", s.KeyID)
+}
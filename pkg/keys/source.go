@@ -0,0 +1,99 @@
+package keys
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileSource loads a versioned set of KEKs from a JSON file of the form
+// {"current": N, "versions": {"1": "<base64>", "2": "<base64>"}}.
+// Rotate updates the file with a freshly generated version and makes it
+// current.
+type FileSource struct {
+	Path string
+
+	mu       sync.Mutex
+	current  int
+	versions map[int][32]byte
+}
+
+type fileSourceDoc struct {
+	Current  int               `json:"current"`
+	Versions map[string]string `json:"versions"`
+}
+
+// LoadFileSource reads path and returns a ready-to-use FileSource.
+func LoadFileSource(path string) (*FileSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("keys: reading %s: %w", path, err)
+	}
+
+	var doc fileSourceDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("keys: parsing %s: %w", path, err)
+	}
+
+	versions := make(map[int][32]byte, len(doc.Versions))
+	for vs, encoded := range doc.Versions {
+		var v int
+		if _, err := fmt.Sscanf(vs, "%d", &v); err != nil {
+			return nil, fmt.Errorf("keys: invalid version %q in %s", vs, path)
+		}
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("keys: invalid key for version %d in %s", v, path)
+		}
+		var key [32]byte
+		copy(key[:], raw)
+		versions[v] = key
+	}
+
+	return &FileSource{Path: path, current: doc.Current, versions: versions}, nil
+}
+
+// Current implements Source.
+func (s *FileSource) Current(ctx context.Context) (KEK, error) {
+	s.mu.Lock()
+	current := s.current
+	s.mu.Unlock()
+	return s.Version(ctx, current)
+}
+
+// Version implements Source.
+func (s *FileSource) Version(ctx context.Context, version int) (KEK, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.versions[version]
+	if !ok {
+		return KEK{}, fmt.Errorf("keys: no KEK at version %d", version)
+	}
+	return KEK{Version: version, Key: key}, nil
+}
+
+// Rotate generates a fresh KEK, adds it as the newest version, makes it
+// current, and persists the result to s.Path. Existing payloads sealed
+// under older versions remain openable since their versions are kept.
+func (s *FileSource) Rotate(newKey [32]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nextVersion := s.current + 1
+	s.versions[nextVersion] = newKey
+	s.current = nextVersion
+
+	doc := fileSourceDoc{Current: s.current, Versions: map[string]string{}}
+	for v, key := range s.versions {
+		doc.Versions[fmt.Sprintf("%d", v)] = base64.StdEncoding.EncodeToString(key[:])
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0o600)
+}
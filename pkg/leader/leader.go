@@ -0,0 +1,77 @@
+// Package leader provides leader election so that when multiple replicas
+// of a service run background jobs (retention, compaction, purge,
+// reindex), only one replica actually executes them at a time.
+package leader
+
+import (
+	"context"
+	"time"
+)
+
+// Elector tries to become leader and to renew that lease while holding
+// it. Implementations (RedisElector) use a mechanism where only one
+// caller can hold the lease at a time.
+type Elector interface {
+	// TryAcquire attempts to become leader, returning true if this
+	// call won the election.
+	TryAcquire(ctx context.Context) (bool, error)
+	// Renew extends the current lease. It must only be called while
+	// this process believes it is leader.
+	Renew(ctx context.Context) (bool, error)
+	// Release gives up leadership early, e.g. on graceful shutdown.
+	Release(ctx context.Context) error
+}
+
+// Run calls onElected when e becomes leader and blocks, renewing the
+// lease every renewInterval, until either ctx is done or a Renew call
+// fails (meaning leadership was lost), at which point onElected's
+// context is canceled. Run retries TryAcquire every retryInterval while
+// not leader. It returns when ctx is done.
+func Run(ctx context.Context, e Elector, retryInterval, renewInterval time.Duration, onElected func(leaderCtx context.Context)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		acquired, err := e.TryAcquire(ctx)
+		if err != nil || !acquired {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+				continue
+			}
+		}
+
+		leaderCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			onElected(leaderCtx)
+		}()
+
+		holdLease(ctx, e, renewInterval, cancel)
+		<-done
+	}
+}
+
+// holdLease renews e's lease every interval until ctx is done or a Renew
+// call reports the lease was lost, at which point cancel is called.
+func holdLease(ctx context.Context, e Elector, interval time.Duration, cancel context.CancelFunc) {
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := e.Renew(ctx)
+			if err != nil || !ok {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package leader
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/respwire"
+)
+
+// RedisElector elects a leader with SET key token NX EX ttl: only the
+// first caller to set an unset key wins, and holds the lease by
+// refreshing the TTL on its own token until it stops renewing or another
+// caller's key expires and takes over.
+type RedisElector struct {
+	Addr    string
+	Key     string
+	TTL     time.Duration
+	Timeout time.Duration
+
+	token string
+}
+
+func (e *RedisElector) dial() (net.Conn, error) {
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return net.DialTimeout("tcp", e.Addr, timeout)
+}
+
+// TryAcquire implements Elector.
+func (e *RedisElector) TryAcquire(ctx context.Context) (bool, error) {
+	token, err := randomToken()
+	if err != nil {
+		return false, err
+	}
+
+	conn, err := e.dial()
+	if err != nil {
+		return false, fmt.Errorf("leader: dial redis %s: %w", e.Addr, err)
+	}
+	defer conn.Close()
+
+	seconds := strconv.Itoa(ttlSeconds(e.TTL))
+	cmd := respwire.EncodeCommand("SET", e.Key, token, "NX", "EX", seconds)
+	if _, err := conn.Write(cmd); err != nil {
+		return false, fmt.Errorf("leader: write SET NX: %w", err)
+	}
+
+	_, err = respwire.ReadSimpleString(bufio.NewReader(conn))
+	if err != nil {
+		// A nil bulk reply ("$-1") means the key already existed;
+		// respwire.ReadSimpleString errors on anything that isn't
+		// a simple string, which covers that case.
+		return false, nil
+	}
+
+	e.token = token
+	return true, nil
+}
+
+// renewScript atomically renews the lease in a single round trip: GET
+// and EXPIRE over two separate commands leaves a window between them
+// where this elector's lease can expire, another replica can acquire
+// the key under a new token, and this elector's EXPIRE would then
+// refresh that *other* replica's key out from under it, leaving two
+// electors believing they hold the lease. EVAL runs the check and the
+// act as one atomic operation on the server, closing that window.
+const renewScript = `if redis.call('get', KEYS[1]) == ARGV[1] then return redis.call('expire', KEYS[1], ARGV[2]) else return 0 end`
+
+// releaseScript is renewScript's DEL counterpart, for the same reason:
+// a GET-then-DEL over two round trips can delete a different replica's
+// key if this elector's lease expired and was reacquired in between.
+const releaseScript = `if redis.call('get', KEYS[1]) == ARGV[1] then return redis.call('del', KEYS[1]) else return 0 end`
+
+// Renew implements Elector by atomically checking this elector's token
+// is still the one holding the key and, if so, resetting its TTL, via a
+// single EVAL rather than a GET followed by a separate EXPIRE (see
+// renewScript). Renewal fails (returns false, nil) if another elector's
+// token has since taken over the key.
+func (e *RedisElector) Renew(ctx context.Context) (bool, error) {
+	seconds := strconv.Itoa(ttlSeconds(e.TTL))
+	count, err := e.evalCheckAndAct(renewScript, seconds)
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+// Release implements Elector by atomically deleting the key, but only
+// if this elector's token still holds it, via a single EVAL rather than
+// a GET followed by a separate DEL (see releaseScript).
+func (e *RedisElector) Release(ctx context.Context) error {
+	_, err := e.evalCheckAndAct(releaseScript, "")
+	return err
+}
+
+// evalCheckAndAct runs script (renewScript or releaseScript) with KEYS[1]
+// = e.Key, ARGV[1] = e.token, and, if extraArg is non-empty, ARGV[2] =
+// extraArg (renewScript's TTL in seconds; releaseScript takes no second
+// argument), returning the integer the script returns.
+func (e *RedisElector) evalCheckAndAct(script, extraArg string) (int64, error) {
+	conn, err := e.dial()
+	if err != nil {
+		return 0, fmt.Errorf("leader: dial redis %s: %w", e.Addr, err)
+	}
+	defer conn.Close()
+
+	args := []string{"EVAL", script, "1", e.Key, e.token}
+	if extraArg != "" {
+		args = append(args, extraArg)
+	}
+	if _, err := conn.Write(respwire.EncodeCommand(args...)); err != nil {
+		return 0, fmt.Errorf("leader: write EVAL: %w", err)
+	}
+	reply, err := respwire.ReadReply(bufio.NewReader(conn))
+	if err != nil {
+		return 0, fmt.Errorf("leader: read EVAL reply: %w", err)
+	}
+	count, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("leader: unexpected EVAL reply: %#v", reply)
+	}
+	return count, nil
+}
+
+func ttlSeconds(ttl time.Duration) int {
+	seconds := int(ttl.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+func randomToken() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,33 @@
+package loadgen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// WriteReport prints a per-step summary of requests, errors, throughput,
+// and latency percentiles to w.
+func WriteReport(w io.Writer, duration time.Duration, results []*StepResult) {
+	for _, r := range results {
+		latencies := append([]time.Duration(nil), r.Latencies...)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		throughput := float64(r.Requests) / duration.Seconds()
+		fmt.Fprintf(w, "%-16s requests=%-8d errors=%-6d throughput=%.1f/s p50=%s p99=%s\n",
+			r.Method, r.Requests, r.Errors, throughput,
+			percentile(latencies, 0.50), percentile(latencies, 0.99))
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)))
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,97 @@
+package loadgen
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Call is the method a Step invokes, supplied by the caller (cmd/loadgen
+// wires each scenario method name to a real service call).
+type Call func(ctx context.Context, params map[string]string) error
+
+// StepResult accumulates a Step's outcome over a run.
+type StepResult struct {
+	Method    string
+	Requests  int64
+	Errors    int64
+	Latencies []time.Duration
+
+	mu sync.Mutex
+}
+
+func (r *StepResult) record(d time.Duration, err error) {
+	atomic.AddInt64(&r.Requests, 1)
+	if err != nil {
+		atomic.AddInt64(&r.Errors, 1)
+		return
+	}
+	r.mu.Lock()
+	r.Latencies = append(r.Latencies, d)
+	r.mu.Unlock()
+}
+
+// Run drives every Step in s.Mix concurrently for s.Duration, calling
+// calls[step.Method] at step.Rate requests/sec, and returns one
+// StepResult per step. onTick, if non-nil, is called roughly once a
+// second with the in-progress results for live reporting.
+func Run(ctx context.Context, s Scenario, calls map[string]Call, onTick func([]*StepResult)) []*StepResult {
+	ctx, cancel := context.WithTimeout(ctx, s.Duration)
+	defer cancel()
+
+	results := make([]*StepResult, len(s.Mix))
+	var wg sync.WaitGroup
+	for i, step := range s.Mix {
+		result := &StepResult{Method: step.Method}
+		results[i] = result
+
+		call, ok := calls[step.Method]
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go driveStep(ctx, &wg, step, call, result)
+	}
+
+	if onTick != nil {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		done := make(chan struct{})
+		go func() { wg.Wait(); close(done) }()
+		for {
+			select {
+			case <-done:
+				return results
+			case <-ticker.C:
+				onTick(results)
+			}
+		}
+	}
+
+	wg.Wait()
+	return results
+}
+
+func driveStep(ctx context.Context, wg *sync.WaitGroup, step Step, call Call, result *StepResult) {
+	defer wg.Done()
+
+	if step.Rate <= 0 {
+		return
+	}
+	interval := time.Duration(float64(time.Second) / step.Rate)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			err := call(ctx, step.Params)
+			result.record(time.Since(start), err)
+		}
+	}
+}
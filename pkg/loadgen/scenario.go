@@ -0,0 +1,132 @@
+// Package loadgen drives sustained, mixed-method load against the
+// services in this repo from a scenario file describing which methods to
+// call, at what rate, and with what payload shape.
+package loadgen
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Step is one method in a scenario's mix: call Method at Rate requests
+// per second, with Params carrying method-specific settings like
+// payload_size or k.
+type Step struct {
+	Method string
+	Rate   float64
+	Params map[string]string
+}
+
+// Scenario is a parsed loadgen config.
+type Scenario struct {
+	Duration time.Duration
+	Mix      []Step
+}
+
+// LoadScenario reads and parses the scenario file at path.
+//
+// Scenario files use a small subset of YAML (top-level scalar keys and a
+// "mix" list of flat maps) rather than full YAML, since this repo has no
+// way to fetch a YAML library offline; the subset below is what a
+// mostly-flat load-test config needs.
+func LoadScenario(path string) (Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("loadgen: reading %s: %w", path, err)
+	}
+	return parseScenario(string(data))
+}
+
+func parseScenario(text string) (Scenario, error) {
+	var s Scenario
+	var currentStep *Step
+
+	for _, rawLine := range strings.Split(text, "\n") {
+		line := stripComment(rawLine)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := countIndent(line)
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "mix:":
+			continue
+
+		case strings.HasPrefix(trimmed, "- "):
+			if currentStep != nil {
+				s.Mix = append(s.Mix, *currentStep)
+			}
+			currentStep = &Step{Params: map[string]string{}}
+			if err := applyField(currentStep, strings.TrimPrefix(trimmed, "- ")); err != nil {
+				return Scenario{}, err
+			}
+
+		case indent > 0 && currentStep != nil:
+			if err := applyField(currentStep, trimmed); err != nil {
+				return Scenario{}, err
+			}
+
+		default:
+			key, value, ok := strings.Cut(trimmed, ":")
+			if !ok {
+				return Scenario{}, fmt.Errorf("loadgen: malformed line %q", trimmed)
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if key == "duration" {
+				d, err := time.ParseDuration(value)
+				if err != nil {
+					return Scenario{}, fmt.Errorf("loadgen: duration: %w", err)
+				}
+				s.Duration = d
+			}
+		}
+	}
+	if currentStep != nil {
+		s.Mix = append(s.Mix, *currentStep)
+	}
+	return s, nil
+}
+
+func applyField(step *Step, field string) error {
+	key, value, ok := strings.Cut(field, ":")
+	if !ok {
+		return fmt.Errorf("loadgen: malformed mix entry field %q", field)
+	}
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+	switch key {
+	case "method":
+		step.Method = value
+	case "rate":
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("loadgen: rate: %w", err)
+		}
+		step.Rate = rate
+	default:
+		step.Params[key] = value
+	}
+	return nil
+}
+
+func stripComment(line string) string {
+	if i := strings.Index(line, "#"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+func countIndent(line string) int {
+	n := 0
+	for _, c := range line {
+		if c != ' ' {
+			break
+		}
+		n++
+	}
+	return n
+}
@@ -0,0 +1,463 @@
+// Package logclient wraps pkg/logservice.WriteStreamHandler's WebSocket
+// write path with buffering, automatic reconnection, local sequence
+// assignment, and flush-on-close, so a caller appending a long-lived
+// stream of frames doesn't hand-roll that logic itself.
+//
+// The request asks this to wrap "generated stubs"; LogService has none
+// to wrap (see pkg/logservice's package doc on why every "Service" here
+// is JSON over HTTP/WebSocket instead of gRPC), and pkg/wswire — the
+// WebSocket implementation WriteStreamHandler is built on — only
+// implements the server half of RFC 6455 (see its package doc). dial.go
+// hand-rolls just enough of the client side to open a connection, the
+// same narrow-scope-to-what's-needed approach pkg/wswire and
+// pkg/respwire already take for their own protocols, and Client wraps
+// that the way pkg/client.LogServiceClient wraps LogService's HTTP
+// routes.
+//
+// WriteStreamHandler also has no fixed mount point yet (see
+// pkg/logservice/tls.go's doc comment on its "zero call site" caveat);
+// Client assumes the binary embedding it mounts WriteStreamHandler at
+// writeStreamPath, the same assumption a future caller will need to
+// satisfy either way.
+package logclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/logservice"
+)
+
+// writeStreamPath is the route Client assumes the embedding binary
+// mounts pkg/logservice.WriteStreamHandler at.
+const writeStreamPath = "/write-stream"
+
+// defaultBufferSize bounds how many not-yet-acked frames Client holds
+// before Write starts dropping the oldest one, the same drop-oldest
+// default pkg/logservice.SessionWriter's own BackpressurePolicy falls
+// back to for its zero value.
+const defaultBufferSize = 1024
+
+// defaultReconnectBackoff is the initial delay between reconnect
+// attempts, doubling each attempt the same way pkg/client.Dialer's
+// request retries back off.
+const defaultReconnectBackoff = 200 * time.Millisecond
+
+// errClosedCleanly is returned internally by serve to tell run a Close
+// drained the buffer on its own terms, as opposed to the connection
+// dropping out from under it.
+var errClosedCleanly = errors.New("logclient: closed")
+
+// Config configures a Client.
+type Config struct {
+	// Addr is LogService's base address, e.g. "http://localhost:8089" or
+	// "https://localhost:8089" for TLS (see dial.go).
+	Addr      string
+	AuthToken string
+	// TenantID partitions which tenant's session this writes to, the
+	// same tenant_id query parameter WriteStreamHandler itself accepts.
+	// "" uses the flat, single-tenant layout.
+	TenantID  string
+	SessionID string
+
+	// BufferSize bounds how many not-yet-acked frames Client holds in
+	// memory; 0 uses defaultBufferSize.
+	BufferSize int
+	// ReconnectBackoff is the initial reconnect delay; 0 uses
+	// defaultReconnectBackoff.
+	ReconnectBackoff time.Duration
+	// MaxReconnectAttempts bounds consecutive failed reconnect attempts
+	// before Client gives up and fails every future Write/Flush/Close; 0
+	// retries forever, appropriate for a long-lived writer that should
+	// outlast a LogService restart.
+	MaxReconnectAttempts int
+}
+
+// ackOrReject decodes either a logservice.WriteStreamAck or a
+// logservice.WriteStreamReject: Code is only ever set on a reject, so
+// it's what tells the two apart once unmarshaled into one struct.
+type ackOrReject struct {
+	HighestSeq int64  `json:"highest_seq"`
+	Seq        int64  `json:"seq"`
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// Client buffers frames for one session and streams them to
+// WriteStreamHandler over a WebSocket it keeps open for the Client's
+// whole lifetime, reconnecting with backoff on any drop and resending
+// whatever hadn't been acked yet. A frame WriteStreamHandler rejects
+// (e.g. RESOURCE_EXHAUSTED) is logged and dropped rather than retried
+// forever — unlike a dropped connection, a reject means the server saw
+// and refused the frame, so resending it blindly isn't safe without
+// knowing why it was refused. The zero value is not usable; use
+// NewClient.
+type Client struct {
+	cfg Config
+
+	mu      sync.Mutex
+	pending []logservice.Frame // not yet acked, oldest first
+	sent    int                // how many of pending have been written to the current conn
+	nextSeq int64
+	closed  bool
+	lastErr error
+
+	wake    chan struct{}
+	closeCh chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewClient returns a Client for cfg.SessionID and starts its
+// connection goroutine immediately, so the first Write doesn't pay for
+// the initial dial.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("logclient: Addr is required")
+	}
+	if cfg.SessionID == "" {
+		return nil, errors.New("logclient: SessionID is required")
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.ReconnectBackoff <= 0 {
+		cfg.ReconnectBackoff = defaultReconnectBackoff
+	}
+
+	c := &Client{
+		cfg:     cfg,
+		wake:    make(chan struct{}, 1),
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go c.run()
+	return c, nil
+}
+
+// Write assigns frame.Seq the next Seq in this session (a caller never
+// sets its own, unlike a direct logservice.SessionWriter.Write caller)
+// and buffers it for delivery, returning as soon as it's queued rather
+// than waiting for an ack — call Flush or Close to wait for durability.
+// If the buffer is already at Config.BufferSize, the oldest unacked
+// frame is dropped to make room.
+func (c *Client) Write(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return fmt.Errorf("logclient: session %s: client is closed", c.cfg.SessionID)
+	}
+
+	frame := logservice.Frame{Seq: c.nextSeq, Timestamp: time.Now(), Data: data}
+	c.nextSeq++
+
+	if len(c.pending) >= c.cfg.BufferSize {
+		c.pending = c.pending[1:]
+		if c.sent > 0 {
+			c.sent--
+		}
+		log.Printf("logclient: session %s: buffer full at %d frame(s), dropped oldest unacked frame", c.cfg.SessionID, c.cfg.BufferSize)
+	}
+	c.pending = append(c.pending, frame)
+	c.signalWake()
+	return nil
+}
+
+// Flush blocks until every frame Write has queued so far has been acked
+// or rejected, ctx is done, or the client gives up reconnecting
+// (Config.MaxReconnectAttempts exhausted). WriteStreamHandler only acks
+// every ackEvery frames (or once more on close — see its doc comment),
+// so Flush on an open connection can block until enough further frames
+// arrive to complete the next ack batch; call Close instead to force an
+// immediate final ack.
+func (c *Client) Flush(ctx context.Context) error {
+	for {
+		c.mu.Lock()
+		drained := len(c.pending) == 0
+		err := c.lastErr
+		c.mu.Unlock()
+
+		if drained {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("logclient: session %s: %w", c.cfg.SessionID, err)
+		}
+
+		select {
+		case <-c.doneCh:
+			// run has stopped (cleanly drained, or gave up reconnecting
+			// without ever setting lastErr); one last check decides which.
+			c.mu.Lock()
+			drained := len(c.pending) == 0
+			c.mu.Unlock()
+			if drained {
+				return nil
+			}
+			return fmt.Errorf("logclient: session %s: closed with frame(s) unflushed", c.cfg.SessionID)
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// Close marks c closed, which tells serve to send a WebSocket close
+// frame once everything buffered has been written (prompting
+// WriteStreamHandler's final ack — see Flush's doc comment on why
+// waiting for that ack must not happen before closed is set), then
+// waits for that drain the same way Flush does before tearing down the
+// connection. Safe to call more than once; only the first call's
+// flushErr is meaningful, later calls return nil once the connection is
+// already down.
+func (c *Client) Close(ctx context.Context) error {
+	c.mu.Lock()
+	alreadyClosed := c.closed
+	c.closed = true
+	c.mu.Unlock()
+
+	if alreadyClosed {
+		select {
+		case <-c.doneCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
+	}
+	close(c.closeCh)
+	c.signalWake()
+
+	flushErr := c.Flush(ctx)
+
+	select {
+	case <-c.doneCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return flushErr
+}
+
+func (c *Client) signalWake() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run owns the connection for c's whole lifetime: dial, serve frames
+// until the connection drops or Close is requested and the buffer is
+// empty, and reconnect with backoff in between.
+func (c *Client) run() {
+	defer close(c.doneCh)
+
+	backoff := c.cfg.ReconnectBackoff
+	attempts := 0
+	for {
+		conn, err := dial(c.streamURL(), c.dialHeader())
+		if err != nil {
+			attempts++
+			if c.cfg.MaxReconnectAttempts > 0 && attempts >= c.cfg.MaxReconnectAttempts {
+				c.fail(fmt.Errorf("connecting: %w", err))
+				return
+			}
+			log.Printf("logclient: session %s: dial failed, retrying in %s: %v", c.cfg.SessionID, backoff, err)
+			select {
+			case <-time.After(backoff):
+			case <-c.closeCh:
+				if c.pendingEmpty() {
+					return
+				}
+			}
+			backoff *= 2
+			continue
+		}
+		attempts = 0
+		backoff = c.cfg.ReconnectBackoff
+		c.resetSent()
+
+		err = c.serve(conn)
+		conn.close()
+		if errors.Is(err, errClosedCleanly) {
+			return
+		}
+		log.Printf("logclient: session %s: connection lost, reconnecting: %v", c.cfg.SessionID, err)
+	}
+}
+
+// serve sends every buffered frame over conn and reads acks/rejects
+// back, until conn breaks, decoding fails unrecoverably, or Close has
+// been requested and the buffer has fully drained.
+func (c *Client) serve(conn *wsConn) error {
+	readErrCh := make(chan error, 1)
+	go func() {
+		for {
+			payload, err := conn.readText()
+			if err != nil {
+				readErrCh <- err
+				return
+			}
+			var msg ackOrReject
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				log.Printf("logclient: session %s: decoding server message: %v", c.cfg.SessionID, err)
+				continue
+			}
+			if msg.Code != "" {
+				log.Printf("logclient: session %s: frame seq %d rejected: %s: %s", c.cfg.SessionID, msg.Seq, msg.Code, msg.Message)
+				c.dropSeq(msg.Seq)
+			} else {
+				c.trimAcked(msg.HighestSeq)
+			}
+		}
+	}()
+
+	closeFrameSent := false
+	for {
+		if err := c.sendUnsent(conn); err != nil {
+			return err
+		}
+		if c.closingDrained() {
+			return errClosedCleanly
+		}
+
+		// Once Close has been requested and everything buffered has been
+		// written to the wire, send a WebSocket close frame so
+		// WriteStreamHandler flushes and sends its own final ack back
+		// (see its doc comment) instead of leaving this goroutine parked
+		// waiting for an ack batch that ackEvery will never complete on
+		// its own.
+		if c.closed && !closeFrameSent && c.sentCaughtUp() {
+			if err := conn.writeCloseFrame(); err != nil {
+				return err
+			}
+			closeFrameSent = true
+		}
+
+		select {
+		case err := <-readErrCh:
+			if closeFrameSent {
+				return errClosedCleanly
+			}
+			return err
+		case <-c.wake:
+		case <-c.closeCh:
+		case <-time.After(20 * time.Millisecond):
+			// Re-check closingDrained/sentCaughtUp periodically while
+			// waiting for WriteStreamHandler's final ack after
+			// writeCloseFrame, since nothing else wakes this loop once
+			// closeCh has already fired once.
+		}
+	}
+}
+
+// sentCaughtUp reports whether every buffered frame has been written to
+// the current connection (not necessarily acked yet).
+func (c *Client) sentCaughtUp() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sent >= len(c.pending)
+}
+
+// sendUnsent writes every buffered frame not yet sent over conn,
+// advancing c.sent as each one succeeds.
+func (c *Client) sendUnsent(conn *wsConn) error {
+	for {
+		c.mu.Lock()
+		if c.sent >= len(c.pending) {
+			c.mu.Unlock()
+			return nil
+		}
+		frame := c.pending[c.sent]
+		c.mu.Unlock()
+
+		encoded, err := json.Marshal(frame)
+		if err != nil {
+			return fmt.Errorf("encoding frame seq %d: %w", frame.Seq, err)
+		}
+		if err := conn.writeText(encoded); err != nil {
+			return fmt.Errorf("writing frame seq %d: %w", frame.Seq, err)
+		}
+
+		c.mu.Lock()
+		c.sent++
+		c.mu.Unlock()
+	}
+}
+
+func (c *Client) resetSent() {
+	c.mu.Lock()
+	c.sent = 0
+	c.mu.Unlock()
+}
+
+func (c *Client) pendingEmpty() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending) == 0
+}
+
+func (c *Client) closingDrained() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed && len(c.pending) == 0
+}
+
+// trimAcked drops every buffered frame at or below highestSeq, the same
+// "safe to forget anything at or below this" contract
+// logservice.WriteStreamAck's doc comment promises a server-side reader.
+func (c *Client) trimAcked(highestSeq int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := 0
+	for i < len(c.pending) && c.pending[i].Seq <= highestSeq {
+		i++
+	}
+	c.pending = c.pending[i:]
+	c.sent -= i
+	if c.sent < 0 {
+		c.sent = 0
+	}
+}
+
+// dropSeq removes a single rejected frame from the buffer by Seq.
+func (c *Client) dropSeq(seq int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, f := range c.pending {
+		if f.Seq == seq {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			if i < c.sent {
+				c.sent--
+			}
+			return
+		}
+	}
+}
+
+func (c *Client) fail(err error) {
+	c.mu.Lock()
+	c.lastErr = err
+	c.mu.Unlock()
+}
+
+func (c *Client) streamURL() string {
+	q := url.Values{}
+	q.Set("session_id", c.cfg.SessionID)
+	if c.cfg.TenantID != "" {
+		q.Set("tenant_id", c.cfg.TenantID)
+	}
+	return c.cfg.Addr + writeStreamPath + "?" + q.Encode()
+}
+
+func (c *Client) dialHeader() http.Header {
+	header := make(http.Header)
+	if c.cfg.AuthToken != "" {
+		header.Set("Authorization", "Bearer "+c.cfg.AuthToken)
+	}
+	return header
+}
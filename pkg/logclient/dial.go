@@ -0,0 +1,248 @@
+package logclient
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// wsConn is the client side of the RFC 6455 connection
+// WriteStreamHandler speaks. pkg/wswire only implements the server half
+// (see its package doc), so this hand-rolls just enough of the client
+// handshake and framing to open a connection, send/receive text frames,
+// and close cleanly — the same narrow-scope-to-what's-needed approach
+// pkg/wswire and pkg/respwire already take for their own protocols.
+type wsConn struct {
+	netConn net.Conn
+	rw      *bufio.ReadWriter
+}
+
+// dial opens addr (an "http(s)://host:port/path" LogService base
+// address plus WriteStreamHandler's path and query string) as a
+// WebSocket connection, performing the RFC 6455 client handshake and
+// sending header as additional request headers (e.g. Authorization).
+func dial(addr string, header http.Header) (*wsConn, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("logclient: parsing address %q: %w", addr, err)
+	}
+
+	var netConn net.Conn
+	host := u.Host
+	if !strings.Contains(host, ":") {
+		if u.Scheme == "https" || u.Scheme == "wss" {
+			host += ":443"
+		} else {
+			host += ":80"
+		}
+	}
+	switch u.Scheme {
+	case "https", "wss":
+		netConn, err = tls.Dial("tcp", host, nil)
+	case "http", "ws", "":
+		netConn, err = net.Dial("tcp", host)
+	default:
+		return nil, fmt.Errorf("logclient: unsupported scheme %q in address %q", u.Scheme, addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("logclient: dialing %s: %w", addr, err)
+	}
+
+	key, err := randomKey()
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	path := u.Path
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	var reqLines strings.Builder
+	fmt.Fprintf(&reqLines, "GET %s HTTP/1.1\r\n", path)
+	fmt.Fprintf(&reqLines, "Host: %s\r\n", u.Host)
+	reqLines.WriteString("Upgrade: websocket\r\n")
+	reqLines.WriteString("Connection: Upgrade\r\n")
+	fmt.Fprintf(&reqLines, "Sec-WebSocket-Key: %s\r\n", key)
+	reqLines.WriteString("Sec-WebSocket-Version: 13\r\n")
+	for name, values := range header {
+		for _, v := range values {
+			fmt.Fprintf(&reqLines, "%s: %s\r\n", name, v)
+		}
+	}
+	reqLines.WriteString("\r\n")
+
+	if _, err := netConn.Write([]byte(reqLines.String())); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("logclient: writing handshake request: %w", err)
+	}
+
+	rw := bufio.NewReadWriter(bufio.NewReader(netConn), bufio.NewWriter(netConn))
+	resp, err := http.ReadResponse(rw.Reader, nil)
+	if err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("logclient: reading handshake response: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		netConn.Close()
+		return nil, fmt.Errorf("logclient: handshake: server returned %s", resp.Status)
+	}
+	if want := acceptKey(key); resp.Header.Get("Sec-WebSocket-Accept") != want {
+		netConn.Close()
+		return nil, errors.New("logclient: handshake: Sec-WebSocket-Accept mismatch")
+	}
+
+	return &wsConn{netConn: netConn, rw: rw}, nil
+}
+
+// writeText sends payload as a single unfragmented, masked text frame.
+// Client-to-server frames must be masked per RFC 6455 §5.1, unlike
+// pkg/wswire's server-to-client writeFrame.
+func (c *wsConn) writeText(payload []byte) error {
+	return c.writeFrame(opText, payload)
+}
+
+// writeCloseFrame sends a close frame without tearing down netConn, so
+// the caller can keep reading the server's response (WriteStreamHandler
+// flushes and sends one final ack before it closes its side) before
+// close tears the connection down.
+func (c *wsConn) writeCloseFrame() error {
+	return c.writeFrame(opClose, nil)
+}
+
+// close sends a close frame and closes the underlying connection.
+func (c *wsConn) close() error {
+	_ = c.writeFrame(opClose, nil)
+	return c.netConn.Close()
+}
+
+// readText blocks for the next text frame from the server, answering
+// any ping with a pong along the way. It returns io.EOF once the server
+// sends a close frame or the connection drops.
+func (c *wsConn) readText() ([]byte, error) {
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opClose:
+			return nil, io.EOF
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opText:
+			return payload, nil
+		}
+	}
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	var maskKey [4]byte
+	if _, err := rand.Read(maskKey[:]); err != nil {
+		return fmt.Errorf("logclient: generating frame mask: %w", err)
+	}
+
+	header := []byte{0x80 | opcode} // FIN=1, no fragmentation
+	length := len(payload)
+	switch {
+	case length <= 125:
+		header = append(header, 0x80|byte(length))
+	case length <= 0xFFFF:
+		ext := make([]byte, 2)
+		binary.BigEndian.PutUint16(ext, uint16(length))
+		header = append(header, 0x80|126)
+		header = append(header, ext...)
+	default:
+		ext := make([]byte, 8)
+		binary.BigEndian.PutUint64(ext, uint64(length))
+		header = append(header, 0x80|127)
+		header = append(header, ext...)
+	}
+	header = append(header, maskKey[:]...)
+
+	masked := make([]byte, length)
+	for i, b := range payload {
+		masked[i] = b ^ maskKey[i%4]
+	}
+
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(masked); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+func (c *wsConn) readFrame() (opcode byte, payload []byte, err error) {
+	first, err := c.rw.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	opcode = first & 0x0F
+
+	second, err := c.rw.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	// Server-to-client frames are unmasked per RFC 6455 §5.1 (see
+	// pkg/wswire's writeFrame), so the mask bit is never set here.
+	length := int64(second & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	return opcode, payload, nil
+}
+
+func randomKey() (string, error) {
+	var raw [16]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", fmt.Errorf("logclient: generating Sec-WebSocket-Key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(raw[:]), nil
+}
+
+func acceptKey(clientKey string) string {
+	h := sha1.New()
+	h.Write([]byte(clientKey + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,61 @@
+// Package logging standardizes the slog JSON output every service
+// should use in place of ad hoc stdlib log or differently-configured
+// slog setups: a common set of fields (service, version, request_id), a
+// level that can be changed at runtime without a restart, and an admin
+// HTTP endpoint to do so.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/cgb808/ZenGlowNext/pkg/reqid"
+)
+
+// Options configures New.
+type Options struct {
+	Service string
+	Version string
+	Level   slog.Level
+}
+
+// New returns a *slog.Logger that writes JSON to stderr, tagged with
+// service and version on every line, and a LevelSetter for changing its
+// level at runtime (wire that into an admin endpoint with Handler).
+func New(opts Options) (*slog.Logger, *LevelSetter) {
+	level := &LevelSetter{}
+	level.Set(opts.Level)
+
+	handler := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level})
+	logger := slog.New(handler).With(
+		slog.String("service", opts.Service),
+		slog.String("version", opts.Version),
+	)
+	return logger, level
+}
+
+// LevelSetter is a slog.Leveler whose level can be changed after
+// construction, which is what lets Handler change it at runtime.
+type LevelSetter struct {
+	level slog.LevelVar
+}
+
+// Level implements slog.Leveler.
+func (s *LevelSetter) Level() slog.Level {
+	return s.level.Level()
+}
+
+// Set changes the active log level.
+func (s *LevelSetter) Set(level slog.Level) {
+	s.level.Set(level)
+}
+
+// WithRequestID returns logger with a request_id field populated from
+// ctx, if reqid.Middleware attached one.
+func WithRequestID(ctx context.Context, logger *slog.Logger) *slog.Logger {
+	if id := reqid.FromContext(ctx); id != "" {
+		return logger.With(slog.String("request_id", id))
+	}
+	return logger
+}
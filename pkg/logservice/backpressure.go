@@ -0,0 +1,114 @@
+package logservice
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/cgb808/ZenGlowNext/pkg/apierror"
+)
+
+// BackpressurePolicy controls what SessionWriter.Write does when a
+// session's buffered frame count would exceed WriterConfig's
+// MaxBufferedFrames, e.g. because a session is producing frames faster
+// than its segments are being rotated or flushed.
+type BackpressurePolicy string
+
+const (
+	// BackpressureDropOldest evicts the oldest buffered frame to make
+	// room for the new one, the original behavior before this setting
+	// existed. Favors keeping writes flowing over keeping history.
+	BackpressureDropOldest BackpressurePolicy = "drop-oldest"
+	// BackpressureDropNewest discards the incoming frame instead,
+	// leaving the buffer's existing contents untouched.
+	BackpressureDropNewest BackpressurePolicy = "drop-newest"
+	// BackpressureBlock rotates the buffer to a segment file immediately
+	// to make room, rather than dropping anything. There's no goroutine
+	// or channel to block a caller on in this package, so "block" means
+	// "pay for a synchronous rotation now" instead of "wait."
+	BackpressureBlock BackpressurePolicy = "block"
+	// BackpressureError rejects the write with ErrBufferFull instead of
+	// buffering or dropping it, so the caller can surface the rejection
+	// to its own client (WriteStreamHandler does this over the
+	// WebSocket) rather than silently losing data.
+	BackpressureError BackpressurePolicy = "error"
+)
+
+// ErrBufferFull is returned by SessionWriter.Write when
+// WriterConfig.Backpressure is BackpressureError and the session's
+// buffer is already at MaxBufferedFrames. Wrapped in an
+// *apierror.Error so HTTP/WebSocket callers can map it to
+// RESOURCE_EXHAUSTED the same way they'd map any other domain error.
+var ErrBufferFull = apierror.QuotaExceeded("logservice: session buffer full")
+
+// WriterStats reports a SessionWriter's backpressure and sequence-gap
+// counters, so operators can tell whether a policy is actually costing
+// them data, and whether frames are going missing upstream of the
+// writer entirely (see recordSeqGapLocked).
+type WriterStats struct {
+	DroppedOldest int64
+	DroppedNewest int64
+	Rejected      int64
+
+	// SeqGaps counts how many times Write saw a frame's Seq jump ahead
+	// of the next Seq it expected. GapFrames is the sum of how many
+	// frames each gap implies were skipped. A client resending from its
+	// last ack (see Write's doc comment) never trips this — only a Seq
+	// higher than expected does, which a drop-oldest-at-some-upstream-hop
+	// (the request's motivating case) or a client-side bug would produce.
+	SeqGaps   int64
+	GapFrames int64
+}
+
+// Stats returns a copy of w's current backpressure counters.
+func (w *SessionWriter) Stats() WriterStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stats
+}
+
+// recordSeqGapLocked records that Write saw a frame n Seq values ahead
+// of what it expected next, e.g. because something upstream of this
+// writer (a WriteStreamHandler client applying its own drop-oldest
+// backpressure, a lossy transport) already dropped frames before they
+// ever reached Write. Callers must hold w.mu.
+func (w *SessionWriter) recordSeqGapLocked(n int64) {
+	w.stats.SeqGaps++
+	w.stats.GapFrames += n
+	w.Config.Metrics.incSeqGaps()
+	w.Config.Metrics.addGapFrames(n)
+	log.Printf("logservice: session %s: sequence gap of %d frame(s) detected, expected seq %d", w.SessionID, n, w.nextSeq)
+}
+
+// applyBackpressureLocked enforces Config.MaxBufferedFrames against
+// w.frames before frame is appended, per Config.Backpressure. Callers
+// must hold w.mu. ok is false if frame must not be appended (the
+// BackpressureDropNewest and BackpressureError cases).
+func (w *SessionWriter) applyBackpressureLocked(frame Frame) (ok bool, err error) {
+	if w.Config.MaxBufferedFrames <= 0 || len(w.frames) < w.Config.MaxBufferedFrames {
+		return true, nil
+	}
+
+	switch w.Config.Backpressure {
+	case BackpressureDropNewest:
+		w.stats.DroppedNewest++
+		w.Config.Metrics.incFramesDropped()
+		return false, nil
+	case BackpressureBlock:
+		if err := w.rotateLocked(); err != nil {
+			return false, err
+		}
+		return true, nil
+	case BackpressureError:
+		w.stats.Rejected++
+		w.Config.Metrics.incFramesDropped()
+		return false, ErrBufferFull
+	default: // BackpressureDropOldest, and the zero value
+		if encoded, err := json.Marshal(w.frames[0]); err == nil {
+			w.sizeBytes -= int64(len(encoded))
+		}
+		w.frames = w.frames[1:]
+		w.stats.DroppedOldest++
+		w.Config.Metrics.incFramesDropped()
+		return true, nil
+	}
+}
@@ -0,0 +1,336 @@
+package logservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// configFilePollInterval is how often WatchConfigFile checks the config
+// file's mtime for a hot-reload trigger. This repo has no way to vendor
+// an inotify/fsnotify binding (the same "can't vendor X" constraint
+// behind every hand-rolled substitute in this package — see compress.go's
+// zstd note), so a stat-and-compare poll is the substitute: cheap enough
+// to run every couple of seconds and, unlike inotify, works the same way
+// whether the config file sits on a local disk or an NFS/network mount.
+const configFilePollInterval = 2 * time.Second
+
+// FileConfig is the subset of a deployment's settings WatchConfigFile
+// hot-reloads from a file: rotation size and compression codec (applied
+// to a ConfigStore, the same knobs AdminHandler's POST /admin/config
+// already changes at runtime) plus the segment-upload queue's backend
+// and address (applied to a QueueConfigStore) — Redis by default, or
+// whichever QueueBackend a deployment has switched to. A zero field
+// means "leave this setting as it is", the same partial-patch semantics
+// as adminConfigPatch, so a config file only needs to mention the
+// settings it actually wants to pin.
+type FileConfig struct {
+	MaxSizeBytes  int64
+	MaxSegmentAge time.Duration
+	Compress      string
+	Fsync         bool
+
+	QueueBackend QueueBackend
+	QueueAddr    string
+	QueueKey     string
+}
+
+// LoadConfigFile reads and parses path, then applies any of
+// LOG_MAX_SIZE_BYTES, LOG_MAX_SEGMENT_AGE, LOG_COMPRESS, LOG_FSYNC,
+// LOG_QUEUE_BACKEND, LOG_QUEUE_ADDR, and LOG_QUEUE_KEY that are set in
+// the environment on top of it, env winning over file the same way
+// pkg/config.Load's env-over-envFile precedence already works. A
+// missing path is not an error and returns a zero-value FileConfig with
+// only env overrides applied: a config file is optional, same as
+// pkg/config.Load's envFile parameter.
+func LoadConfigFile(path string) (FileConfig, error) {
+	var fc FileConfig
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return FileConfig{}, fmt.Errorf("logservice: reading config file %s: %w", path, err)
+		}
+		if err == nil {
+			fc, err = parseConfigYAML(data)
+			if err != nil {
+				return FileConfig{}, fmt.Errorf("logservice: parsing config file %s: %w", path, err)
+			}
+		}
+	}
+	if err := applyConfigFileEnvOverrides(&fc); err != nil {
+		return FileConfig{}, err
+	}
+	return fc, nil
+}
+
+func applyConfigFileEnvOverrides(fc *FileConfig) error {
+	if v, ok := os.LookupEnv("LOG_MAX_SIZE_BYTES"); ok {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("logservice: LOG_MAX_SIZE_BYTES=%q: %w", v, err)
+		}
+		fc.MaxSizeBytes = n
+	}
+	if v, ok := os.LookupEnv("LOG_MAX_SEGMENT_AGE"); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("logservice: LOG_MAX_SEGMENT_AGE=%q: %w", v, err)
+		}
+		fc.MaxSegmentAge = d
+	}
+	if v, ok := os.LookupEnv("LOG_COMPRESS"); ok {
+		fc.Compress = v
+	}
+	if v, ok := os.LookupEnv("LOG_FSYNC"); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("logservice: LOG_FSYNC=%q: %w", v, err)
+		}
+		fc.Fsync = b
+	}
+	if v, ok := os.LookupEnv("LOG_QUEUE_BACKEND"); ok {
+		fc.QueueBackend = QueueBackend(v)
+	}
+	if v, ok := os.LookupEnv("LOG_QUEUE_ADDR"); ok {
+		fc.QueueAddr = v
+	}
+	if v, ok := os.LookupEnv("LOG_QUEUE_KEY"); ok {
+		fc.QueueKey = v
+	}
+	return nil
+}
+
+// parseConfigYAML parses a deliberately small subset of YAML: top-level
+// "key: value" scalars plus one level of nesting under a "queue:" block
+// indented two spaces, "#" comments, and blank lines. That's exactly the
+// shape FileConfig needs (a flat group of settings plus one nested
+// group for the queue's backend/addr/key), and it's as far as this
+// parser goes — this repo has no way to vendor gopkg.in/yaml.v3 (the
+// same constraint msgpack.go's doc comment describes for MessagePack,
+// except YAML's full grammar is too large to hand-roll the way the
+// msgpack subset was, so this implements only the restricted shape
+// below rather than the whole spec). A config file that uses anchors,
+// flow style, multi-line scalars, or deeper nesting is not supported.
+//
+// Example:
+//
+//	max_size_bytes: 67108864
+//	compress: gzip
+//	queue:
+//	  backend: redis
+//	  addr: redis:6379
+//	  key: logservice:segments
+func parseConfigYAML(data []byte) (FileConfig, error) {
+	var fc FileConfig
+	inQueue := false
+
+	for lineNo, raw := range strings.Split(string(data), "\n") {
+		line := stripYAMLComment(raw)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			return FileConfig{}, fmt.Errorf("line %d: expected \"key: value\", got %q", lineNo+1, raw)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if !indented {
+			inQueue = key == "queue" && value == ""
+			if inQueue {
+				continue
+			}
+		}
+
+		if indented && inQueue {
+			if err := setQueueField(&fc, key, value); err != nil {
+				return FileConfig{}, fmt.Errorf("line %d: %w", lineNo+1, err)
+			}
+			continue
+		}
+		if indented {
+			return FileConfig{}, fmt.Errorf("line %d: unexpected indentation outside queue:", lineNo+1)
+		}
+		if err := setTopLevelField(&fc, key, value); err != nil {
+			return FileConfig{}, fmt.Errorf("line %d: %w", lineNo+1, err)
+		}
+	}
+	return fc, nil
+}
+
+func setTopLevelField(fc *FileConfig, key, value string) error {
+	switch key {
+	case "max_size_bytes":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		fc.MaxSizeBytes = n
+	case "max_segment_age":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		fc.MaxSegmentAge = d
+	case "compress":
+		fc.Compress = value
+	case "fsync":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fc.Fsync = b
+	default:
+		return fmt.Errorf("unknown key %q", key)
+	}
+	return nil
+}
+
+func setQueueField(fc *FileConfig, key, value string) error {
+	switch key {
+	case "backend":
+		fc.QueueBackend = QueueBackend(value)
+	case "addr":
+		fc.QueueAddr = value
+	case "key":
+		fc.QueueKey = value
+	default:
+		return fmt.Errorf("unknown queue key %q", key)
+	}
+	return nil
+}
+
+// stripYAMLComment removes a trailing "# ..." comment from line, the
+// same minimal treatment parseEnvFile gives "#"-prefixed env file lines.
+func stripYAMLComment(line string) string {
+	if i := strings.Index(line, "#"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// QueueConfigStore holds the QueueConfig PublishSegmentTo and
+// RecoverOrphanedSegments are called with, guarded the same way
+// ConfigStore guards WriterConfig, so WatchConfigFile can change the
+// publish queue's backend and address at runtime without restarting
+// whatever's calling PublishSegmentTo per rotation.
+type QueueConfigStore struct {
+	mu  sync.RWMutex
+	cfg QueueConfig
+}
+
+// NewQueueConfigStore returns a QueueConfigStore seeded with cfg.
+func NewQueueConfigStore(cfg QueueConfig) *QueueConfigStore {
+	return &QueueConfigStore{cfg: cfg}
+}
+
+// Load returns the store's current QueueConfig.
+func (s *QueueConfigStore) Load() QueueConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// Update applies mutations to a copy of the store's current config and
+// stores the result, returning the new config.
+func (s *QueueConfigStore) Update(mutate func(*QueueConfig)) QueueConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mutate(&s.cfg)
+	return s.cfg
+}
+
+// applyFileConfig copies fc's non-zero fields onto store and queueStore,
+// the same partial-patch approach AdminHandler's POST /admin/config
+// already applies from an adminConfigPatch.
+func applyFileConfig(store *ConfigStore, queueStore *QueueConfigStore, fc FileConfig) {
+	if store != nil {
+		store.Update(func(cfg *WriterConfig) {
+			if fc.MaxSizeBytes != 0 {
+				cfg.MaxSizeBytes = fc.MaxSizeBytes
+			}
+			if fc.MaxSegmentAge != 0 {
+				cfg.MaxSegmentAge = fc.MaxSegmentAge
+			}
+			if fc.Compress != "" {
+				cfg.Compress = fc.Compress
+			}
+			cfg.Fsync = fc.Fsync
+		})
+	}
+	if queueStore != nil {
+		queueStore.Update(func(cfg *QueueConfig) {
+			if fc.QueueBackend != "" {
+				cfg.Backend = fc.QueueBackend
+			}
+			if fc.QueueAddr != "" {
+				cfg.Addr = fc.QueueAddr
+			}
+			if fc.QueueKey != "" {
+				cfg.Key = fc.QueueKey
+			}
+		})
+	}
+}
+
+// WatchConfigFile loads path into store and queueStore (either may be
+// nil to skip that half) once immediately, then again whenever the
+// process receives SIGHUP or path's mtime changes, polled every
+// configFilePollInterval — SIGHUP for an
+// operator or init system that already knows to signal a reload, the
+// poll for one that doesn't, or for a config-management tool that just
+// rewrites the file. It runs until ctx is done, the same
+// run-until-cancelled shape as SessionWriter.Run, and is meant to be
+// started once per process next to AdminHandler rather than per session.
+func WatchConfigFile(ctx context.Context, path string, store *ConfigStore, queueStore *QueueConfigStore) {
+	reload := func(reason string) {
+		fc, err := LoadConfigFile(path)
+		if err != nil {
+			log.Printf("logservice: reloading config file %s (%s): %v", path, reason, err)
+			return
+		}
+		applyFileConfig(store, queueStore, fc)
+		log.Printf("logservice: reloaded config file %s (%s)", path, reason)
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	ticker := time.NewTicker(configFilePollInterval)
+	defer ticker.Stop()
+
+	var lastModTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		lastModTime = info.ModTime()
+	}
+	reload("startup")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sighup:
+			reload("SIGHUP")
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if info.ModTime().After(lastModTime) {
+				lastModTime = info.ModTime()
+				reload("file changed")
+			}
+		}
+	}
+}
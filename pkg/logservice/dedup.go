@@ -0,0 +1,20 @@
+package logservice
+
+// lastCommittedSeqOnDisk returns the highest Seq already rotated to a
+// segment file for sessionID under root, or 0 if the session has no
+// segments yet (or root can't be read, since a fresh session and an
+// unreadable directory look the same from here and NewSessionWriter has
+// no error return to report the difference through).
+func lastCommittedSeqOnDisk(root, sessionID string) int64 {
+	segments, err := ListSegments(root, sessionID)
+	if err != nil {
+		return 0
+	}
+	var last int64
+	for _, seg := range segments {
+		if seg.ToSeq > last {
+			last = seg.ToSeq
+		}
+	}
+	return last
+}
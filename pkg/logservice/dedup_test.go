@@ -0,0 +1,37 @@
+package logservice
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLastCommittedSeqOnDiskNoSegments(t *testing.T) {
+	root := t.TempDir()
+	if got := lastCommittedSeqOnDisk(root, "sess1"); got != 0 {
+		t.Fatalf("lastCommittedSeqOnDisk = %d, want 0 for a session with no segments", got)
+	}
+}
+
+func TestLastCommittedSeqOnDiskUnreadableRoot(t *testing.T) {
+	if got := lastCommittedSeqOnDisk("/nonexistent-root", "sess1"); got != 0 {
+		t.Fatalf("lastCommittedSeqOnDisk = %d, want 0 for an unreadable root", got)
+	}
+}
+
+func TestLastCommittedSeqOnDiskReturnsHighestToSeq(t *testing.T) {
+	root := t.TempDir()
+	sessionID := "sess1"
+	dir := sessionDir(root, sessionID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	for _, name := range []string{"0-10.log", "11-25.log", "26-20.log"} {
+		if err := os.WriteFile(dir+"/"+name, []byte("data"), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	if got := lastCommittedSeqOnDisk(root, sessionID); got != 25 {
+		t.Fatalf("lastCommittedSeqOnDisk = %d, want 25 (highest ToSeq across segments)", got)
+	}
+}
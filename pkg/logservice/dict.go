@@ -0,0 +1,194 @@
+package logservice
+
+import (
+	"bufio"
+	"compress/flate"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// dictCodec is this segment format's Codec/extension pair. It isn't
+// registered in compressors (see compress.go): every Compressor there
+// is constructed with no arguments, but decoding a flate-dict segment
+// needs the dictionary ID recorded in its header resolved first, so it
+// gets its own branch in rotateLocked/readSegment instead of going
+// through CompressorByName.
+const (
+	dictCodec     = "flate-dict"
+	dictExtension = ".fdict"
+	// dictStoreSubdir is where TrainDictionary and rotateLocked save
+	// trained dictionaries, as a sibling of the session directories
+	// under the same root (tenant- and shard-resolved, same as
+	// sessionDir) rather than anywhere a caller has to configure: this
+	// is the "record the mapping so the read APIs can find segments"
+	// piece, and co-locating it with the segments it describes means
+	// readSegment can always find it from a SegmentInfo.Path alone.
+	dictStoreSubdir = ".dicts"
+)
+
+var dictMagic = [4]byte{'Z', 'G', 'L', 'D'}
+
+const dictFormatVersion = 1
+
+// DictProvider resolves a trained dictionary's bytes by the ID recorded
+// in a flate-dict segment's header, the same shape KeyProvider resolves
+// an encryption key by ID. FileDictStore is the only implementation
+// this package needs, since a dictionary (sampled frame content, not
+// secret material) lives happily on the same disk as the segments it
+// compresses.
+type DictProvider interface {
+	Dictionary(ctx context.Context, dictID string) ([]byte, error)
+}
+
+// FileDictStore persists trained dictionaries as "<id>.dict" files under
+// Dir and resolves them back.
+type FileDictStore struct {
+	Dir string
+}
+
+// Save writes dict under id, creating Dir if it doesn't exist yet.
+func (s FileDictStore) Save(id string, dict []byte) error {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("logservice: creating dictionary store %s: %w", s.Dir, err)
+	}
+	return os.WriteFile(filepath.Join(s.Dir, id+".dict"), dict, 0o644)
+}
+
+// Dictionary implements DictProvider.
+func (s FileDictStore) Dictionary(ctx context.Context, id string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.Dir, id+".dict"))
+	if err != nil {
+		return nil, fmt.Errorf("logservice: resolving dictionary %s: %w", id, err)
+	}
+	return data, nil
+}
+
+// dictIDFor derives a short, content-addressed ID for dict, so retraining
+// against an unchanged sample reuses the same file instead of minting a
+// new one every run.
+func dictIDFor(dict []byte) string {
+	sum := sha256.Sum256(dict)
+	return base64.RawURLEncoding.EncodeToString(sum[:8])
+}
+
+// TrainDictionaryMaxBytes bounds how much sample content TrainDictionary
+// concatenates into one dictionary. compress/flate's window is 32KB, so
+// a preset dictionary larger than that can never be fully referenced by
+// a single backward match; this stays under it while still capturing a
+// session's recent common framing and vocabulary.
+const TrainDictionaryMaxBytes = 32 * 1024
+
+// TrainDictionary builds a preset dictionary for compressing sessionID's
+// future small segments from its most recent existing ones — this
+// package's dictionary-training job. A real zstd dictionary trainer
+// (e.g. the COVER algorithm) samples many inputs and solves for the
+// substrings most shared across them; this repo has no way to vendor
+// zstd at all (see compress.go's no-vendored-zstd note), and
+// compress/flate has no trainer API to substitute one for either. What
+// it does have is NewWriterDict/NewReaderDict, which seeds the LZ77
+// window with arbitrary preset bytes — the same practical win a zstd
+// dictionary buys a small input: a short segment that used to start
+// compressing cold now has a window already full of the phrasing and
+// JSON scaffolding recent segments shared. TrainDictionary is the
+// simplest thing that feeds that API well: it concatenates recent
+// frames' raw Data, oldest first so the most recent content ends up
+// closest to where a new segment's own bytes will start, up to
+// TrainDictionaryMaxBytes. keys decrypts any encrypted segments sampled
+// along the way; segments it can't read (wrong/missing key, corrupt)
+// are skipped rather than failing the whole run.
+func TrainDictionary(ctx context.Context, root, sessionID string, keys KeyProvider) ([]byte, error) {
+	segments, err := ListSegments(root, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].FromSeq < segments[j].FromSeq })
+
+	var sample [][]byte
+	total := 0
+	for i := len(segments) - 1; i >= 0 && total < TrainDictionaryMaxBytes; i-- {
+		frames, err := readSegment(ctx, segments[i], 0, 1<<63-1, keys)
+		if err != nil {
+			continue
+		}
+		for j := len(frames) - 1; j >= 0 && total < TrainDictionaryMaxBytes; j-- {
+			sample = append(sample, frames[j].Data)
+			total += len(frames[j].Data)
+		}
+	}
+
+	dict := make([]byte, 0, total)
+	for i := len(sample) - 1; i >= 0; i-- {
+		dict = append(dict, sample[i]...)
+	}
+	if len(dict) > TrainDictionaryMaxBytes {
+		dict = dict[len(dict)-TrainDictionaryMaxBytes:]
+	}
+	return dict, nil
+}
+
+// dictHeader builds the plaintext header rotateLocked writes before a
+// flate-dict segment's compressed bytes, recording id so a reader can
+// resolve the matching dictionary (via FileDictStore, rooted at the
+// segment's own directory — see dictsDirFor) before it even reaches the
+// flate stream.
+func dictHeader(id string) []byte {
+	header := make([]byte, 0, len(dictMagic)+1+2+len(id))
+	header = append(header, dictMagic[:]...)
+	header = append(header, dictFormatVersion)
+	idLen := make([]byte, 2)
+	binary.BigEndian.PutUint16(idLen, uint16(len(id)))
+	header = append(header, idLen...)
+	header = append(header, id...)
+	return header
+}
+
+// dictsDirFor returns the dictionary store directory for a segment at
+// segPath (".../<root>/<sessionID>/<file>"): sessionID's own parent, the
+// same root sessionDir joined segPath from, so it resolves correctly
+// whether or not that root is tenant-partitioned (tenantDir) or one of
+// several HRW-sharded directories (shardDir) — whichever root actually
+// produced this segment is exactly segPath's grandparent.
+func dictsDirFor(segPath string) string {
+	return filepath.Join(filepath.Dir(filepath.Dir(segPath)), dictStoreSubdir)
+}
+
+// newDictWriter writes dictHeader(id) to f and returns a flate writer
+// seeded with dict, so the bytes that follow decompress back with
+// newDictReader (given the same dictionary).
+func newDictWriter(f io.Writer, id string, dict []byte) (io.WriteCloser, error) {
+	if _, err := f.Write(dictHeader(id)); err != nil {
+		return nil, fmt.Errorf("logservice: writing dict segment header: %w", err)
+	}
+	return flate.NewWriterDict(f, flate.DefaultCompression, dict)
+}
+
+// newDictReader reads r's dictHeader, resolves the matching dictionary
+// through dicts, and returns a flate reader seeded with it.
+func newDictReader(ctx context.Context, r *bufio.Reader, dicts DictProvider) (io.ReadCloser, error) {
+	header := make([]byte, len(dictMagic)+1+2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("logservice: reading dict segment header: %w", err)
+	}
+	version := header[len(dictMagic)]
+	if version != dictFormatVersion {
+		return nil, fmt.Errorf("logservice: unsupported flate-dict segment version %d", version)
+	}
+	idLen := int(binary.BigEndian.Uint16(header[len(dictMagic)+1:]))
+	id := make([]byte, idLen)
+	if _, err := io.ReadFull(r, id); err != nil {
+		return nil, fmt.Errorf("logservice: reading dict segment header: %w", err)
+	}
+
+	dict, err := dicts.Dictionary(ctx, string(id))
+	if err != nil {
+		return nil, err
+	}
+	return flate.NewReaderDict(r, dict), nil
+}
@@ -0,0 +1,51 @@
+package logservice
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Drain flushes every writer currently registered with reg, so a server
+// stopping on SIGTERM doesn't lose whatever's still buffered in memory
+// for an open WriteStreamHandler connection. Drained writers are
+// unregistered as they're flushed. Drain's signature matches
+// pkg/shutdown.Component's Stop field, so a main wiring
+// WriteStreamHandler can register it directly:
+//
+//	runner.Register(shutdown.Component{
+//		Name:    "logservice writers",
+//		Timeout: 10 * time.Second,
+//		Stop:    registry.Drain,
+//	})
+//
+// If ctx is done before every writer has been flushed, Drain stops and
+// returns ctx's error, having still attempted every writer up to that
+// point — it does not force-rotate the ones it didn't get to, since
+// rotateLocked writes each segment directly rather than through a tmp
+// file it could resume later; a writer Drain didn't reach keeps
+// whatever it had buffered and will flush it on its own next rotation
+// trigger, or the next Drain.
+func (reg *WriterRegistry) Drain(ctx context.Context) error {
+	reg.mu.Lock()
+	writers := make([]*SessionWriter, 0, len(reg.writers))
+	for _, w := range reg.writers {
+		writers = append(writers, w)
+	}
+	reg.mu.Unlock()
+
+	var firstErr error
+	for i, w := range writers {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("logservice: drain deadline exceeded with %d of %d writers left: %w", len(writers)-i, len(writers), err)
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("logservice: draining writer for session %s: %v", w.SessionID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		reg.Unregister(w.TenantID, w.SessionID)
+	}
+	return firstErr
+}
@@ -0,0 +1,214 @@
+package logservice
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// payload is the minimal shape ExportCSV needs from a Frame's Data:
+// nothing in this repo defines a canonical frame body format (see the
+// Frame doc comment), so export, like pkg/gdpr/segments.go's tombstone
+// pass, treats Data as a JSON object and pulls out whichever of these
+// fields are present. Missing fields export as empty columns rather
+// than failing the row.
+type payload struct {
+	User    string `json:"user"`
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ExportCSV writes every frame in frames to path as gzip-compressed CSV
+// with columns time, seq, user, role, content, trace_id, span_id,
+// ordered by Seq. trace_id/span_id come from Frame itself (see the
+// Frame doc comment), not payload, since they're real struct fields
+// rather than conventions living inside Data.
+//
+// NOTE: the request this implements asked specifically for Parquet, the
+// columnar format analytics tooling can query directly, and this does
+// not deliver that — this repo has no way to vendor a Parquet encoder
+// (the same constraint pkg/backup's ChunkManifest documents for its own
+// chunk format), and unlike FormatMsgpack's hand-rolled encoding,
+// Parquet's container format plus Thrift-based metadata and encoding
+// schemes are too large to hand-roll from spec here. gzip-compressed
+// CSV is a stopgap that gives analytics tooling a single-file,
+// directly-queryable export in the meantime, but it is not a substitute
+// for Parquet's columnar predicate pushdown, and this request should
+// stay open — tracked as a known gap, not treated as done — until
+// Parquet support is actually feasible (vendoring becomes possible, or
+// a small enough subset of the format is specified to hand-roll).
+func ExportCSV(path string, frames []Frame) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("logservice: creating %s: %w", path, err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	w := csv.NewWriter(gz)
+	if err := w.Write([]string{"time", "seq", "user", "role", "content", "trace_id", "span_id"}); err != nil {
+		return fmt.Errorf("logservice: writing CSV header: %w", err)
+	}
+
+	for _, frame := range frames {
+		var p payload
+		if len(frame.Data) > 0 {
+			// Data that isn't a JSON object (or is absent) exports with
+			// empty user/role/content columns rather than failing the row.
+			json.Unmarshal(frame.Data, &p)
+		}
+		row := []string{
+			frame.Timestamp.Format(timeLayout),
+			fmt.Sprintf("%d", frame.Seq),
+			p.User,
+			p.Role,
+			p.Content,
+			frame.TraceID,
+			frame.SpanID,
+		}
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("logservice: writing row for seq %d: %w", frame.Seq, err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("logservice: flushing CSV: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("logservice: closing gzip stream: %w", err)
+	}
+	return nil
+}
+
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// defaultExportFields is the column/key set ExportFrames falls back to
+// when a caller doesn't pass -fields, covering the frame metadata that
+// doesn't depend on a particular Data schema.
+var defaultExportFields = []string{"seq", "timestamp", "data", "trace_id", "span_id"}
+
+// frameField resolves name to a field's string representation, for
+// ExportFrames' field selection: the frame metadata fields below, or
+// "data.<key>" reaching into frame.Data as a JSON object the same way
+// payload does for ExportCSV — a field that isn't one of those, or that
+// reaches into Data that isn't a JSON object or lacks key, reports false
+// rather than failing the whole export (ad-hoc debugging exports over a
+// session's frames shouldn't abort on the one frame with a different
+// shape).
+func frameField(frame Frame, name string) (string, bool) {
+	switch name {
+	case "seq":
+		return strconv.FormatInt(frame.Seq, 10), true
+	case "timestamp":
+		return frame.Timestamp.Format(timeLayout), true
+	case "data":
+		return string(frame.Data), true
+	case "checksum":
+		return strconv.FormatUint(uint64(frame.Checksum), 10), true
+	case "principal":
+		return frame.Principal, true
+	case "tenant_id":
+		return frame.TenantID, true
+	case "trace_id":
+		return frame.TraceID, true
+	case "span_id":
+		return frame.SpanID, true
+	case "level":
+		return frame.Level, true
+	}
+
+	const dataPrefix = "data."
+	if !strings.HasPrefix(name, dataPrefix) {
+		return "", false
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(frame.Data, &obj); err != nil {
+		return "", false
+	}
+	raw, ok := obj[strings.TrimPrefix(name, dataPrefix)]
+	if !ok {
+		return "", false
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, true
+	}
+	return string(raw), true
+}
+
+// ExportFrames writes frames to path in format ("jsonl" or "csv"),
+// restricted to fields if given (see frameField) or defaultExportFields
+// otherwise. Unlike ExportCSV, this writes its chosen format uncompressed
+// and isn't tied to any particular Data schema — it's the general-purpose
+// export behind "zenglow logexport", for pulling an arbitrary session's
+// segments into something a spreadsheet or jq can read directly, not the
+// fixed user/role/content columns ExportCSV's analytics pipeline expects.
+func ExportFrames(path string, frames []Frame, format string, fields []string) error {
+	if len(fields) == 0 {
+		fields = defaultExportFields
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("logservice: creating %s: %w", path, err)
+	}
+	defer f.Close()
+
+	switch format {
+	case "jsonl":
+		return writeJSONLFrames(f, frames, fields)
+	case "csv":
+		return writeCSVFrames(f, frames, fields)
+	default:
+		return fmt.Errorf("logservice: unknown export format %q", format)
+	}
+}
+
+// writeJSONLFrames writes one JSON object per frame per line, with exactly
+// the keys in fields, in the order given.
+func writeJSONLFrames(w io.Writer, frames []Frame, fields []string) error {
+	enc := json.NewEncoder(w)
+	for _, frame := range frames {
+		row := make(map[string]string, len(fields))
+		for _, field := range fields {
+			if v, ok := frameField(frame, field); ok {
+				row[field] = v
+			}
+		}
+		if err := enc.Encode(row); err != nil {
+			return fmt.Errorf("logservice: encoding seq %d: %w", frame.Seq, err)
+		}
+	}
+	return nil
+}
+
+// writeCSVFrames writes frames as CSV with fields as the header row, one
+// row per frame in the same order.
+func writeCSVFrames(w io.Writer, frames []Frame, fields []string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(fields); err != nil {
+		return fmt.Errorf("logservice: writing CSV header: %w", err)
+	}
+
+	for _, frame := range frames {
+		row := make([]string, len(fields))
+		for i, field := range fields {
+			row[i], _ = frameField(frame, field)
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("logservice: writing row for seq %d: %w", frame.Seq, err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("logservice: flushing CSV: %w", err)
+	}
+	return nil
+}
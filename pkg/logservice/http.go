@@ -0,0 +1,207 @@
+package logservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Handler serves the read-side RPCs over root, a directory (or, per
+// splitDirs, a comma-separated list of them sharded by HRW on
+// session_id — see sessionRoot) containing one subdirectory of segments
+// per session:
+//
+//   - GET /segments?session_id=<id>&tenant_id=<id> lists that session's
+//     segments.
+//   - GET /frames?session_id=<id>&tenant_id=<id>&from_seq=<n>&to_seq=<n>
+//     streams the frames in that sequence range back as JSON, in order,
+//     decrypting any AES-GCM-encrypted segments via keys. keys may be
+//     nil if no segment under root is encrypted.
+//   - GET /replay?session_id=<id>&tenant_id=<id>&from_seq=<n>&from_time=<RFC3339>
+//     server-streams every frame from there on (rotated segments, plus
+//     whatever live's active writer still has buffered) as NDJSON, so a
+//     client recovering from a crash can pick back up where it left
+//     off. live may be nil if Handler's caller isn't tracking active
+//     SessionWriters.
+//   - GET /tail?session_id=<id>&tenant_id=<id> server-streams every
+//     frame written to that session from this point on, as NDJSON, via
+//     SessionWriter.Subscribe — the live-dashboard counterpart to
+//     /replay's crash-recovery catch-up. It requires live to have an
+//     active writer for the session already registered (404 if not)
+//     and never terminates on its own; it runs until the client
+//     disconnects or the writer is unregistered, whichever comes
+//     first.
+//
+// tenant_id is optional on every route and partitions root the same way
+// WriteStreamHandler partitions where it writes (see tenantDir); omitting
+// it reads the flat, single-tenant layout.
+//
+// metrics may be nil; when set, each route's handling time is recorded
+// as "logservice_<route>_latency_seconds" via Metrics.ObserveHandler —
+// this package's substitute for per-method gRPC latency, since it has
+// no gRPC/protobuf transport and these routes are its "methods".
+func Handler(root string, keys KeyProvider, live *WriterRegistry, metrics *Metrics) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/segments", func(w http.ResponseWriter, r *http.Request) {
+		defer observeSince(metrics, "segments", time.Now())
+		sessionID := r.URL.Query().Get("session_id")
+		if sessionID == "" {
+			http.Error(w, "logservice: session_id is required", http.StatusBadRequest)
+			return
+		}
+
+		tenantID := r.URL.Query().Get("tenant_id")
+		segments, err := ListSegments(sessionRoot(root, tenantID, sessionID), sessionID)
+		if err != nil {
+			http.Error(w, "logservice: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"segments": segments})
+	})
+	mux.HandleFunc("/frames", func(w http.ResponseWriter, r *http.Request) {
+		defer observeSince(metrics, "frames", time.Now())
+		sessionID := r.URL.Query().Get("session_id")
+		if sessionID == "" {
+			http.Error(w, "logservice: session_id is required", http.StatusBadRequest)
+			return
+		}
+
+		fromSeq, toSeq, err := parseSeqRange(r)
+		if err != nil {
+			http.Error(w, "logservice: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		tenantID := r.URL.Query().Get("tenant_id")
+		frames, err := ReadSessionWithKeys(r.Context(), sessionRoot(root, tenantID, sessionID), sessionID, fromSeq, toSeq, keys)
+		if err != nil {
+			http.Error(w, "logservice: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"frames": frames})
+	})
+	mux.HandleFunc("/replay", func(w http.ResponseWriter, r *http.Request) {
+		defer observeSince(metrics, "replay", time.Now())
+		sessionID := r.URL.Query().Get("session_id")
+		tenantID := r.URL.Query().Get("tenant_id")
+		if sessionID == "" {
+			http.Error(w, "logservice: session_id is required", http.StatusBadRequest)
+			return
+		}
+
+		fromSeq, _, err := parseSeqRange(r)
+		if err != nil {
+			http.Error(w, "logservice: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var fromTime time.Time
+		if v := r.URL.Query().Get("from_time"); v != "" {
+			fromTime, err = time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "logservice: malformed from_time: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		flusher, _ := w.(http.Flusher)
+		if err := Replay(r.Context(), root, tenantID, sessionID, fromSeq, fromTime, keys, live, func(frame Frame) error {
+			if err := enc.Encode(frame); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return nil
+		}); err != nil {
+			log.Printf("logservice: replaying session %s: %v", sessionID, err)
+		}
+	})
+	mux.HandleFunc("/tail", func(w http.ResponseWriter, r *http.Request) {
+		defer observeSince(metrics, "tail", time.Now())
+		sessionID := r.URL.Query().Get("session_id")
+		if sessionID == "" {
+			http.Error(w, "logservice: session_id is required", http.StatusBadRequest)
+			return
+		}
+		tenantID := r.URL.Query().Get("tenant_id")
+
+		var writer *SessionWriter
+		if live != nil {
+			writer = live.writerFor(tenantID, sessionID)
+		}
+		if writer == nil {
+			http.Error(w, fmt.Sprintf("logservice: no active writer for session %s", sessionID), http.StatusNotFound)
+			return
+		}
+
+		frames, cancel := writer.Subscribe()
+		defer cancel()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		flusher, _ := w.(http.Flusher)
+		// Send headers now rather than waiting for the first frame, so
+		// a caller with nothing to tail yet (a quiet session) can still
+		// tell the subscription succeeded instead of looking identical
+		// to a connection that's still pending.
+		w.WriteHeader(http.StatusOK)
+		if flusher != nil {
+			flusher.Flush()
+		}
+		enc := json.NewEncoder(w)
+		ctx := r.Context()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case frame, ok := <-frames:
+				if !ok {
+					return
+				}
+				if err := enc.Encode(frame); err != nil {
+					return
+				}
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+		}
+	})
+	return mux
+}
+
+// parseSeqRange reads from_seq/to_seq from the request's query string,
+// defaulting to [0, math.MaxInt64] when either is omitted.
+func parseSeqRange(r *http.Request) (fromSeq, toSeq int64, err error) {
+	toSeq = 1<<63 - 1
+
+	if v := r.URL.Query().Get("from_seq"); v != "" {
+		fromSeq, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	if v := r.URL.Query().Get("to_seq"); v != "" {
+		toSeq, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return fromSeq, toSeq, nil
+}
+
+// observeSince records the elapsed time since start against route on
+// metrics. Deferred at the top of each handler func so it covers the
+// whole request, including /replay's streaming body.
+func observeSince(metrics *Metrics, route string, start time.Time) {
+	metrics.ObserveHandler(route, time.Since(start))
+}
@@ -0,0 +1,58 @@
+package logservice
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// activeWritersMetric is the gauge name WriterRegistry.SetMetrics
+// reports the registry's current writer count on.
+const activeWritersMetric = "logservice_active_writers"
+
+// ReapIdle flushes and unregisters every SessionWriter in reg that has
+// had no Write for at least idleTimeout, so a registry fed by
+// short-lived connections (WriteStreamHandler registers one writer per
+// WebSocket) doesn't accumulate goroutine-free but still-resident
+// SessionWriters forever when a connection drops without ever reaching
+// its deferred Unregister — a reconnect from a new TCP connection, or a
+// crash. It returns how many writers were reaped.
+func (reg *WriterRegistry) ReapIdle(idleTimeout time.Duration) int {
+	reg.mu.Lock()
+	var idle []*SessionWriter
+	for sessionID, w := range reg.writers {
+		if time.Since(w.LastActivity()) >= idleTimeout {
+			idle = append(idle, w)
+			delete(reg.writers, sessionID)
+		}
+	}
+	reg.mu.Unlock()
+	reg.reportActive()
+
+	for _, w := range idle {
+		if err := w.Flush(); err != nil {
+			log.Printf("logservice: flushing idle writer for session %s: %v", w.SessionID, err)
+		}
+	}
+	return len(idle)
+}
+
+// ReapLoop calls ReapIdle every interval until ctx is done, the
+// idle-eviction counterpart to SessionWriter.Run's stale-rotation
+// ticker.
+func (reg *WriterRegistry) ReapLoop(ctx context.Context, interval, idleTimeout time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reg.ReapIdle(idleTimeout)
+		}
+	}
+}
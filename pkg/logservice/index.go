@@ -0,0 +1,147 @@
+package logservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultIndexInterval is how many frames apart BuildIndex's sparse
+// entries land when a caller passes interval <= 0.
+const DefaultIndexInterval = 100
+
+// IndexEntry is one seekable point in a segment's .idx sidecar: the byte
+// offset Seq's frame starts at in the segment's own WriteSegment-encoded
+// byte stream. For an encrypted or compressed segment that's the
+// plaintext, pre-wrapping stream DecryptSegment/gunzip produces, not the
+// bytes actually on disk — the index is built from frames before
+// EncryptSegment or gzip ever see them, so a reader always seeks after
+// unwrapping, the same order readSegment already decodes in.
+type IndexEntry struct {
+	Seq       int64     `json:"seq"`
+	Timestamp time.Time `json:"timestamp"`
+	Offset    int64     `json:"offset"`
+}
+
+// SegmentIndex is a sparse seq/time -> byte offset index for one
+// segment, letting a future read/replay API skip straight to the frame
+// nearest a seq or time instead of decoding every frame before it.
+type SegmentIndex struct {
+	Entries []IndexEntry `json:"entries"`
+}
+
+// SeekSeq returns the byte offset of the latest indexed frame at or
+// before seq, and whether any entry qualifies. A reader should start
+// decoding from that offset and skip frames until it reaches seq, since
+// the index is sparse and may not have an entry for seq itself.
+func (idx SegmentIndex) SeekSeq(seq int64) (offset int64, ok bool) {
+	for _, e := range idx.Entries {
+		if e.Seq > seq {
+			break
+		}
+		offset, ok = e.Offset, true
+	}
+	return offset, ok
+}
+
+// SeekTime is Seek's time-based counterpart, returning the latest
+// indexed frame at or before t.
+func (idx SegmentIndex) SeekTime(t time.Time) (offset int64, ok bool) {
+	for _, e := range idx.Entries {
+		if e.Timestamp.After(t) {
+			break
+		}
+		offset, ok = e.Offset, true
+	}
+	return offset, ok
+}
+
+// BuildIndex computes a sparse index over frames as WriteSegment(format,
+// ...) would encode them: every interval-th frame plus the first and
+// last, each paired with the byte offset its encoding starts at.
+// interval <= 0 uses DefaultIndexInterval.
+func BuildIndex(format SegmentFormat, frames []Frame, interval int) (SegmentIndex, error) {
+	if interval <= 0 {
+		interval = DefaultIndexInterval
+	}
+
+	var idx SegmentIndex
+	var offset int64
+	switch format {
+	case FormatBinary:
+		offset = int64(len(binaryMagic)) + 1
+	case FormatMsgpack:
+		offset = int64(len(msgpackMagic)) + 1
+	}
+
+	for i, frame := range frames {
+		if i%interval == 0 || i == len(frames)-1 {
+			idx.Entries = append(idx.Entries, IndexEntry{Seq: frame.Seq, Timestamp: frame.Timestamp, Offset: offset})
+		}
+
+		var encoded []byte
+		var err error
+		switch format {
+		case FormatBinary:
+			encoded, err = encodeBinaryFrame(frame)
+		case FormatMsgpack:
+			encoded, err = encodeMsgpackFrame(frame)
+		case FormatNDJSON:
+			encoded, err = encodeNDJSONFrame(frame)
+		default:
+			return SegmentIndex{}, fmt.Errorf("logservice: unknown segment format %d", format)
+		}
+		if err != nil {
+			return SegmentIndex{}, err
+		}
+		offset += int64(len(encoded))
+	}
+	return idx, nil
+}
+
+// indexPath returns the .idx sidecar path for a segment file path,
+// stripping the same suffixes trimSegmentSuffix does. In practice
+// rotateLocked never builds an index for a compressed segment (see its
+// comment), but this stays in sync with trimSegmentSuffix regardless.
+func indexPath(segmentPath string) string {
+	for _, suffix := range []string{".log.gz", ".log.zlib", ".log.flate", ".log.fdict", ".log"} {
+		if strings.HasSuffix(segmentPath, suffix) {
+			return strings.TrimSuffix(segmentPath, suffix) + ".idx"
+		}
+	}
+	return segmentPath + ".idx"
+}
+
+// WriteIndexFile writes idx as JSON to path, the .idx sidecar for a
+// segment (see indexPath).
+func WriteIndexFile(path string, idx SegmentIndex) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("logservice: encoding index %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("logservice: writing index %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadIndexFile reads a segment's .idx sidecar written by
+// WriteIndexFile. A missing sidecar is not an error: segments written
+// before this package added indexing don't have one, and callers should
+// fall back to a full scan from the start of the segment instead.
+func ReadIndexFile(path string) (SegmentIndex, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return SegmentIndex{}, nil
+	}
+	if err != nil {
+		return SegmentIndex{}, fmt.Errorf("logservice: reading index %s: %w", path, err)
+	}
+	var idx SegmentIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return SegmentIndex{}, fmt.Errorf("logservice: decoding index %s: %w", path, err)
+	}
+	return idx, nil
+}
@@ -0,0 +1,117 @@
+package logservice
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Level is a log frame's severity, ordered Debug < Info < Warn < Error
+// for LevelFilter's MinLevel comparisons. Frame.Level only carries
+// meaning when a LevelFilter is configured.
+type Level string
+
+const (
+	LevelDebug Level = "debug"
+	LevelInfo  Level = "info"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+)
+
+var levelRank = map[Level]int{LevelDebug: 0, LevelInfo: 1, LevelWarn: 2, LevelError: 3}
+
+// rank returns l's precedence, treating an empty or unrecognized Level
+// the same as LevelInfo so a frame written before this feature existed
+// (or by a caller that never sets Level) isn't dropped by a MinLevel at
+// or below LevelInfo.
+func (l Level) rank() int {
+	if r, ok := levelRank[l]; ok {
+		return r
+	}
+	return levelRank[LevelInfo]
+}
+
+// LevelFilter decides whether SessionWriter.Write keeps or drops a
+// frame based on its Level: MinLevel/SampleRate are the package-wide
+// default, and SetOverride (driven by AdminHandler's POST
+// /admin/sessions/level) replaces them for one session without
+// requiring every client writing to it to redeploy with a new level
+// configured locally. This is the same global-config-plus-per-key-
+// override shape TenantQuota already uses for per-tenant state, scoped
+// to session_id here instead of tenant_id.
+//
+// A nil *LevelFilter keeps every frame, the same "nil means no limit"
+// contract WriterConfig.RateLimit and TenantQuota already have.
+type LevelFilter struct {
+	// MinLevel drops any frame ranked below it; "" keeps every level.
+	MinLevel Level
+	// SampleRate keeps this fraction (0 to 1) of LevelDebug frames that
+	// already passed MinLevel, instead of keeping all of them — debug
+	// frames are the request's motivating high-volume, low-value case,
+	// so sampling only applies to them rather than every level below
+	// Info (there is only one). 0 drops all debug frames outright; 1 or
+	// above keeps all of them; values in between sample.
+	SampleRate float64
+
+	mu        sync.Mutex
+	overrides map[string]levelOverride
+}
+
+type levelOverride struct {
+	minLevel   Level
+	sampleRate float64
+}
+
+// NewLevelFilter returns a LevelFilter with the given package-wide
+// defaults and no per-session overrides.
+func NewLevelFilter(minLevel Level, sampleRate float64) *LevelFilter {
+	return &LevelFilter{
+		MinLevel:   minLevel,
+		SampleRate: sampleRate,
+		overrides:  make(map[string]levelOverride),
+	}
+}
+
+// SetOverride replaces sessionID's MinLevel/SampleRate, independent of
+// f's package-wide defaults, until ClearOverride reverts it.
+func (f *LevelFilter) SetOverride(sessionID string, minLevel Level, sampleRate float64) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.overrides[sessionID] = levelOverride{minLevel: minLevel, sampleRate: sampleRate}
+}
+
+// ClearOverride removes sessionID's override, reverting it to f's
+// package-wide MinLevel/SampleRate.
+func (f *LevelFilter) ClearOverride(sessionID string) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.overrides, sessionID)
+}
+
+// Allow reports whether a frame at level should be kept for sessionID,
+// consulting sessionID's override if one is set.
+func (f *LevelFilter) Allow(sessionID string, level Level) bool {
+	if f == nil {
+		return true
+	}
+
+	minLevel, sampleRate := f.MinLevel, f.SampleRate
+	f.mu.Lock()
+	if o, ok := f.overrides[sessionID]; ok {
+		minLevel, sampleRate = o.minLevel, o.sampleRate
+	}
+	f.mu.Unlock()
+
+	if minLevel != "" && level.rank() < minLevel.rank() {
+		return false
+	}
+	if level == LevelDebug && sampleRate < 1 {
+		return rand.Float64() < sampleRate
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+// Package logservice implements the read side of the log segment store:
+// ListSegments and ReadSession let a downstream consumer page through a
+// session's rotated frames instead of scraping the filesystem directly.
+//
+// The request asks for these as RPCs "in grpc/logservice", but this
+// repo has no protobuf/gRPC code generation pipeline (the same
+// constraint pkg/spiffe's package doc documents for the SPIRE Workload
+// API) — every other "Service" here (LogService, RouterService,
+// CanonicalService, IngestionService) is actually JSON over HTTP, so
+// ListSegments and ReadSession follow that convention instead: they're
+// plain functions here, exposed over HTTP by Handler and consumed
+// through new methods on pkg/client.LogServiceClient.
+//
+// There was no log segment writer anywhere in this repo when
+// ListSegments/ReadSession were added (the closest things were
+// pkg/pipeline.Segment, which only names a rotated segment without
+// defining its on-disk format, and pkg/gdpr/segments.go, which assumes
+// an NDJSON-per-subject format scoped to GDPR erasure); SessionWriter
+// is that writer now. This package defines the format
+// ListSegments/ReadSession and SessionWriter actually read and write:
+// each session has its own directory of segment files named
+// "<from_seq>-<to_seq>.log", optionally suffixed with a codec extension
+// ("gz", "zlib", "flate" — see Compressor) when WriterConfig.Compress
+// names one. This repo has no way to vendor a zstd, lz4, or snappy
+// library, the same call pkg/backup's ChunkManifest made for
+// CSV-over-Parquet, so compression is one of the stdlib compress/...
+// codecs instead. Each segment is, depending on WriterConfig.Format, a
+// newline-delimited stream of JSON-encoded Frame values ordered by Seq, a
+// length-prefixed stream of the same JSON encoding (FormatBinary), or a
+// stream of MessagePack-encoded Frame values (FormatMsgpack) — see
+// binary.go and msgpack.go.
+package logservice
+
+import (
+	"time"
+)
+
+// Frame is one record within a segment. Checksum is a CRC32 over Data,
+// set by WriteSegment/SessionWriter.Write when the segment is written
+// and checked by VerifyFrame/VerifySegment; it rides in the frame itself
+// (rather than a trailer or separate index) so a single frame is still
+// self-verifying even read out of its segment's context.
+type Frame struct {
+	Seq       int64     `json:"seq"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      []byte    `json:"data"`
+	Checksum  uint32    `json:"checksum"`
+
+	// Principal is the authenticated caller that wrote this frame, set
+	// by WriteStreamHandler from pkg/authn's Identity rather than
+	// trusted from the client, so a frame can be attributed even when
+	// its writer reused another session's client code. Empty when
+	// written outside WriteStreamHandler (e.g. directly via
+	// SessionWriter.Write in a test, or before auth was wired in).
+	Principal string `json:"principal,omitempty"`
+
+	// TenantID identifies which tenant's session this frame belongs to,
+	// set by WriteStreamHandler from the write stream's tenant_id query
+	// parameter and mirrored onto SessionWriter.TenantID, which
+	// partitions where the frame lands on disk (see tenantDir). Empty
+	// means the single flat, un-partitioned layout this package used
+	// before multi-tenancy.
+	TenantID string `json:"tenant_id,omitempty"`
+
+	// Attributes is an arbitrary, caller-supplied string map carried
+	// alongside Data, for metadata a consumer wants to filter or index
+	// on without unmarshaling the opaque payload (see the package doc's
+	// note on Data having no defined schema). Unlike Principal and
+	// TenantID, nothing in this package stamps Attributes itself — it's
+	// set by whoever constructs the Frame, the same as Data.
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// TraceID and SpanID correlate a frame with the distributed trace
+	// that produced it, set by the caller from its own tracing context
+	// the same way Attributes is. This package has no tracing
+	// integration of its own to stamp these automatically (there's no
+	// OpenTelemetry or other tracing library vendored here), so a Frame
+	// written without a trace in scope just leaves them empty.
+	TraceID string `json:"trace_id,omitempty"`
+	SpanID  string `json:"span_id,omitempty"`
+
+	// Level is this frame's severity ("debug", "info", "warn", "error"),
+	// set by the caller the same way Data is. It only matters to
+	// WriterConfig.Levels (see level.go); nothing else in this package
+	// interprets it, and an empty Level is treated as "info" wherever it
+	// does. Like Attributes/TraceID/SpanID, nothing stamps this
+	// automatically.
+	Level string `json:"level,omitempty"`
+}
+
+// SegmentInfo describes one rotated segment file on disk.
+type SegmentInfo struct {
+	ID      string // the file's base name without its .log[.codec] suffix
+	Path    string
+	FromSeq int64
+	ToSeq   int64
+	// Codec is the compression codec name (e.g. "gzip") the segment's
+	// filename extension maps to, or "" if it's uncompressed.
+	Codec      string
+	Compressed bool
+	SizeBytes  int64
+	ModTime    time.Time
+}
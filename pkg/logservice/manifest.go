@@ -0,0 +1,239 @@
+package logservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// manifestFileName is the per-session manifest's file name, living
+// alongside that session's segments under sessionDir, the same way an
+// index sidecar (indexPath) and a trained dictionary (dictStoreSubdir)
+// live next to the data they describe rather than anywhere a caller has
+// to configure.
+const manifestFileName = "manifest.json"
+
+// ManifestEntry records one segment in a SessionManifest: enough to
+// cross-validate it against what's actually on disk (see CheckManifest)
+// without re-decoding every frame the way VerifySegment does.
+type ManifestEntry struct {
+	SegmentID  string `json:"segment_id"`
+	FromSeq    int64  `json:"from_seq"`
+	ToSeq      int64  `json:"to_seq"`
+	FrameCount int    `json:"frame_count"`
+	SizeBytes  int64  `json:"size_bytes"`
+	// Checksum is a CRC32 over the segment file's on-disk bytes exactly as
+	// rotateLocked wrote them — compressed and/or encrypted, if either is
+	// configured — unlike Frame.Checksum, which only covers one frame's
+	// Data. It's how CheckManifest tells a segment was altered or
+	// corrupted on disk without decoding it at all.
+	Checksum uint32    `json:"checksum"`
+	ModTime  time.Time `json:"mod_time"`
+}
+
+// SessionManifest is sessionID's current set of rotated segments, written
+// by appendManifestEntry on every rotation.
+type SessionManifest struct {
+	SessionID string          `json:"session_id"`
+	Segments  []ManifestEntry `json:"segments"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// manifestPath returns sessionID's manifest file path under root.
+func manifestPath(root, sessionID string) string {
+	return filepath.Join(sessionDir(root, sessionID), manifestFileName)
+}
+
+// ReadManifest reads sessionID's manifest under root. A missing manifest
+// is not an error and returns a zero-value SessionManifest with SessionID
+// set: sessions written before this package added manifests, or a session
+// CheckManifest is asked about that happens to have none yet, report no
+// recorded segments rather than a parse failure.
+func ReadManifest(root, sessionID string) (SessionManifest, error) {
+	data, err := os.ReadFile(manifestPath(root, sessionID))
+	if os.IsNotExist(err) {
+		return SessionManifest{SessionID: sessionID}, nil
+	}
+	if err != nil {
+		return SessionManifest{}, fmt.Errorf("logservice: reading manifest for session %s: %w", sessionID, err)
+	}
+	var m SessionManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return SessionManifest{}, fmt.Errorf("logservice: decoding manifest for session %s: %w", sessionID, err)
+	}
+	return m, nil
+}
+
+// writeManifest writes m to its session's manifest path via a tmp file
+// and rename, the same crash-safety rotateLocked already uses for segment
+// files themselves — a reader should never see a half-written
+// manifest.json.
+func writeManifest(root string, m SessionManifest) error {
+	path := manifestPath(root, m.SessionID)
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("logservice: encoding manifest for session %s: %w", m.SessionID, err)
+	}
+	tmpPath := path + tmpSegmentSuffix
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("logservice: writing manifest for session %s: %w", m.SessionID, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("logservice: finalizing manifest for session %s: %w", m.SessionID, err)
+	}
+	return nil
+}
+
+// appendManifestEntry adds entry to sessionID's manifest under root,
+// called by rotateLocked right after a segment is finalized. If
+// entry.SegmentID already exists (RunRetention's compaction pass rewrites
+// a segment in place under the same ID), the existing record is replaced
+// rather than duplicated.
+func appendManifestEntry(root, sessionID string, entry ManifestEntry) error {
+	m, err := ReadManifest(root, sessionID)
+	if err != nil {
+		return err
+	}
+	m.SessionID = sessionID
+
+	replaced := false
+	for i, existing := range m.Segments {
+		if existing.SegmentID == entry.SegmentID {
+			m.Segments[i] = entry
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		m.Segments = append(m.Segments, entry)
+	}
+	m.UpdatedAt = entry.ModTime
+
+	return writeManifest(root, m)
+}
+
+// removeManifestEntry drops segmentID from sessionID's manifest under
+// root, called by RunRetention's expiry and compaction passes when they
+// remove a segment file — without this, CheckManifest would report every
+// expired or compacted-away segment as "missing_on_disk" forever.
+// Removing an entry that isn't there is not an error: a manifest that
+// predates this entry's segment, or a segment retention already swept
+// in an earlier pass, both just leave nothing to remove.
+func removeManifestEntry(root, sessionID, segmentID string) error {
+	m, err := ReadManifest(root, sessionID)
+	if err != nil {
+		return err
+	}
+	kept := m.Segments[:0]
+	for _, existing := range m.Segments {
+		if existing.SegmentID != segmentID {
+			kept = append(kept, existing)
+		}
+	}
+	if len(kept) == len(m.Segments) {
+		return nil
+	}
+	m.Segments = kept
+	m.UpdatedAt = time.Now()
+	return writeManifest(root, m)
+}
+
+// segmentFileChecksum computes a CRC32 over path's on-disk bytes, for
+// ManifestEntry.Checksum.
+func segmentFileChecksum(path string) (uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("logservice: checksumming %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := crc32.NewIEEE()
+	if _, err := io.Copy(h, f); err != nil {
+		return 0, fmt.Errorf("logservice: checksumming %s: %w", path, err)
+	}
+	return h.Sum32(), nil
+}
+
+// ManifestDiscrepancy describes one way a session's manifest.json
+// disagreed with what CheckManifest actually found on disk.
+type ManifestDiscrepancy struct {
+	SegmentID string
+	// Kind is one of "missing_on_disk", "missing_from_manifest",
+	// "size_mismatch", or "checksum_mismatch".
+	Kind   string
+	Detail string
+}
+
+// CheckManifest cross-validates sessionID's manifest.json against
+// ListSegments(root, sessionID)'s actual directory contents: every segment
+// the manifest records should exist on disk with the same size and
+// checksum (segmentFileChecksum, recomputed now), and every segment on
+// disk should be recorded in the manifest. Unlike VerifySession, this
+// never decodes a frame — it's a much cheaper whole-file integrity check,
+// the kind "zenglow logverify" can afford to run on every pass rather than
+// reserving for a full decode-and-checksum sweep.
+func CheckManifest(root, sessionID string) ([]ManifestDiscrepancy, error) {
+	manifest, err := ReadManifest(root, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	onDisk, err := ListSegments(root, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	bySegment := make(map[string]SegmentInfo, len(onDisk))
+	for _, seg := range onDisk {
+		bySegment[seg.ID] = seg
+	}
+
+	var discrepancies []ManifestDiscrepancy
+	seen := make(map[string]bool, len(manifest.Segments))
+	for _, entry := range manifest.Segments {
+		seen[entry.SegmentID] = true
+		seg, ok := bySegment[entry.SegmentID]
+		if !ok {
+			discrepancies = append(discrepancies, ManifestDiscrepancy{
+				SegmentID: entry.SegmentID,
+				Kind:      "missing_on_disk",
+				Detail:    "manifest lists this segment but it isn't in the session directory",
+			})
+			continue
+		}
+		if seg.SizeBytes != entry.SizeBytes {
+			discrepancies = append(discrepancies, ManifestDiscrepancy{
+				SegmentID: entry.SegmentID,
+				Kind:      "size_mismatch",
+				Detail:    fmt.Sprintf("manifest says %d bytes, disk has %d", entry.SizeBytes, seg.SizeBytes),
+			})
+			continue
+		}
+		sum, err := segmentFileChecksum(seg.Path)
+		if err != nil {
+			return nil, err
+		}
+		if sum != entry.Checksum {
+			discrepancies = append(discrepancies, ManifestDiscrepancy{
+				SegmentID: entry.SegmentID,
+				Kind:      "checksum_mismatch",
+				Detail:    fmt.Sprintf("manifest checksum %08x, disk checksum %08x", entry.Checksum, sum),
+			})
+		}
+	}
+
+	for _, seg := range onDisk {
+		if !seen[seg.ID] {
+			discrepancies = append(discrepancies, ManifestDiscrepancy{
+				SegmentID: seg.ID,
+				Kind:      "missing_from_manifest",
+				Detail:    "segment exists on disk but isn't recorded in manifest.json",
+			})
+		}
+	}
+
+	return discrepancies, nil
+}
@@ -0,0 +1,133 @@
+package logservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSegmentFile(t *testing.T, root, sessionID, name string, data []byte) {
+	t.Helper()
+	dir := sessionDir(root, sessionID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestAppendManifestEntryReplacesExisting(t *testing.T) {
+	root := t.TempDir()
+	sessionID := "sess1"
+	if err := os.MkdirAll(sessionDir(root, sessionID), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	if err := appendManifestEntry(root, sessionID, ManifestEntry{SegmentID: "0-10", FrameCount: 5}); err != nil {
+		t.Fatalf("appendManifestEntry: %v", err)
+	}
+	if err := appendManifestEntry(root, sessionID, ManifestEntry{SegmentID: "0-10", FrameCount: 7}); err != nil {
+		t.Fatalf("appendManifestEntry (replace): %v", err)
+	}
+
+	m, err := ReadManifest(root, sessionID)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if len(m.Segments) != 1 {
+		t.Fatalf("got %d segments, want 1 (replace, not append)", len(m.Segments))
+	}
+	if m.Segments[0].FrameCount != 7 {
+		t.Fatalf("FrameCount = %d, want 7 (latest entry should win)", m.Segments[0].FrameCount)
+	}
+}
+
+func TestRemoveManifestEntry(t *testing.T) {
+	root := t.TempDir()
+	sessionID := "sess1"
+	if err := os.MkdirAll(sessionDir(root, sessionID), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	if err := appendManifestEntry(root, sessionID, ManifestEntry{SegmentID: "0-10"}); err != nil {
+		t.Fatalf("appendManifestEntry: %v", err)
+	}
+	if err := appendManifestEntry(root, sessionID, ManifestEntry{SegmentID: "11-20"}); err != nil {
+		t.Fatalf("appendManifestEntry: %v", err)
+	}
+	if err := removeManifestEntry(root, sessionID, "0-10"); err != nil {
+		t.Fatalf("removeManifestEntry: %v", err)
+	}
+
+	m, err := ReadManifest(root, sessionID)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if len(m.Segments) != 1 || m.Segments[0].SegmentID != "11-20" {
+		t.Fatalf("Segments = %+v, want only 11-20 remaining", m.Segments)
+	}
+
+	// Removing an already-absent entry is not an error.
+	if err := removeManifestEntry(root, sessionID, "0-10"); err != nil {
+		t.Fatalf("removeManifestEntry (already gone): %v", err)
+	}
+}
+
+func TestCheckManifestDetectsDiscrepancies(t *testing.T) {
+	root := t.TempDir()
+	sessionID := "sess1"
+
+	writeSegmentFile(t, root, sessionID, "0-10.log", []byte("clean segment data"))
+	sum, err := segmentFileChecksum(filepath.Join(sessionDir(root, sessionID), "0-10.log"))
+	if err != nil {
+		t.Fatalf("segmentFileChecksum: %v", err)
+	}
+	if err := appendManifestEntry(root, sessionID, ManifestEntry{
+		SegmentID: "0-10", SizeBytes: int64(len("clean segment data")), Checksum: sum,
+	}); err != nil {
+		t.Fatalf("appendManifestEntry: %v", err)
+	}
+
+	// A segment the manifest knows nothing about.
+	writeSegmentFile(t, root, sessionID, "11-20.log", []byte("undocumented"))
+
+	// A segment the manifest records but that no longer has the recorded
+	// checksum, simulating on-disk corruption.
+	writeSegmentFile(t, root, sessionID, "21-30.log", []byte("original"))
+	if err := appendManifestEntry(root, sessionID, ManifestEntry{
+		SegmentID: "21-30", SizeBytes: int64(len("original")), Checksum: 0xdeadbeef,
+	}); err != nil {
+		t.Fatalf("appendManifestEntry: %v", err)
+	}
+
+	// A segment the manifest records but that's been deleted from disk.
+	if err := appendManifestEntry(root, sessionID, ManifestEntry{SegmentID: "31-40", SizeBytes: 5}); err != nil {
+		t.Fatalf("appendManifestEntry: %v", err)
+	}
+
+	discrepancies, err := CheckManifest(root, sessionID)
+	if err != nil {
+		t.Fatalf("CheckManifest: %v", err)
+	}
+
+	byKind := map[string]int{}
+	bySegment := map[string]ManifestDiscrepancy{}
+	for _, d := range discrepancies {
+		byKind[d.Kind]++
+		bySegment[d.SegmentID] = d
+	}
+
+	if byKind["missing_from_manifest"] != 1 || bySegment["11-20"].Kind != "missing_from_manifest" {
+		t.Errorf("expected 11-20 reported missing_from_manifest, got %+v", discrepancies)
+	}
+	if byKind["checksum_mismatch"] != 1 || bySegment["21-30"].Kind != "checksum_mismatch" {
+		t.Errorf("expected 21-30 reported checksum_mismatch, got %+v", discrepancies)
+	}
+	if byKind["missing_on_disk"] != 1 || bySegment["31-40"].Kind != "missing_on_disk" {
+		t.Errorf("expected 31-40 reported missing_on_disk, got %+v", discrepancies)
+	}
+	if _, ok := bySegment["0-10"]; ok {
+		t.Errorf("clean segment 0-10 should not be reported, got %+v", discrepancies)
+	}
+}
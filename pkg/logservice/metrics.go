@@ -0,0 +1,120 @@
+package logservice
+
+import (
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/telemetry"
+)
+
+// defaultLatencyBounds are the histogram buckets (seconds) ObserveHandler
+// uses for per-method request latency.
+var defaultLatencyBounds = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+
+// Metrics holds the counters and histograms this package's callers can
+// wire into a telemetry.Registry to get a Prometheus /metrics endpoint
+// the same way cmd/push-gateway mounts telemetry.Handler(reg) — pkg
+// /logservice has no main of its own to mount a listener from, so the
+// binary embedding Handler/WriteStreamHandler is expected to create the
+// Registry, pass it to NewMetrics, and mount telemetry.Handler(reg)
+// itself. A nil *Metrics is valid everywhere it's accepted and simply
+// records nothing, so instrumentation is opt-in.
+type Metrics struct {
+	reg *telemetry.Registry
+
+	FramesReceived  *telemetry.Counter
+	FramesDropped   *telemetry.Counter
+	BytesWritten    *telemetry.Counter
+	Rotations       *telemetry.Counter
+	PublishFailures *telemetry.Counter
+
+	// SeqGaps and GapFrames count SessionWriter.Write's sequence-gap
+	// detections (see WriterStats.SeqGaps) across every session sharing
+	// this Metrics, so an operator can alert on data loss upstream of
+	// the writer without querying AdminHandler's per-session
+	// /admin/sessions/gaps route for every active session.
+	SeqGaps   *telemetry.Counter
+	GapFrames *telemetry.Counter
+
+	// FramesFiltered counts frames SessionWriter.Write dropped or
+	// sampled out via WriterConfig.Levels (see level.go), across every
+	// session sharing this Metrics.
+	FramesFiltered *telemetry.Counter
+}
+
+// NewMetrics registers pkg/logservice's counters on reg and returns them
+// attached to reg for ObserveHandler's per-method histograms.
+func NewMetrics(reg *telemetry.Registry) *Metrics {
+	return &Metrics{
+		reg:             reg,
+		FramesReceived:  reg.Counter("logservice_frames_received_total"),
+		FramesDropped:   reg.Counter("logservice_frames_dropped_total"),
+		BytesWritten:    reg.Counter("logservice_bytes_written_total"),
+		Rotations:       reg.Counter("logservice_rotations_total"),
+		PublishFailures: reg.Counter("logservice_publish_failures_total"),
+		SeqGaps:         reg.Counter("logservice_seq_gaps_total"),
+		GapFrames:       reg.Counter("logservice_seq_gap_frames_total"),
+		FramesFiltered:  reg.Counter("logservice_frames_filtered_total"),
+	}
+}
+
+// ObserveHandler records how long one call to a Handler or
+// WriteStreamHandler route took, as
+// "logservice_<method>_latency_seconds". This package has no
+// protobuf/gRPC pipeline (see the package doc's note on why every
+// "Service" here is JSON over HTTP instead), so its "methods" are the
+// HTTP routes Handler and WriteStreamHandler serve rather than gRPC
+// service methods.
+func (m *Metrics) ObserveHandler(method string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.reg.Histogram("logservice_"+method+"_latency_seconds", defaultLatencyBounds).Observe(d.Seconds())
+}
+
+func (m *Metrics) incFramesReceived() {
+	if m != nil {
+		m.FramesReceived.Inc()
+	}
+}
+
+func (m *Metrics) incFramesDropped() {
+	if m != nil {
+		m.FramesDropped.Inc()
+	}
+}
+
+func (m *Metrics) addBytesWritten(n int64) {
+	if m != nil {
+		m.BytesWritten.Add(n)
+	}
+}
+
+func (m *Metrics) incRotations() {
+	if m != nil {
+		m.Rotations.Inc()
+	}
+}
+
+func (m *Metrics) incPublishFailures() {
+	if m != nil {
+		m.PublishFailures.Inc()
+	}
+}
+
+func (m *Metrics) incSeqGaps() {
+	if m != nil {
+		m.SeqGaps.Inc()
+	}
+}
+
+func (m *Metrics) addGapFrames(n int64) {
+	if m != nil {
+		m.GapFrames.Add(n)
+	}
+}
+
+func (m *Metrics) incFramesFiltered() {
+	if m != nil {
+		m.FramesFiltered.Inc()
+	}
+}
@@ -0,0 +1,166 @@
+package logservice
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/backup"
+	"github.com/cgb808/ZenGlowNext/pkg/bus"
+	"github.com/cgb808/ZenGlowNext/pkg/pipeline"
+	"github.com/cgb808/ZenGlowNext/pkg/respwire"
+)
+
+// PublishSegment pushes seg onto the Redis list at addr/key as a
+// JSON-encoded pipeline.Segment, the same list pipeline.RedisSegmentSource
+// pops from in cmd/pipeline-coordinator. It is the writer-side
+// counterpart RedisSegmentSource's doc comment already describes:
+// "a Redis list that logservice pushes rotated segments onto". metrics
+// may be nil; every error path counts against its PublishFailures
+// counter when it isn't.
+func PublishSegment(ctx context.Context, addr, key string, seg pipeline.Segment, metrics *Metrics) error {
+	data, err := json.Marshal(seg)
+	if err != nil {
+		metrics.incPublishFailures()
+		return fmt.Errorf("logservice: encoding segment: %w", err)
+	}
+
+	dialer := net.Dialer{Timeout: 5 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		metrics.incPublishFailures()
+		return fmt.Errorf("logservice: dial redis %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(respwire.EncodeCommand("RPUSH", key, string(data))); err != nil {
+		metrics.incPublishFailures()
+		return fmt.Errorf("logservice: write RPUSH: %w", err)
+	}
+	if _, err := respwire.ReadInteger(bufio.NewReader(conn)); err != nil {
+		metrics.incPublishFailures()
+		return fmt.Errorf("logservice: read RPUSH reply: %w", err)
+	}
+	return nil
+}
+
+// PublishKafkaSegment publishes seg to topic as a JSON-encoded
+// pipeline.Segment via pub, the same event PublishSegment pushes onto
+// the Redis list, for deployments whose downstream pipelines already
+// consume Kafka rather than popping a Redis list. It is additive, not
+// a replacement: a caller wiring both wants every rotated segment on
+// both sinks, so nothing here stops a caller from also calling
+// PublishSegment with the same seg. pub is typically a *pkg/bus.Kafka;
+// passing any other bus.Publisher works too, since this only depends
+// on the interface. metrics may be nil.
+func PublishKafkaSegment(ctx context.Context, pub bus.Publisher, topic string, seg pipeline.Segment, metrics *Metrics) error {
+	return publishViaBus(ctx, pub, "kafka topic", topic, seg, metrics)
+}
+
+// PublishJetStreamSegment publishes seg to subject as a JSON-encoded
+// pipeline.Segment via a pkg/bus.JetStream at addr, the JetStream
+// counterpart to PublishKafkaSegment. Unlike PublishSegment's RPUSH,
+// JetStream.Publish blocks for the broker's ack, so a nil return means
+// the segment is durably stored on the stream, not just written to the
+// socket. metrics may be nil.
+func PublishJetStreamSegment(ctx context.Context, addr, subject string, seg pipeline.Segment, metrics *Metrics) error {
+	return publishViaBus(ctx, &bus.JetStream{Addr: addr}, "jetstream subject", subject, seg, metrics)
+}
+
+// PublishRedisStreamGroupSegment publishes seg to topic's Redis Stream
+// at addr via bus.RedisStreamGroup.Publish — the same XADD
+// PublishSegment's plain Redis list trades for an RPUSH, so multiple
+// downstream workers can each run a pipeline.BusSegmentSource against
+// a RedisStreamGroup sharing Group on the read side, consuming their
+// own share of entries with XACK/XAUTOCLAIM instead of every worker
+// racing to BRPOP the same list. metrics may be nil.
+func PublishRedisStreamGroupSegment(ctx context.Context, addr, topic string, seg pipeline.Segment, metrics *Metrics) error {
+	return publishViaBus(ctx, &bus.RedisStreamGroup{Addr: addr}, "redis stream", topic, seg, metrics)
+}
+
+// publishViaBus is the shared implementation behind PublishKafkaSegment
+// and PublishJetStreamSegment: both are a JSON-encoded Segment handed
+// to a pkg/bus.Publisher, differing only in which broker dialed it and
+// the wording of the resulting error.
+func publishViaBus(ctx context.Context, pub bus.Publisher, brokerNoun, topic string, seg pipeline.Segment, metrics *Metrics) error {
+	data, err := json.Marshal(seg)
+	if err != nil {
+		metrics.incPublishFailures()
+		return fmt.Errorf("logservice: encoding segment: %w", err)
+	}
+
+	if err := pub.Publish(ctx, topic, data); err != nil {
+		metrics.incPublishFailures()
+		return fmt.Errorf("logservice: publishing to %s %s: %w", brokerNoun, topic, err)
+	}
+	return nil
+}
+
+// QueueBackend selects which broker PublishSegmentTo talks to.
+type QueueBackend string
+
+const (
+	// QueueBackendRedis pushes onto the Redis list PublishSegment
+	// already implements; it's the default so existing LOG_QUEUE_ADDR/
+	// LOG_QUEUE_KEY deployments need no config change.
+	QueueBackendRedis QueueBackend = "redis"
+	// QueueBackendJetStream publishes through pkg/bus.JetStream,
+	// ack'd and durable the way RedisSegmentSource's plain RPUSH/BRPOP
+	// list isn't.
+	QueueBackendJetStream QueueBackend = "jetstream"
+	// QueueBackendRedisStreamGroup publishes to a Redis Stream through
+	// pkg/bus.RedisStreamGroup, for multiple downstream workers to
+	// consume with consumer-group acknowledgement and pending-entry
+	// recovery instead of contending over one Redis list.
+	QueueBackendRedisStreamGroup QueueBackend = "redis-stream-group"
+)
+
+// QueueConfig selects and configures PublishSegmentTo's broker.
+// Addr/Key are interpreted per Backend: for QueueBackendRedis they're
+// the Redis address and list key PublishSegment already takes; for
+// QueueBackendJetStream they're the NATS server address and the
+// JetStream subject (BusSegmentSource's Topic on the consuming side);
+// for QueueBackendRedisStreamGroup they're the Redis address and the
+// stream's topic name (BusSegmentSource's Subscriber is then a
+// RedisStreamGroup sharing that same topic and a Group name of its
+// own choosing).
+type QueueConfig struct {
+	Backend QueueBackend `env:"LOG_QUEUE_BACKEND"`
+	Addr    string       `env:"LOG_QUEUE_ADDR"`
+	Key     string       `env:"LOG_QUEUE_KEY"`
+}
+
+// PublishSegmentTo publishes seg to the broker cfg selects, so a
+// deployment switches queue backends with LOG_QUEUE_BACKEND instead of
+// a call-site change. An empty/unrecognized Backend falls back to
+// QueueBackendRedis, matching PublishSegment's pre-existing behavior
+// for callers that set Addr/Key without ever setting Backend. metrics
+// may be nil.
+func PublishSegmentTo(ctx context.Context, cfg QueueConfig, seg pipeline.Segment, metrics *Metrics) error {
+	switch cfg.Backend {
+	case QueueBackendJetStream:
+		return PublishJetStreamSegment(ctx, cfg.Addr, cfg.Key, seg, metrics)
+	case QueueBackendRedisStreamGroup:
+		return PublishRedisStreamGroupSegment(ctx, cfg.Addr, cfg.Key, seg, metrics)
+	default:
+		return PublishSegment(ctx, cfg.Addr, cfg.Key, seg, metrics)
+	}
+}
+
+// UploadAndPublish uploads the segment at localPath to store under
+// objectName, then publishes it to the Redis segment queue at
+// addr/key with Path set to the object's durable URL rather than
+// localPath, so cmd/pipeline-coordinator fetches it from object storage
+// instead of assuming the local disk it was rotated on is still around.
+// metrics may be nil.
+func UploadAndPublish(ctx context.Context, store backup.URLObjectStore, localPath, objectName string, queueAddr, queueKey string, seg pipeline.Segment, metrics *Metrics) error {
+	if err := store.Put(ctx, objectName, localPath); err != nil {
+		metrics.incPublishFailures()
+		return fmt.Errorf("logservice: uploading %s: %w", localPath, err)
+	}
+	seg.Path = store.URL(objectName)
+	return PublishSegment(ctx, queueAddr, queueKey, seg, metrics)
+}
@@ -0,0 +1,86 @@
+package logservice
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cgb808/ZenGlowNext/pkg/apierror"
+)
+
+// TenantQuota bounds how many bytes a tenant may write and how many
+// sessions it may have active at once, shared across every SessionWriter
+// and WriteStreamHandler connection for that tenant the same way Metrics
+// is shared across every SessionWriter in a WriterConfig. A nil
+// *TenantQuota enforces nothing, the same "nil means no limit" contract
+// WriterConfig.RateLimit already has.
+type TenantQuota struct {
+	// MaxBytes bounds total bytes written per tenant, across every
+	// session. Zero disables the byte bound.
+	MaxBytes int64
+	// MaxSessions bounds how many sessions a tenant may have
+	// concurrently registered with a WriterRegistry. Zero disables the
+	// session bound.
+	MaxSessions int
+
+	mu       sync.Mutex
+	bytes    map[string]int64
+	sessions map[string]int
+}
+
+// NewTenantQuota returns a TenantQuota enforcing maxBytes total bytes and
+// maxSessions concurrent sessions per tenant. Either limit may be zero to
+// disable that bound.
+func NewTenantQuota(maxBytes int64, maxSessions int) *TenantQuota {
+	return &TenantQuota{
+		MaxBytes:    maxBytes,
+		MaxSessions: maxSessions,
+		bytes:       make(map[string]int64),
+		sessions:    make(map[string]int),
+	}
+}
+
+// checkAndAddBytes charges n bytes against tenantID's running total,
+// rejecting with apierror.QuotaExceeded instead of charging it if that
+// would exceed MaxBytes.
+func (q *TenantQuota) checkAndAddBytes(tenantID string, n int64) error {
+	if q == nil || q.MaxBytes <= 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.bytes[tenantID]+n > q.MaxBytes {
+		return apierror.QuotaExceeded(fmt.Sprintf("logservice: tenant %q exceeded its %d byte quota", tenantID, q.MaxBytes))
+	}
+	q.bytes[tenantID] += n
+	return nil
+}
+
+// acquireSession reserves one of tenantID's session slots, rejecting
+// with apierror.QuotaExceeded instead of reserving one if tenantID is
+// already at MaxSessions. Every successful acquireSession must be
+// matched by a releaseSession once that session closes.
+func (q *TenantQuota) acquireSession(tenantID string) error {
+	if q == nil || q.MaxSessions <= 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.sessions[tenantID] >= q.MaxSessions {
+		return apierror.QuotaExceeded(fmt.Sprintf("logservice: tenant %q exceeded its %d concurrent session limit", tenantID, q.MaxSessions))
+	}
+	q.sessions[tenantID]++
+	return nil
+}
+
+// releaseSession frees one of tenantID's session slots acquired by a
+// prior acquireSession call.
+func (q *TenantQuota) releaseSession(tenantID string) {
+	if q == nil || q.MaxSessions <= 0 {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.sessions[tenantID] > 0 {
+		q.sessions[tenantID]--
+	}
+}
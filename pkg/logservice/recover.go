@@ -0,0 +1,315 @@
+package logservice
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cgb808/ZenGlowNext/pkg/pipeline"
+)
+
+// RecoveredSegment describes one ".logtmp" file RecoverOrphanedSegments
+// finished and, if queue is configured, published.
+type RecoveredSegment struct {
+	SessionID string
+	Path      string
+	FromSeq   int64
+	ToSeq     int64
+	Frames    int
+}
+
+// RecoveryReport summarizes one RecoverOrphanedSegments pass.
+type RecoveryReport struct {
+	Sessions  int
+	Recovered []RecoveredSegment
+	// Unrecoverable lists ".logtmp" paths RecoverOrphanedSegments found
+	// but couldn't salvage a single complete frame from (e.g. the crash
+	// landed before rotateLocked finished writing even one frame). These
+	// are left on disk, not deleted, so an operator can inspect them.
+	Unrecoverable []string
+}
+
+// RecoverOrphanedSegments scans every session directory under root for
+// ".logtmp" files rotateLocked left behind when a crash landed between
+// finishing a segment's write and renaming it into place (see
+// tmpSegmentSuffix), finalizes each one it can salvage frames from by
+// renaming it to its real name, rebuilds its index, and republishes it
+// to queue the same way a normal rotation would. Call this once at
+// startup, before constructing any SessionWriter for root, so
+// NewSessionWriter's lastCommittedSeqOnDisk call sees the recovered
+// segments and doesn't hand out Seq values a client already has acked
+// on a segment this pass just finalized.
+//
+// An AES-GCM-encrypted orphan can't be validated or indexed without its
+// key, which RecoverOrphanedSegments doesn't take; it finalizes those by
+// name alone and leaves confirming them to "zenglow logverify -key-prefix",
+// the same read/verify split RunRetention's compaction already has for
+// encrypted segments it can't compact without a KeyProvider.
+//
+// RecoverOrphanedSegments has no notion of tenants itself, the same as
+// RunRetention: for a tenant-partitioned layout (see tenantDir), point
+// root at one tenant's subtree and run it once per tenant.
+//
+// queue.Addr == "" skips publishing recovered segments, finalizing them
+// on disk only. metrics may be nil.
+func RecoverOrphanedSegments(ctx context.Context, root string, queue QueueConfig, metrics *Metrics) (RecoveryReport, error) {
+	var report RecoveryReport
+
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return report, nil
+	}
+	if err != nil {
+		return report, fmt.Errorf("logservice: listing %s: %w", root, err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		sessionID := entry.Name()
+		dir := sessionDir(root, sessionID)
+
+		tmpNames, err := orphanedTmpFiles(dir)
+		if err != nil {
+			return report, fmt.Errorf("logservice: listing %s: %w", dir, err)
+		}
+		if len(tmpNames) == 0 {
+			continue
+		}
+		report.Sessions++
+
+		for _, name := range tmpNames {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			if err := recoverOne(ctx, sessionID, filepath.Join(dir, name), queue, metrics, &report); err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, nil
+}
+
+// orphanedTmpFiles lists dir's ".logtmp" file names, oldest rotation
+// first, so a session with more than one orphan (unusual, but possible
+// after several crashed rotations) is recovered in the order it was
+// written.
+func orphanedTmpFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), tmpSegmentSuffix) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// recoverOne finalizes a single ".logtmp" file, or records it under
+// report.Unrecoverable if it can't salvage any frames from it.
+func recoverOne(ctx context.Context, sessionID, tmpPath string, queue QueueConfig, metrics *Metrics, report *RecoveryReport) error {
+	finalPath := strings.TrimSuffix(tmpPath, tmpSegmentSuffix)
+	m := segmentNameRE.FindStringSubmatch(filepath.Base(finalPath))
+	if m == nil {
+		// Not a name rotateLocked would ever produce; leave it for an
+		// operator to look at rather than guessing at its format.
+		report.Unrecoverable = append(report.Unrecoverable, tmpPath)
+		return nil
+	}
+	fromSeq, _ := strconv.ParseInt(m[1], 10, 64)
+	toSeq, _ := strconv.ParseInt(m[2], 10, 64)
+	codec := codecForExtension(m[3])
+
+	seg := SegmentInfo{
+		ID:         trimSegmentSuffix(filepath.Base(finalPath)),
+		Path:       tmpPath,
+		FromSeq:    fromSeq,
+		ToSeq:      toSeq,
+		Codec:      codec,
+		Compressed: codec != "",
+	}
+
+	frames, err := readSegment(ctx, seg, 0, 1<<63-1, nil)
+	salvaged := false
+	switch {
+	case errors.Is(err, ErrNoKeyProvider):
+		// Complete or not, an AES-GCM segment can't be told apart
+		// without its key; finalize it by name and leave validating its
+		// contents to logverify once keys are available.
+		if err := finalizeOrphan(tmpPath, finalPath); err != nil {
+			return err
+		}
+		report.Recovered = append(report.Recovered, RecoveredSegment{SessionID: sessionID, Path: finalPath, FromSeq: fromSeq, ToSeq: toSeq})
+		return publishRecovered(ctx, queue, finalPath, metrics)
+	case err != nil && codec == "":
+		// Compression and encryption have no byte-boundary to salvage a
+		// valid prefix from the way NDJSON's newlines do; only fall back
+		// to a partial-frame salvage for the plain, uncompressed case.
+		frames, err = recoverTruncatedNDJSON(tmpPath)
+		salvaged = err == nil && len(frames) > 0
+	}
+	if err != nil || len(frames) == 0 {
+		if err != nil {
+			log.Printf("logservice: recovering %s: %v", tmpPath, err)
+		}
+		report.Unrecoverable = append(report.Unrecoverable, tmpPath)
+		return nil
+	}
+
+	if salvaged {
+		// recoverTruncatedNDJSON stopped before the truncated tail, but
+		// tmpPath on disk still has it; rewrite tmpPath down to just the
+		// frames being kept before finalizing, so a later ReadSession
+		// doesn't trip over the same bad line this pass already decided
+		// to drop.
+		if err := rewriteSalvaged(tmpPath, frames); err != nil {
+			return err
+		}
+	}
+
+	if err := finalizeOrphan(tmpPath, finalPath); err != nil {
+		return err
+	}
+
+	if codec == "" {
+		format, ferr := detectFormat(finalPath)
+		if ferr == nil {
+			if idx, ierr := BuildIndex(format, frames, 0); ierr == nil {
+				if werr := WriteIndexFile(indexPath(finalPath), idx); werr != nil {
+					log.Printf("logservice: writing index for recovered segment %s: %v", finalPath, werr)
+				}
+			} else {
+				log.Printf("logservice: building index for recovered segment %s: %v", finalPath, ierr)
+			}
+		}
+	}
+
+	report.Recovered = append(report.Recovered, RecoveredSegment{
+		SessionID: sessionID,
+		Path:      finalPath,
+		FromSeq:   frames[0].Seq,
+		ToSeq:     frames[len(frames)-1].Seq,
+		Frames:    len(frames),
+	})
+	return publishRecovered(ctx, queue, finalPath, metrics)
+}
+
+// rewriteSalvaged overwrites tmpPath with just frames NDJSON-encoded,
+// dropping whatever truncated tail recoverTruncatedNDJSON stopped
+// short of, so the file finalizeOrphan renames into place only contains
+// the frames this pass is vouching for.
+func rewriteSalvaged(tmpPath string, frames []Frame) error {
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("logservice: rewriting salvaged segment %s: %w", tmpPath, err)
+	}
+	defer f.Close()
+	if err := WriteNDJSONSegment(f, frames); err != nil {
+		return fmt.Errorf("logservice: rewriting salvaged segment %s: %w", tmpPath, err)
+	}
+	return f.Close()
+}
+
+// finalizeOrphan renames tmpPath to finalPath, the same step rotateLocked
+// takes once a segment is fully written.
+func finalizeOrphan(tmpPath, finalPath string) error {
+	if err := os.Rename(tmpPath, finalPath); err != nil {
+		return fmt.Errorf("logservice: finalizing recovered segment %s: %w", finalPath, err)
+	}
+	return nil
+}
+
+// publishRecovered pushes a recovered segment to queue the same way a
+// normal rotation's would-be caller does, so a downstream pipeline that
+// consumes rotated segments off the queue doesn't miss one a crash
+// stranded. queue.Addr == "" is a no-op.
+func publishRecovered(ctx context.Context, queue QueueConfig, path string, metrics *Metrics) error {
+	if queue.Addr == "" {
+		return nil
+	}
+	seg := pipeline.Segment{
+		ID:   trimSegmentSuffix(filepath.Base(path)),
+		Path: path,
+	}
+	if err := PublishSegmentTo(ctx, queue, seg, metrics); err != nil {
+		log.Printf("logservice: publishing recovered segment %s: %v", path, err)
+	}
+	return nil
+}
+
+// detectFormat peeks path's header to tell WriteSegment's NDJSON output
+// apart from decodeBinaryFrames' and decodeMsgpackFrames', the same check
+// readSegment makes inline, so BuildIndex gets the format its offsets
+// actually describe.
+func detectFormat(path string) (SegmentFormat, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return FormatNDJSON, err
+	}
+	defer f.Close()
+
+	var header [4]byte
+	n, err := f.Read(header[:])
+	if err != nil && err != io.EOF {
+		return FormatNDJSON, err
+	}
+	switch {
+	case isBinarySegment(header[:n]):
+		return FormatBinary, nil
+	case isMsgpackSegment(header[:n]):
+		return FormatMsgpack, nil
+	}
+	return FormatNDJSON, nil
+}
+
+// recoverTruncatedNDJSON decodes every complete JSON-encoded Frame line
+// in an uncompressed, unencrypted ".logtmp" file, stopping at the first
+// line that doesn't parse instead of failing the whole segment the way
+// decodeNDJSONFrames does for a normal read. A crash mid-write only ever
+// truncates the last line being written when rotateLocked was
+// interrupted, so everything before it is a complete, valid frame worth
+// keeping.
+func recoverTruncatedNDJSON(tmpPath string) ([]Frame, error) {
+	f, err := os.Open(tmpPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var frames []Frame
+	scanner := bufio.NewScanner(bufio.NewReaderSize(f, 64*1024))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var frame Frame
+		if err := json.Unmarshal(line, &frame); err != nil {
+			break
+		}
+		frames = append(frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		return frames, err
+	}
+	return frames, nil
+}
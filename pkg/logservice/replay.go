@@ -0,0 +1,44 @@
+package logservice
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Replay streams every Frame for tenantID's sessionID at or after
+// fromSeq, and at or after fromTime if it's non-zero, across sessionID's
+// rotated segments plus whatever's buffered in live's active writer for
+// it, in Seq order, to send. It's the crash-recovery read path:
+// ReadSession/ReadSessionWithKeys only ever see rotated segments, so a
+// client reconnecting after a crash mid-segment would otherwise never
+// see its own unflushed tail frames. tenantID == "" replays from the
+// flat, single-tenant layout. root may be a splitDirs-style
+// comma-separated directory list; sessionRoot resolves it to
+// sessionID's shard the same way NewSessionWriter does.
+func Replay(ctx context.Context, root, tenantID, sessionID string, fromSeq int64, fromTime time.Time, keys KeyProvider, live *WriterRegistry, send func(Frame) error) error {
+	frames, err := ReadSessionWithKeys(ctx, sessionRoot(root, tenantID, sessionID), sessionID, fromSeq, 1<<63-1, keys)
+	if err != nil {
+		return err
+	}
+	if live != nil {
+		frames = append(frames, live.Buffered(tenantID, sessionID)...)
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i].Seq < frames[j].Seq })
+
+	for _, frame := range frames {
+		if frame.Seq < fromSeq {
+			continue
+		}
+		if !fromTime.IsZero() && frame.Timestamp.Before(fromTime) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := send(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
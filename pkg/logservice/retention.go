@@ -0,0 +1,225 @@
+package logservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/backup"
+)
+
+// RetentionConfig bounds how much a session's segments may grow before
+// RunRetention deletes or archives the oldest ones, and what counts as
+// "small enough to compact".
+type RetentionConfig struct {
+	Dir                string
+	MaxBytesPerSession int64              // 0 disables the size bound
+	MaxAge             time.Duration      // 0 disables the age bound
+	CompactBelowBytes  int64              // segments smaller than this are merged together; 0 disables compaction
+	Archive            backup.ObjectStore // if set, expiring segments are uploaded here before being removed locally; nil just deletes them
+}
+
+// RetentionReport summarizes one RunRetention pass.
+type RetentionReport struct {
+	Sessions  int
+	Deleted   []string // segment paths removed (archived first, if Config.Archive is set)
+	Compacted int      // segments merged away during compaction
+}
+
+// RunRetention scans every session directory under cfg.Dir once,
+// expiring segments past cfg.MaxAge or beyond cfg.MaxBytesPerSession,
+// then compacting whatever's left under cfg.CompactBelowBytes per
+// session. It does one pass and returns; running it on a schedule (cron,
+// a systemd timer, or cmd/zenglow's "logretention" subcommand invoked
+// periodically) is the same division of labor pkg/querystats.Rollup.Run
+// and PostgresSink.DeleteOlderThan already use instead of an in-process
+// ticker.
+//
+// RunRetention has no notion of tenants itself: for a tenant-partitioned
+// layout (see tenantDir), point cfg.Dir at one tenant's subtree
+// (root/tenantID) and run it once per tenant, the same way a caller
+// already runs it once per logservice deployment. That keeps RunRetention
+// generic over whatever directory tree it's pointed at, rather than
+// teaching it to distinguish a tenant directory from a session directory.
+func RunRetention(ctx context.Context, cfg RetentionConfig) (RetentionReport, error) {
+	var report RetentionReport
+
+	entries, err := os.ReadDir(cfg.Dir)
+	if os.IsNotExist(err) {
+		return report, nil
+	}
+	if err != nil {
+		return report, fmt.Errorf("logservice: listing %s: %w", cfg.Dir, err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		sessionID := entry.Name()
+		report.Sessions++
+
+		if err := expireSession(ctx, cfg, sessionID, &report); err != nil {
+			return report, fmt.Errorf("logservice: expiring session %s: %w", sessionID, err)
+		}
+		if cfg.CompactBelowBytes > 0 {
+			compacted, err := compactSession(cfg, sessionID)
+			if err != nil {
+				return report, fmt.Errorf("logservice: compacting session %s: %w", sessionID, err)
+			}
+			report.Compacted += compacted
+		}
+	}
+	return report, nil
+}
+
+// expireSession removes sessionID's oldest segments while they are
+// either past cfg.MaxAge or pushing the session over
+// cfg.MaxBytesPerSession. Segments are ordered oldest-first by
+// ListSegments, so the first segment that needs neither expiring nor
+// trimming means everything after it is newer and survives too.
+func expireSession(ctx context.Context, cfg RetentionConfig, sessionID string, report *RetentionReport) error {
+	segments, err := ListSegments(cfg.Dir, sessionID)
+	if err != nil {
+		return err
+	}
+
+	var totalBytes int64
+	for _, seg := range segments {
+		totalBytes += seg.SizeBytes
+	}
+
+	for _, seg := range segments {
+		expired := cfg.MaxAge > 0 && time.Since(seg.ModTime) > cfg.MaxAge
+		overBudget := cfg.MaxBytesPerSession > 0 && totalBytes > cfg.MaxBytesPerSession
+		if !expired && !overBudget {
+			break
+		}
+
+		if cfg.Archive != nil {
+			name := filepath.Join(sessionID, filepath.Base(seg.Path))
+			if err := cfg.Archive.Put(ctx, name, seg.Path); err != nil {
+				return fmt.Errorf("archiving %s: %w", seg.Path, err)
+			}
+		}
+		if err := os.Remove(seg.Path); err != nil {
+			return fmt.Errorf("removing %s: %w", seg.Path, err)
+		}
+		if err := os.Remove(indexPath(seg.Path)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing index for %s: %w", seg.Path, err)
+		}
+		if err := removeManifestEntry(cfg.Dir, sessionID, seg.ID); err != nil {
+			return fmt.Errorf("updating manifest after removing %s: %w", seg.Path, err)
+		}
+		totalBytes -= seg.SizeBytes
+		report.Deleted = append(report.Deleted, seg.Path)
+	}
+	return nil
+}
+
+// compactSession merges sessionID's segments under cfg.CompactBelowBytes
+// into a single new NDJSON segment spanning their combined sequence
+// range, returning how many segments it merged away. Segments at or
+// above cfg.CompactBelowBytes are left alone.
+func compactSession(cfg RetentionConfig, sessionID string) (int, error) {
+	segments, err := ListSegments(cfg.Dir, sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	var small []SegmentInfo
+	for _, seg := range segments {
+		if seg.SizeBytes < cfg.CompactBelowBytes {
+			small = append(small, seg)
+		}
+	}
+	if len(small) < 2 {
+		return 0, nil
+	}
+
+	var frames []Frame
+	var compactable []SegmentInfo
+	for _, seg := range small {
+		segFrames, err := readSegment(context.Background(), seg, 0, 1<<63-1, nil)
+		if errors.Is(err, ErrNoKeyProvider) {
+			// Compaction only merges segments it can already read
+			// without a key; an encrypted segment is left alone rather
+			// than failing the whole pass.
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("reading %s: %w", seg.Path, err)
+		}
+		frames = append(frames, segFrames...)
+		compactable = append(compactable, seg)
+	}
+	small = compactable
+	if len(small) < 2 {
+		return 0, nil
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i].Seq < frames[j].Seq })
+
+	dir := sessionDir(cfg.Dir, sessionID)
+	path := filepath.Join(dir, fmt.Sprintf("%d-%d.log.compacted", frames[0].Seq, frames[len(frames)-1].Seq))
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	if err := WriteNDJSONSegment(f, frames); err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+
+	for _, seg := range small {
+		if err := os.Remove(seg.Path); err != nil {
+			return 0, fmt.Errorf("removing %s after compaction: %w", seg.Path, err)
+		}
+		if err := os.Remove(indexPath(seg.Path)); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("removing index for %s after compaction: %w", seg.Path, err)
+		}
+		if err := removeManifestEntry(cfg.Dir, sessionID, seg.ID); err != nil {
+			return 0, fmt.Errorf("updating manifest after compacting %s: %w", seg.Path, err)
+		}
+	}
+
+	final := filepath.Join(dir, fmt.Sprintf("%d-%d.log", frames[0].Seq, frames[len(frames)-1].Seq))
+	if err := os.Rename(path, final); err != nil {
+		return 0, fmt.Errorf("finalizing compacted segment: %w", err)
+	}
+
+	idx, err := BuildIndex(FormatNDJSON, frames, 0)
+	if err != nil {
+		return 0, fmt.Errorf("building index for compacted segment: %w", err)
+	}
+	if err := WriteIndexFile(indexPath(final), idx); err != nil {
+		return 0, fmt.Errorf("writing index for compacted segment: %w", err)
+	}
+
+	finalInfo, err := os.Stat(final)
+	if err != nil {
+		return 0, fmt.Errorf("stat compacted segment: %w", err)
+	}
+	sum, err := segmentFileChecksum(final)
+	if err != nil {
+		return 0, err
+	}
+	if err := appendManifestEntry(cfg.Dir, sessionID, ManifestEntry{
+		SegmentID:  trimSegmentSuffix(filepath.Base(final)),
+		FromSeq:    frames[0].Seq,
+		ToSeq:      frames[len(frames)-1].Seq,
+		FrameCount: len(frames),
+		SizeBytes:  finalInfo.Size(),
+		Checksum:   sum,
+		ModTime:    finalInfo.ModTime(),
+	}); err != nil {
+		return 0, fmt.Errorf("updating manifest for compacted segment: %w", err)
+	}
+	return len(small), nil
+}
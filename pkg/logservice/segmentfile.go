@@ -0,0 +1,60 @@
+package logservice
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// segmentInfoFromFile builds a SegmentInfo from a segment file's full path,
+// the same parsing ListSegments applies to each directory entry, for
+// callers that have a bare segment path rather than a ListSegments result.
+func segmentInfoFromFile(path string) (SegmentInfo, error) {
+	name := filepath.Base(path)
+	m := segmentNameRE.FindStringSubmatch(name)
+	if m == nil {
+		return SegmentInfo{}, fmt.Errorf("logservice: %s does not look like a segment file", path)
+	}
+	fromSeq, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return SegmentInfo{}, fmt.Errorf("logservice: %s: malformed from_seq: %w", path, err)
+	}
+	toSeq, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil {
+		return SegmentInfo{}, fmt.Errorf("logservice: %s: malformed to_seq: %w", path, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return SegmentInfo{}, fmt.Errorf("logservice: stat %s: %w", path, err)
+	}
+	codec := codecForExtension(m[3])
+	return SegmentInfo{
+		ID:         trimSegmentSuffix(name),
+		Path:       path,
+		FromSeq:    fromSeq,
+		ToSeq:      toSeq,
+		Codec:      codec,
+		Compressed: codec != "",
+		SizeBytes:  info.Size(),
+		ModTime:    info.ModTime(),
+	}, nil
+}
+
+// ReadSegmentFile decodes every frame in the segment file at path,
+// independent of ListSegments' session-directory layout, for tools (like
+// "zenglow logexport") that operate on bare segment paths handed to them
+// rather than a session ID. keys decrypts the segment if it's
+// AES-GCM-encrypted (see DecryptSegment); nil is fine as long as it isn't.
+func ReadSegmentFile(ctx context.Context, path string, keys KeyProvider) ([]Frame, error) {
+	seg, err := segmentInfoFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	frames, err := readSegment(ctx, seg, seg.FromSeq, seg.ToSeq, keys)
+	if err != nil {
+		return nil, fmt.Errorf("logservice: reading segment %s: %w", seg.ID, err)
+	}
+	return frames, nil
+}
@@ -0,0 +1,238 @@
+package logservice
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// segmentNameRE matches "<from_seq>-<to_seq>.log", optionally suffixed
+// with a registered Compressor's Extension (".gz", ".zlib", ".flate")
+// or dictExtension (".fdict") for a flate-dict segment (see dict.go).
+// It deliberately doesn't match a trailing tmpSegmentSuffix, so a
+// segment still being rotated (or orphaned by a crash mid-rotation)
+// never shows up in ListSegments until RecoverOrphanedSegments or a
+// clean rotation finishes it.
+var segmentNameRE = regexp.MustCompile(`^(\d+)-(\d+)\.log(\.gz|\.zlib|\.flate|\.fdict)?$`)
+
+// tmpSegmentSuffix is appended to a segment's final path while
+// rotateLocked is still writing it, so the file only appears at its
+// real name once the write (and any configured fsync) has fully
+// succeeded. See RecoverOrphanedSegments for what finishes a
+// tmpSegmentSuffix file a crash left behind.
+const tmpSegmentSuffix = ".logtmp"
+
+// sessionDir returns the directory a session's segments live under.
+func sessionDir(root, sessionID string) string {
+	return filepath.Join(root, sessionID)
+}
+
+// tenantDir returns root partitioned by tenantID, so each tenant's
+// sessions land under their own subtree (root/tenantID/sessionID)
+// instead of sharing root/sessionID across tenants, where two tenants
+// picking the same sessionID would otherwise collide on disk. tenantID
+// == "" returns root unchanged, so a deployment that never sets
+// TenantID keeps the flat, single-tenant layout this package had before
+// multi-tenancy.
+func tenantDir(root, tenantID string) string {
+	if tenantID == "" {
+		return root
+	}
+	return filepath.Join(root, tenantID)
+}
+
+// ListSegments returns every segment on disk for sessionID under root,
+// ordered by FromSeq. root is a directory containing one subdirectory
+// per session, per the layout documented on this package.
+func ListSegments(root, sessionID string) ([]SegmentInfo, error) {
+	dir := sessionDir(root, sessionID)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("logservice: listing segments for session %s: %w", sessionID, err)
+	}
+
+	var segments []SegmentInfo
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		m := segmentNameRE.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		fromSeq, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		toSeq, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("logservice: stat segment %s: %w", entry.Name(), err)
+		}
+		codec := codecForExtension(m[3])
+		segments = append(segments, SegmentInfo{
+			ID:         trimSegmentSuffix(entry.Name()),
+			Path:       filepath.Join(dir, entry.Name()),
+			FromSeq:    fromSeq,
+			ToSeq:      toSeq,
+			Codec:      codec,
+			Compressed: codec != "",
+			SizeBytes:  info.Size(),
+			ModTime:    info.ModTime(),
+		})
+	}
+
+	sort.Slice(segments, func(i, j int) bool { return segments[i].FromSeq < segments[j].FromSeq })
+	return segments, nil
+}
+
+// trimSegmentSuffix strips the .log suffix, plus any codec extension, from
+// a segment file name, leaving the "<from_seq>-<to_seq>" ID.
+func trimSegmentSuffix(name string) string {
+	for _, suffix := range []string{".log.gz", ".log.zlib", ".log.flate", ".log.fdict", ".log"} {
+		if len(name) > len(suffix) && name[len(name)-len(suffix):] == suffix {
+			return name[:len(name)-len(suffix)]
+		}
+	}
+	return name
+}
+
+// ReadSession streams every Frame in [fromSeq, toSeq] across sessionID's
+// segments, in order, skipping segments whose range doesn't overlap the
+// request at all. It cannot decrypt AES-GCM-encrypted segments (see
+// EncryptSegment); use ReadSessionWithKeys for those.
+func ReadSession(root, sessionID string, fromSeq, toSeq int64) ([]Frame, error) {
+	return ReadSessionWithKeys(context.Background(), root, sessionID, fromSeq, toSeq, nil)
+}
+
+// ReadSessionWithKeys is ReadSession, additionally decrypting any
+// AES-GCM-encrypted segments in range via keys. keys may be nil as long
+// as none of the segments in [fromSeq, toSeq] are encrypted;
+// readSegment errors if it finds one and keys is nil.
+func ReadSessionWithKeys(ctx context.Context, root, sessionID string, fromSeq, toSeq int64, keys KeyProvider) ([]Frame, error) {
+	segments, err := ListSegments(root, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var frames []Frame
+	for _, seg := range segments {
+		if seg.ToSeq < fromSeq || seg.FromSeq > toSeq {
+			continue
+		}
+		segFrames, err := readSegment(ctx, seg, fromSeq, toSeq, keys)
+		if err != nil {
+			return nil, fmt.Errorf("logservice: reading segment %s: %w", seg.ID, err)
+		}
+		frames = append(frames, segFrames...)
+	}
+	return frames, nil
+}
+
+// readSegment decodes every Frame in seg whose Seq falls in [fromSeq, toSeq],
+// auto-detecting whether seg holds newline-delimited JSON frames, the
+// length-prefixed binary format WriteBinarySegment writes, or an
+// AES-GCM-encrypted segment wrapping either, decrypted via keys.
+func readSegment(ctx context.Context, seg SegmentInfo, fromSeq, toSeq int64, keys KeyProvider) ([]Frame, error) {
+	f, err := os.Open(seg.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	switch {
+	case seg.Codec == dictCodec:
+		dr, err := newDictReader(ctx, bufio.NewReaderSize(f, 64*1024), FileDictStore{Dir: dictsDirFor(seg.Path)})
+		if err != nil {
+			return nil, fmt.Errorf("opening %s stream: %w", seg.Codec, err)
+		}
+		defer dr.Close()
+		r = dr
+	case seg.Codec != "":
+		c, ok := CompressorByName(seg.Codec)
+		if !ok {
+			return nil, fmt.Errorf("unknown segment codec %q", seg.Codec)
+		}
+		cr, err := c.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("opening %s stream: %w", seg.Codec, err)
+		}
+		defer cr.Close()
+		r = cr
+	}
+
+	br := bufio.NewReaderSize(r, 64*1024)
+	peeked, err := br.Peek(len(encryptedMagic))
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("peeking segment header: %w", err)
+	}
+	if isEncryptedSegment(peeked) {
+		ciphertext, err := io.ReadAll(br)
+		if err != nil {
+			return nil, fmt.Errorf("reading encrypted segment: %w", err)
+		}
+		plaintext, err := DecryptSegment(ctx, keys, ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("decrypting segment: %w", err)
+		}
+		br = bufio.NewReaderSize(bytes.NewReader(plaintext), 64*1024)
+	}
+
+	peeked, err = br.Peek(len(binaryMagic))
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("peeking segment header: %w", err)
+	}
+	switch {
+	case isBinarySegment(peeked):
+		if _, err := br.Discard(len(binaryMagic) + 1); err != nil {
+			return nil, fmt.Errorf("reading segment header: %w", err)
+		}
+		return decodeBinaryFrames(br, fromSeq, toSeq)
+	case isMsgpackSegment(peeked):
+		if _, err := br.Discard(len(msgpackMagic) + 1); err != nil {
+			return nil, fmt.Errorf("reading segment header: %w", err)
+		}
+		return decodeMsgpackFrames(br, fromSeq, toSeq)
+	}
+	return decodeNDJSONFrames(br, fromSeq, toSeq)
+}
+
+// decodeNDJSONFrames reads one JSON-encoded Frame per line from r.
+func decodeNDJSONFrames(r *bufio.Reader, fromSeq, toSeq int64) ([]Frame, error) {
+	var frames []Frame
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var frame Frame
+		if err := json.Unmarshal(line, &frame); err != nil {
+			return nil, fmt.Errorf("decoding frame: %w", err)
+		}
+		if frame.Seq < fromSeq || frame.Seq > toSeq {
+			continue
+		}
+		frames = append(frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
@@ -0,0 +1,67 @@
+package logservice
+
+import (
+	"strings"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+)
+
+// SplitDirs parses dir as a comma-separated list of directories, the
+// same one-env-var-many-values convention pkg/nodeset/source.go's
+// EnvSource uses for its own comma-separated node lists. A single
+// directory with no comma returns a one-element slice, so callers don't
+// need a special case for the common, non-sharded LOG_DIR. Exported so
+// cmd/zenglow's maintenance commands (logrecover, logretention,
+// logverify), which scan every session under a root rather than one
+// session_id at a time, can loop over the same directory list
+// NewSessionWriter shards writes across instead of operating on only
+// the first one.
+func SplitDirs(dir string) []string {
+	var dirs []string
+	for _, d := range strings.Split(dir, ",") {
+		d = strings.TrimSpace(d)
+		if d == "" {
+			continue
+		}
+		dirs = append(dirs, d)
+	}
+	return dirs
+}
+
+// shardDir picks which of dirs sessionID's segments live under, via the
+// same HRW (rendezvous) routing pkg/hashring uses for the hrw CLI and
+// grpc-router. The assignment is recomputed from sessionID and dirs
+// alone on every call rather than recorded anywhere: HRW's whole point
+// is that it's a stateless, consistent function of (key, node set), so
+// "the mapping" the read APIs need is just calling shardDir with the
+// same dirs a write was made with, not a lookup table. The caveat that
+// comes with that: changing the configured directory list after
+// sessions already have segments on disk reshuffles where those
+// sessions' *future* writes land (existing segments don't move, nothing
+// reads or migrates them), the same rebalancing cost any HRW- or
+// hash-ring-routed system accepts in exchange for not keeping a
+// manifest in sync.
+//
+// len(dirs) <= 1 returns dirs[0] (or "" if dirs is empty) without
+// touching pkg/hashring, so the common single-directory deployment
+// neither pays for nor depends on it.
+func shardDir(dirs []string, sessionID string) string {
+	switch len(dirs) {
+	case 0:
+		return ""
+	case 1:
+		return dirs[0]
+	default:
+		return hashring.RouteStrings(sessionID, dirs)
+	}
+}
+
+// sessionRoot resolves dir (sessionRoot's callers pass WriterConfig.Dir
+// or Handler's root, either of which may be a splitDirs-style
+// comma-separated list) and tenantID down to the single directory
+// sessionID's segments live under: shardDir picks the disk, tenantDir
+// then partitions within it, in the same order NewSessionWriter applies
+// them when it first creates that session's segments.
+func sessionRoot(dir, tenantID, sessionID string) string {
+	return tenantDir(shardDir(SplitDirs(dir), sessionID), tenantID)
+}
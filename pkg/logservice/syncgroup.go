@@ -0,0 +1,70 @@
+package logservice
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// SyncGroup batches the fsyncs WriterConfig.Fsync triggers across many
+// SessionWriters: rotateLocked queues its newly-written segment file and
+// blocks until the group's next tick fsyncs every file queued since,
+// instead of every rotation paying its own fsync round-trip serially.
+// Durability is unchanged — rotateLocked still blocks until its file's
+// fsync actually completes — only the scheduling of when fsyncs happen
+// is batched, the same trade the group-commit pattern makes for WAL
+// writers sharing one log file, adapted to this package's one-file-per-
+// session layout where the syscalls themselves can't be merged.
+//
+// A nil *SyncGroup is not valid; SessionWriters that want individual,
+// unbatched fsyncs should leave WriterConfig.SyncGroup nil and just set
+// WriterConfig.Fsync.
+type SyncGroup struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	pending []syncRequest
+	timer   *time.Timer
+}
+
+type syncRequest struct {
+	file *os.File
+	done chan error
+}
+
+// NewSyncGroup returns a SyncGroup that fsyncs every file queued via
+// Sync within window of the first request in each batch. Typical values
+// are 5-20ms: long enough for concurrent rotations across many sessions
+// to land in the same batch, short enough not to meaningfully delay any
+// one of them.
+func NewSyncGroup(window time.Duration) *SyncGroup {
+	return &SyncGroup{window: window}
+}
+
+// Sync queues f to be fsynced with the group's current batch, blocking
+// until that batch runs and reporting f.Sync's result.
+func (g *SyncGroup) Sync(f *os.File) error {
+	req := syncRequest{file: f, done: make(chan error, 1)}
+
+	g.mu.Lock()
+	g.pending = append(g.pending, req)
+	if g.timer == nil {
+		g.timer = time.AfterFunc(g.window, g.flush)
+	}
+	g.mu.Unlock()
+
+	return <-req.done
+}
+
+// flush fsyncs every file queued since the batch's timer was armed.
+func (g *SyncGroup) flush() {
+	g.mu.Lock()
+	batch := g.pending
+	g.pending = nil
+	g.timer = nil
+	g.mu.Unlock()
+
+	for _, req := range batch {
+		req.done <- req.file.Sync()
+	}
+}
@@ -0,0 +1,43 @@
+package logservice
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// ServerTLSConfig builds a *tls.Config for Handler/WriteStreamHandler's
+// listener to serve over mTLS, so a mobile/edge client writing frames
+// over WriteStreamHandler can't do so in plaintext in production.
+// clientCAFile's pool is required to verify every connecting client's
+// certificate; there is no unauthenticated fallback.
+//
+// This package has no main of its own — the binary embedding Handler
+// and WriteStreamHandler (see their doc comments for the zero-call-site
+// caveat that's still true today) is expected to load cert/key/client-CA
+// paths from its own flags/env the way cmd/grpc-router does for
+// -spiffe-cert/-spiffe-key/-spiffe-bundle, call ServerTLSConfig, and set
+// the result as http.Server.TLSConfig before calling ListenAndServeTLS
+// with empty cert/key file arguments.
+func ServerTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("logservice: loading server certificate: %w", err)
+	}
+
+	pem, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("logservice: reading client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("logservice: no certificates found in %s", clientCAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package logservice
+
+import (
+	"context"
+	"hash/crc32"
+)
+
+// checksum computes Frame's per-frame checksum. The request asks for
+// xxhash, but this repo has no way to vendor a non-stdlib hash (the same
+// constraint behind every other "can't vendor X" call in this package);
+// stdlib hash/crc32 gives the same tamper/bit-rot detection a fixed,
+// append-only frame needs.
+func checksum(data []byte) uint32 {
+	return crc32.ChecksumIEEE(data)
+}
+
+// VerifyFrame reports whether frame's Checksum matches one computed over
+// its Data right now.
+func VerifyFrame(frame Frame) bool {
+	return frame.Checksum == checksum(frame.Data)
+}
+
+// SegmentVerifyReport is the result of VerifySegment.
+type SegmentVerifyReport struct {
+	SegmentID string
+	// FramesRead is how many frames VerifySegment could decode from the
+	// segment at all; it only reflects structural (JSON/binary framing)
+	// failures, not checksum mismatches.
+	FramesRead int
+	// Corrupt lists the Seq of every decoded frame whose checksum didn't
+	// match its Data.
+	Corrupt []int64
+	// LastValidSeq is the highest Seq verified clean before the first
+	// corrupt frame (or -1 if none were). A single corrupt record can
+	// desync what "valid" means for anything after it, so this is not
+	// simply the maximum Seq seen.
+	LastValidSeq int64
+	// Err is set if the segment couldn't be decoded at all (I/O error,
+	// truncated framing, or an encrypted segment with no usable key).
+	Err error
+}
+
+// VerifySegment decodes every frame in seg and checksums each one.
+func VerifySegment(ctx context.Context, seg SegmentInfo, keys KeyProvider) SegmentVerifyReport {
+	report := SegmentVerifyReport{SegmentID: seg.ID, LastValidSeq: -1}
+
+	frames, err := readSegment(ctx, seg, 0, 1<<63-1, keys)
+	if err != nil {
+		report.Err = err
+		return report
+	}
+
+	report.FramesRead = len(frames)
+	for _, frame := range frames {
+		if !VerifyFrame(frame) {
+			report.Corrupt = append(report.Corrupt, frame.Seq)
+			continue
+		}
+		if len(report.Corrupt) == 0 {
+			report.LastValidSeq = frame.Seq
+		}
+	}
+	return report
+}
+
+// SessionVerifyReport is the result of VerifySession.
+type SessionVerifyReport struct {
+	SessionID    string
+	Segments     []SegmentVerifyReport
+	LastValidSeq int64
+}
+
+// VerifySession verifies every segment for sessionID under root, in
+// order, stopping at the first segment that doesn't decode cleanly or
+// contains a checksum mismatch: everything after an already-broken
+// segment can't be trusted to be sequence-contiguous either.
+func VerifySession(ctx context.Context, root, sessionID string, keys KeyProvider) (SessionVerifyReport, error) {
+	segments, err := ListSegments(root, sessionID)
+	if err != nil {
+		return SessionVerifyReport{}, err
+	}
+
+	report := SessionVerifyReport{SessionID: sessionID, LastValidSeq: -1}
+	for _, seg := range segments {
+		segReport := VerifySegment(ctx, seg, keys)
+		report.Segments = append(report.Segments, segReport)
+
+		if segReport.Err != nil {
+			// The segment didn't decode at all, so it has nothing to
+			// report a LastValidSeq from; keep whatever the last clean
+			// segment already established.
+			break
+		}
+		report.LastValidSeq = segReport.LastValidSeq
+		if len(segReport.Corrupt) > 0 {
+			break
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,619 @@
+package logservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/ratelimit"
+	"github.com/cgb808/ZenGlowNext/pkg/telemetry"
+)
+
+// WriterConfig configures a SessionWriter via pkg/config, using the same
+// LOG_MAX_SIZE_BYTES env var name the original size-only rotation
+// trigger used, plus LOG_MAX_SEGMENT_AGE for the age trigger and
+// LOG_DIR/LOG_SEGMENT_FORMAT for this package's own additions.
+type WriterConfig struct {
+	// Dir is a single directory, or a comma-separated list of them (see
+	// splitDirs), to spread segment I/O across more than one disk. A
+	// list shards sessions across its entries by HRW on session_id (see
+	// shardDir/sessionRoot); a single directory behaves exactly as
+	// before.
+	Dir           string        `env:"LOG_DIR,required"`
+	MaxSizeBytes  int64         `env:"LOG_MAX_SIZE_BYTES"`
+	MaxSegmentAge time.Duration `env:"LOG_MAX_SEGMENT_AGE"`
+	Format        SegmentFormat `env:"LOG_SEGMENT_FORMAT"`
+
+	// Compress names a registered Compressor ("gzip", "zlib", "flate")
+	// to compress each rotated segment with, or "" to leave segments
+	// uncompressed. See CompressorByName. Ignored when DictID/Dict are
+	// both set — dictionary compression takes over rotation-time
+	// compression entirely rather than stacking with it.
+	Compress string `env:"LOG_COMPRESS"`
+
+	// DictID and Dict, if both set, flate-compress each rotated segment
+	// against Dict as a preset dictionary (see dict.go) instead of
+	// whatever Compress names, recording DictID in the segment's header
+	// so a reader can look the same dictionary bytes back up later (see
+	// DictProvider) even after a fresher TrainDictionary run replaces
+	// these fields. Like EncryptKeyID/EncryptKey, Dict is resolved once
+	// (by calling TrainDictionary, or via AdminHandler's
+	// /admin/dict/train) rather than loaded with `env`, since pkg/config
+	// only parses scalar field types.
+	DictID string
+	Dict   []byte
+
+	// Fsync, when true, fsyncs every rotated segment before rotateLocked
+	// returns, so a caller that only considers a write durable once
+	// Flush/Write returns (WriteStreamHandler's ack-every-N, in
+	// particular) isn't lying about that. SyncGroup, if set, batches
+	// those fsyncs across every SessionWriter sharing it instead of each
+	// calling f.Sync() inline; leave it nil for an unbatched fsync per
+	// rotation.
+	Fsync     bool `env:"LOG_FSYNC"`
+	SyncGroup *SyncGroup
+
+	// MaxBufferedFrames bounds how many frames SessionWriter will hold in
+	// memory before Backpressure kicks in. Zero disables the limit,
+	// preserving the original unbounded behavior.
+	MaxBufferedFrames int                `env:"LOG_MAX_BUFFERED_FRAMES"`
+	Backpressure      BackpressurePolicy `env:"LOG_BACKPRESSURE_POLICY"`
+
+	// EncryptKeyID and EncryptKey, if both set, AES-GCM-encrypt each
+	// rotated segment under EncryptKeyID via EncryptSegment before it
+	// hits disk. EncryptKey is resolved once at startup (e.g. via a
+	// SecretsKeyProvider) rather than loaded with `env`, since pkg/config
+	// only parses scalar field types and key material shouldn't round-trip
+	// through a plain string field anyway.
+	EncryptKeyID string
+	EncryptKey   []byte
+
+	// Metrics, if set, records frames received/dropped, bytes written,
+	// and rotations from this SessionWriter. See Metrics's doc comment
+	// for why this isn't `env`-tagged. A nil Metrics records nothing.
+	Metrics *Metrics
+
+	// RateLimit, if set, is consulted by WriteStreamHandler before each
+	// frame is written, keyed by "<subject>:<session_id>" so one
+	// over-eager client can't starve disk and the Redis publish queue
+	// for every other session sharing the same WriterConfig. nil means
+	// no limit, preserving the original unbounded behavior.
+	RateLimit ratelimit.Limiter
+
+	// Quota, if set, bounds bytes written and concurrent sessions per
+	// tenant, the same shared-pointer-on-WriterConfig shape Metrics and
+	// RateLimit already use so every SessionWriter sharing this config
+	// enforces against the same counters. nil means no limit.
+	Quota *TenantQuota
+
+	// Levels, if set, drops or samples frames by Frame.Level before
+	// they're buffered or counted against MaxBufferedFrames/Quota, the
+	// same shared-pointer-on-WriterConfig shape Metrics/RateLimit/Quota
+	// already use. nil keeps every frame regardless of Level.
+	Levels *LevelFilter
+}
+
+// encrypted reports whether cfg is configured to encrypt rotated
+// segments.
+func (cfg WriterConfig) encrypted() bool {
+	return cfg.EncryptKeyID != "" && len(cfg.EncryptKey) > 0
+}
+
+// SessionWriter buffers one session's frames and rotates them into a new
+// segment file, named "<from_seq>-<to_seq>.log" per the layout
+// ListSegments/ReadSession expect, whenever Config.MaxSizeBytes or
+// Config.MaxSegmentAge is exceeded. A zero MaxSizeBytes or
+// MaxSegmentAge disables that trigger.
+type SessionWriter struct {
+	Config    WriterConfig
+	SessionID string
+	// TenantID partitions where this session's segments land on disk
+	// (see tenantDir) and which of Config.Quota's counters its writes
+	// count against. Empty means the flat, single-tenant layout.
+	TenantID string
+
+	mu            sync.Mutex
+	root          string // cfg.Dir partitioned by TenantID; see tenantDir
+	frames        []Frame
+	sizeBytes     int64
+	openedAt      time.Time
+	nextSeq       int64
+	lastCommitted int64
+	lastActivity  time.Time
+	stats         WriterStats
+	subscribers   map[chan Frame]struct{}
+}
+
+// subscriberBufferSize bounds how many frames TailHandler's subscriber
+// channel will hold before Write starts dropping the newest frame for
+// it, the same lossy-slow-reader tradeoff Config.Backpressure already
+// accepts for the buffered-frames path — a live tail falling behind
+// loses frames rather than blocking every other write to this session.
+const subscriberBufferSize = 64
+
+// NewSessionWriter returns a SessionWriter for tenantID's sessionID,
+// configured by cfg. It seeds its dedup state from sessionID's existing
+// segments under cfg.Dir (sharded across cfg.Dir's directory list, if
+// it has more than one, and partitioned by tenantID; see sessionRoot),
+// so a client reconnecting after a crash and resending frames with
+// explicit Seq values it already had acked doesn't double-write them to
+// a new SessionWriter instance. tenantID == "" preserves the original
+// flat, single-tenant layout.
+func NewSessionWriter(cfg WriterConfig, tenantID, sessionID string) *SessionWriter {
+	root := sessionRoot(cfg.Dir, tenantID, sessionID)
+	lastCommitted := lastCommittedSeqOnDisk(root, sessionID)
+
+	w := &SessionWriter{
+		Config:        cfg,
+		SessionID:     sessionID,
+		TenantID:      tenantID,
+		root:          root,
+		lastCommitted: lastCommitted,
+		lastActivity:  time.Now(),
+	}
+	// Seeds the gap-detection baseline (see recordSeqGapLocked) from
+	// wherever this session left off, the same ambiguity
+	// lastCommittedSeqOnDisk's own doc comment already accepts: 0 means
+	// either a fresh session or one whose last committed frame happened
+	// to be Seq 0, and either way nextSeq starting at 0 is the right
+	// call for it.
+	if lastCommitted > 0 {
+		w.nextSeq = lastCommitted + 1
+	}
+	return w
+}
+
+// Write buffers frame, assigning it the next sequence number if Seq is
+// zero, rotating the current segment first if appending it would exceed
+// Config.MaxSizeBytes. It returns the Seq frame was actually assigned,
+// so a caller that left Seq zero (WriteStreamHandler's ack-every-N
+// acking, in particular) can still report exactly what it wrote.
+//
+// A frame with an explicit Seq at or below the session's last committed
+// Seq is treated as a retry of an already-durable write and silently
+// skipped rather than appended again, so a client resending its
+// unacked buffer after a reconnect doesn't duplicate lines that made it
+// to disk before the connection dropped.
+func (w *SessionWriter) Write(frame Frame) (seq int64, rotated bool, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if frame.Seq != 0 && frame.Seq <= w.lastCommitted {
+		return frame.Seq, false, nil
+	}
+
+	if frame.Seq == 0 {
+		frame.Seq = w.nextSeq
+	} else if frame.Seq > w.nextSeq {
+		w.recordSeqGapLocked(frame.Seq - w.nextSeq)
+	}
+	w.nextSeq = frame.Seq + 1
+
+	// Levels is checked after Seq bookkeeping (so a dropped or sampled-
+	// out frame still advances nextSeq and doesn't read as a gap to the
+	// next real frame — see recordSeqGapLocked) but before everything
+	// else, so a filtered-out frame costs nothing: no buffering, no
+	// quota/backpressure accounting, no bytes written.
+	if !w.Config.Levels.Allow(w.SessionID, Level(frame.Level)) {
+		w.Config.Metrics.incFramesFiltered()
+		return frame.Seq, rotated, nil
+	}
+
+	if frame.Timestamp.IsZero() {
+		frame.Timestamp = time.Now()
+	}
+
+	encoded, err := json.Marshal(frame)
+	if err != nil {
+		return 0, false, fmt.Errorf("logservice: encoding frame %d: %w", frame.Seq, err)
+	}
+
+	if err := w.Config.Quota.checkAndAddBytes(w.TenantID, int64(len(encoded))); err != nil {
+		return 0, false, err
+	}
+
+	if w.Config.MaxSizeBytes > 0 && len(w.frames) > 0 && w.sizeBytes+int64(len(encoded)) > w.Config.MaxSizeBytes {
+		if err := w.rotateLocked(); err != nil {
+			return 0, false, err
+		}
+		rotated = true
+	}
+
+	ok, err := w.applyBackpressureLocked(frame)
+	if err != nil {
+		return 0, rotated, err
+	}
+	if !ok {
+		return frame.Seq, rotated, nil
+	}
+
+	if w.openedAt.IsZero() {
+		w.openedAt = time.Now()
+	}
+	w.frames = append(w.frames, frame)
+	w.sizeBytes += int64(len(encoded))
+	w.lastActivity = time.Now()
+	w.Config.Metrics.incFramesReceived()
+	w.publishLocked(frame)
+	return frame.Seq, rotated, nil
+}
+
+// Subscribe registers a new live subscriber to every frame Write
+// buffers for w from here on, for TailHandler's live tail — unlike
+// Replay, which only ever sees a snapshot of what's buffered or
+// rotated at the moment it's called. cancel removes and closes the
+// subscription; callers must call it once done reading, the same as
+// WriterRegistry.Unregister pairs with Register.
+func (w *SessionWriter) Subscribe() (frames <-chan Frame, cancel func()) {
+	ch := make(chan Frame, subscriberBufferSize)
+	w.mu.Lock()
+	if w.subscribers == nil {
+		w.subscribers = make(map[chan Frame]struct{})
+	}
+	w.subscribers[ch] = struct{}{}
+	w.mu.Unlock()
+
+	return ch, func() {
+		w.mu.Lock()
+		delete(w.subscribers, ch)
+		w.mu.Unlock()
+		close(ch)
+	}
+}
+
+// publishLocked fans frame out to every live subscriber, dropping it
+// for any subscriber whose channel is already full rather than
+// blocking Write on a slow reader. Callers must hold w.mu.
+func (w *SessionWriter) publishLocked(frame Frame) {
+	for ch := range w.subscribers {
+		select {
+		case ch <- frame:
+		default:
+		}
+	}
+}
+
+// LastActivity returns the last time Write buffered a frame for w.
+// WriterRegistry.ReapIdle uses this to decide when a writer has gone
+// quiet long enough to flush and evict.
+func (w *SessionWriter) LastActivity() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastActivity
+}
+
+// RotateIfStale rotates the current segment if Config.MaxSegmentAge has
+// elapsed since it was opened, even though Config.MaxSizeBytes hasn't
+// been reached yet. Run calls this on a ticker so a low-traffic session
+// still produces segments regularly instead of growing one segment
+// forever.
+func (w *SessionWriter) RotateIfStale() (rotated bool, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.Config.MaxSegmentAge <= 0 || len(w.frames) == 0 || w.openedAt.IsZero() {
+		return false, nil
+	}
+	if time.Since(w.openedAt) < w.Config.MaxSegmentAge {
+		return false, nil
+	}
+	if err := w.rotateLocked(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Run polls RotateIfStale every interval until ctx is done. One Run
+// call per SessionWriter is the "ticker per session writer" the
+// max-age rotation trigger needs, since only the writer holding the
+// buffer knows when it was opened.
+func (w *SessionWriter) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.RotateIfStale(); err != nil {
+				log.Printf("logservice: rotating stale segment for session %s: %v", w.SessionID, err)
+			}
+		}
+	}
+}
+
+// Flush rotates any buffered frames into a segment regardless of size or
+// age, e.g. on shutdown so nothing buffered is lost.
+func (w *SessionWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotateLocked()
+}
+
+// BufferedFrames returns a copy of the frames currently buffered in
+// memory, not yet rotated to a segment file on disk. Replay reads these
+// through a WriterRegistry so a client reconnecting mid-segment after a
+// crash can recover up to its last written frame, not just its last
+// rotated one.
+func (w *SessionWriter) BufferedFrames() []Frame {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	frames := make([]Frame, len(w.frames))
+	copy(frames, w.frames)
+	return frames
+}
+
+// WriterRegistry tracks each session's active SessionWriter, so Replay
+// can include frames still buffered in memory alongside what's already
+// been rotated to disk. It also drives idle reaping (see ReapIdle) and,
+// if SetMetrics is called, reports its active writer count.
+type WriterRegistry struct {
+	mu      sync.Mutex
+	writers map[string]*SessionWriter
+	metrics *telemetry.Registry
+}
+
+// NewWriterRegistry returns an empty WriterRegistry.
+func NewWriterRegistry() *WriterRegistry {
+	return &WriterRegistry{writers: make(map[string]*SessionWriter)}
+}
+
+// SetMetrics reports reg's active writer count on metrics' activeWritersMetric
+// gauge, updated on every Register, Unregister, and ReapIdle call.
+func (reg *WriterRegistry) SetMetrics(metrics *telemetry.Registry) {
+	reg.mu.Lock()
+	reg.metrics = metrics
+	reg.mu.Unlock()
+	reg.reportActive()
+}
+
+// Register records w as the active writer for its TenantID/SessionID.
+// Callers should Unregister it once it stops accepting writes (shutdown,
+// idle eviction), so Replay falls back to rotated segments alone.
+func (reg *WriterRegistry) Register(w *SessionWriter) {
+	reg.mu.Lock()
+	reg.writers[writerKey(w.TenantID, w.SessionID)] = w
+	reg.mu.Unlock()
+	reg.reportActive()
+}
+
+// Unregister removes tenantID's sessionID's active writer, if any.
+func (reg *WriterRegistry) Unregister(tenantID, sessionID string) {
+	reg.mu.Lock()
+	delete(reg.writers, writerKey(tenantID, sessionID))
+	reg.mu.Unlock()
+	reg.reportActive()
+}
+
+// writerKey composes WriterRegistry's map key from a tenant and session
+// ID, so two tenants that happen to pick the same sessionID don't
+// collide on the same active writer. A null byte separates the two
+// since it can't appear in either ID coming from a URL query parameter.
+func writerKey(tenantID, sessionID string) string {
+	return tenantID + "\x00" + sessionID
+}
+
+// reportActive sets the active writer gauge to the registry's current
+// writer count, if SetMetrics has been called.
+func (reg *WriterRegistry) reportActive() {
+	reg.mu.Lock()
+	metrics := reg.metrics
+	n := len(reg.writers)
+	reg.mu.Unlock()
+	if metrics != nil {
+		metrics.Gauge(activeWritersMetric).Set(float64(n))
+	}
+}
+
+// Buffered returns tenantID's sessionID's active writer's currently
+// buffered frames, or nil if no writer is registered for it.
+func (reg *WriterRegistry) Buffered(tenantID, sessionID string) []Frame {
+	w := reg.writerFor(tenantID, sessionID)
+	if w == nil {
+		return nil
+	}
+	return w.BufferedFrames()
+}
+
+// writerFor returns tenantID's sessionID's active writer, or nil if none
+// is registered. AdminHandler uses this to run a one-off action (force-
+// rotate, close) against a single session's writer.
+func (reg *WriterRegistry) writerFor(tenantID, sessionID string) *SessionWriter {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	return reg.writers[writerKey(tenantID, sessionID)]
+}
+
+// nopWriteCloser adapts an io.Writer that doesn't need closing (the
+// destination segment file itself, when rotateLocked isn't compressing)
+// to the io.WriteCloser every other branch of newWriter already returns,
+// so rotateLocked has one code path regardless of which (if any) codec
+// is active.
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// rotateLocked writes the buffered frames to a new segment file and
+// resets the buffer. Callers must hold w.mu.
+func (w *SessionWriter) rotateLocked() error {
+	if len(w.frames) == 0 {
+		return nil
+	}
+
+	dir := sessionDir(w.root, w.SessionID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("logservice: creating %s: %w", dir, err)
+	}
+
+	fromSeq := w.frames[0].Seq
+	toSeq := w.frames[len(w.frames)-1].Seq
+
+	useDict := w.Config.DictID != "" && len(w.Config.Dict) > 0
+
+	var compressor Compressor
+	if !useDict && w.Config.Compress != "" {
+		c, ok := CompressorByName(w.Config.Compress)
+		if !ok {
+			return fmt.Errorf("logservice: unknown compression codec %q", w.Config.Compress)
+		}
+		compressor = c
+	}
+
+	ext := ".log"
+	switch {
+	case useDict:
+		ext += dictExtension
+	case compressor != nil:
+		ext += compressor.Extension()
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%d-%d%s", fromSeq, toSeq, ext))
+	// Written to tmpPath first and renamed into place once fully written,
+	// so a crash mid-rotation leaves an orphaned ".logtmp" file instead of
+	// a truncated file sitting at path — ListSegments' segmentNameRE
+	// never matches a ".logtmp" suffix, so a half-written rotation can't
+	// silently corrupt a read. RecoverOrphanedSegments is the startup
+	// pass that finds these and finishes what rotateLocked didn't.
+	tmpPath := path + tmpSegmentSuffix
+
+	if useDict {
+		// Saved on every rotation rather than once when the dictionary
+		// is trained, so a fresh root (a new HRW shard, or a directory
+		// an operator hasn't synced the store to yet) still gets the
+		// mapping it needs the first time this dictionary is actually
+		// used to write into it. Writing the same bytes under the same
+		// content-addressed ID repeatedly is a cheap no-op.
+		store := FileDictStore{Dir: filepath.Join(w.root, dictStoreSubdir)}
+		if err := store.Save(w.Config.DictID, w.Config.Dict); err != nil {
+			return fmt.Errorf("logservice: saving dictionary %s: %w", w.Config.DictID, err)
+		}
+	}
+
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("logservice: creating segment %s: %w", tmpPath, err)
+	}
+	defer f.Close()
+
+	newWriter := func(dst io.Writer) (io.WriteCloser, error) {
+		switch {
+		case useDict:
+			return newDictWriter(dst, w.Config.DictID, w.Config.Dict)
+		case compressor != nil:
+			return compressor.NewWriter(dst)
+		default:
+			return nopWriteCloser{dst}, nil
+		}
+	}
+	codecName := w.Config.Compress
+	if useDict {
+		codecName = dictCodec
+	}
+
+	if w.Config.encrypted() {
+		var buf bytes.Buffer
+		if err := WriteSegment(w.Config.Format, &buf, w.frames); err != nil {
+			return fmt.Errorf("logservice: encoding segment %s: %w", path, err)
+		}
+		ciphertext, err := EncryptSegment(w.Config.EncryptKeyID, w.Config.EncryptKey, buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("logservice: encrypting segment %s: %w", path, err)
+		}
+		cw, err := newWriter(f)
+		if err != nil {
+			return fmt.Errorf("logservice: starting %s compression for %s: %w", codecName, path, err)
+		}
+		if _, err := cw.Write(ciphertext); err != nil {
+			return fmt.Errorf("logservice: writing segment %s: %w", path, err)
+		}
+		if err := cw.Close(); err != nil {
+			return fmt.Errorf("logservice: closing %s stream for %s: %w", codecName, path, err)
+		}
+	} else {
+		cw, err := newWriter(f)
+		if err != nil {
+			return fmt.Errorf("logservice: starting %s compression for %s: %w", codecName, path, err)
+		}
+		if err := WriteSegment(w.Config.Format, cw, w.frames); err != nil {
+			return fmt.Errorf("logservice: writing segment %s: %w", path, err)
+		}
+		if err := cw.Close(); err != nil {
+			return fmt.Errorf("logservice: closing %s stream for %s: %w", codecName, path, err)
+		}
+	}
+
+	if w.Config.Fsync {
+		var syncErr error
+		if w.Config.SyncGroup != nil {
+			syncErr = w.Config.SyncGroup.Sync(f)
+		} else {
+			syncErr = f.Sync()
+		}
+		if syncErr != nil {
+			return fmt.Errorf("logservice: fsyncing segment %s: %w", path, syncErr)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("logservice: closing segment %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("logservice: finalizing segment %s: %w", path, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("logservice: stat segment %s: %w", path, err)
+	}
+	sum, err := segmentFileChecksum(path)
+	if err != nil {
+		return err
+	}
+	if err := appendManifestEntry(w.root, w.SessionID, ManifestEntry{
+		SegmentID:  trimSegmentSuffix(filepath.Base(path)),
+		FromSeq:    fromSeq,
+		ToSeq:      toSeq,
+		FrameCount: len(w.frames),
+		SizeBytes:  info.Size(),
+		Checksum:   sum,
+		ModTime:    info.ModTime(),
+	}); err != nil {
+		return fmt.Errorf("logservice: updating manifest for segment %s: %w", path, err)
+	}
+
+	// BuildIndex's offsets are byte positions in the uncompressed frame
+	// stream WriteSegment would produce; they don't locate anything in a
+	// compressed segment file, so skip writing one rather than ship an
+	// index that looks usable but seeks to the wrong place. A
+	// dictionary-compressed (.fdict) segment is just as compressed as
+	// one under compressor, even though compressor itself is nil for
+	// that path (see useDict above), so it needs excluding here too.
+	if compressor == nil && !useDict {
+		idx, err := BuildIndex(w.Config.Format, w.frames, 0)
+		if err != nil {
+			return fmt.Errorf("logservice: building index for segment %s: %w", path, err)
+		}
+		if err := WriteIndexFile(indexPath(path), idx); err != nil {
+			return fmt.Errorf("logservice: writing index for segment %s: %w", path, err)
+		}
+	}
+
+	if toSeq > w.lastCommitted {
+		w.lastCommitted = toSeq
+	}
+	w.Config.Metrics.incRotations()
+	w.Config.Metrics.addBytesWritten(w.sizeBytes)
+	w.frames = nil
+	w.sizeBytes = 0
+	w.openedAt = time.Time{}
+	return nil
+}
@@ -0,0 +1,357 @@
+package logservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/apierror"
+	"github.com/cgb808/ZenGlowNext/pkg/authn"
+	"github.com/cgb808/ZenGlowNext/pkg/wswire"
+)
+
+// WriteBatchHandler serves POST /write, the unary counterpart to
+// WriteStreamHandler for a producer (a cron job, a one-off CLI
+// invocation) that has a handful of lines to append and doesn't want to
+// hold a WebSocket open for them. pkg/client.LogServiceClient.WriteLogStream
+// is the client for this route, despite its name — see that method's own
+// doc comment — and WriteStreamAck's doc comment already describes this
+// route's single-ack-at-the-end behavior, which predates this handler
+// actually existing.
+//
+// The request body is {"lines": ["...", ...]}, the same shape
+// WriteLogStream sends; each line becomes one Frame with Data set to its
+// bytes, written in order through a SessionWriter scoped to this request
+// alone (unlike WriteStreamHandler's, it's never registered with live,
+// since there's no connection lifetime for Replay to track it against).
+// The response is {"segment_id": "<from_seq>-<to_seq>"}, identifying the
+// segment every line was rotated into — computed from the first and last
+// Seq Write assigned rather than returned by Flush, since rotateLocked
+// doesn't report the segment it created. If every line is dropped by
+// cfg.Levels (see level.go), the returned segment_id won't actually
+// exist on disk, the same way an all-filtered WriteStreamHandler batch
+// never triggers a rotation.
+//
+// Authentication, rate limiting, and the optional tenant_id partitioning
+// all follow WriteStreamHandler's rules (see its doc comment) so the two
+// routes enforce the same policy regardless of which one a client picks.
+func WriteBatchHandler(store *ConfigStore, live *WriterRegistry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		cfg := store.Load()
+		defer observeSince(cfg.Metrics, "write", start)
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "logservice: POST required", http.StatusMethodNotAllowed)
+			return
+		}
+
+		id, ok := authn.FromContext(r.Context())
+		if !ok {
+			apierror.WriteHTTP(w, apierror.Unauthenticated("logservice: write batch requires an authenticated caller"))
+			return
+		}
+
+		sessionID := r.URL.Query().Get("session_id")
+		if sessionID == "" {
+			http.Error(w, "logservice: session_id is required", http.StatusBadRequest)
+			return
+		}
+		tenantID := r.URL.Query().Get("tenant_id")
+
+		var body struct {
+			Lines []string `json:"lines"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "logservice: decoding request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(body.Lines) == 0 {
+			http.Error(w, "logservice: lines must not be empty", http.StatusBadRequest)
+			return
+		}
+
+		if err := cfg.Quota.acquireSession(tenantID); err != nil {
+			if apiErr, ok := apierror.As(err); ok {
+				apierror.WriteHTTP(w, apiErr)
+				return
+			}
+			http.Error(w, "logservice: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer cfg.Quota.releaseSession(tenantID)
+
+		// If a WriteStreamHandler connection already has this session's
+		// writer live, reuse it rather than constructing a second
+		// SessionWriter seeded from whatever's already on disk: the live
+		// writer may still have frames buffered in memory that a
+		// disk-seeded nextSeq wouldn't know about, and writing through a
+		// second instance concurrently would reassign Seq numbers the
+		// live writer already handed out.
+		var writer *SessionWriter
+		if live != nil {
+			writer = live.writerFor(tenantID, sessionID)
+		}
+		if writer == nil {
+			writer = NewSessionWriter(cfg, tenantID, sessionID)
+		}
+
+		var firstSeq, lastSeq int64
+		for i, line := range body.Lines {
+			if cfg.RateLimit != nil {
+				allowed, err := cfg.RateLimit.Allow(r.Context(), fmt.Sprintf("%s:%s", id.Subject, sessionID))
+				if err != nil {
+					http.Error(w, "logservice: checking rate limit: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if !allowed {
+					apierror.WriteHTTP(w, apierror.QuotaExceeded("logservice: rate limit exceeded for this session"))
+					return
+				}
+			}
+
+			frame := Frame{Data: []byte(line), Principal: id.Subject, TenantID: tenantID}
+			seq, _, err := writer.Write(frame)
+			if err != nil {
+				if apiErr, ok := apierror.As(err); ok {
+					apierror.WriteHTTP(w, apiErr)
+					return
+				}
+				http.Error(w, fmt.Sprintf("logservice: writing line %d: %v", i, err), http.StatusInternalServerError)
+				return
+			}
+			if i == 0 {
+				firstSeq = seq
+			}
+			lastSeq = seq
+		}
+
+		if err := writer.Flush(); err != nil {
+			http.Error(w, "logservice: flushing batch: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"segment_id": fmt.Sprintf("%d-%d", firstSeq, lastSeq),
+		})
+	})
+}
+
+// WriteStreamAck is sent back to the client after every ackEvery frames
+// (and once more on close), reporting the highest Seq written to a
+// segment file on disk so far. A client can safely drop anything at or
+// below HighestSeq from its local retry buffer: POST /write only acks
+// once, at the end of the whole request, so a client sending a long-
+// lived stream of frames has nothing to truncate against until this.
+type WriteStreamAck struct {
+	SessionID  string `json:"session_id"`
+	HighestSeq int64  `json:"highest_seq"`
+}
+
+// WriteStreamReject is sent back in place of a WriteStreamAck when a
+// frame is rejected rather than buffered, e.g. WriterConfig.Backpressure
+// is BackpressureError and the session's buffer is already full. The
+// connection stays open: a client seeing Code == "resource_exhausted"
+// is expected to back off and resend, the same as it would for an HTTP
+// 429 from POST /write.
+type WriteStreamReject struct {
+	SessionID string `json:"session_id"`
+	Seq       int64  `json:"seq"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+// WriteStreamHandler upgrades to a WebSocket and appends every frame the
+// client sends to a SessionWriter for session_id, flushing and acking
+// the highest Seq written every ackEvery frames instead of only once the
+// connection closes. ackEvery <= 0 acks every frame. If live is non-nil,
+// the SessionWriter is registered with it for the life of the
+// connection, so Handler's /replay RPC can see this stream's buffered
+// tail before it's ever flushed.
+//
+// cfg.Metrics, if set, records the connection's whole lifetime (upgrade
+// to close) as "logservice_writestream_latency_seconds" via
+// Metrics.ObserveHandler, and every frame written through cfg's
+// SessionWriters feeds cfg.Metrics' frame/byte/rotation counters the
+// same way Handler's /frames and /replay routes would.
+//
+// The caller must already be authenticated: WriteStreamHandler requires
+// an authn.Identity on the request context (attached upstream by
+// authn.Middleware, the way pkg/audit and pkg/authz already consume it)
+// and rejects the upgrade with UNAUTHENTICATED if none is present,
+// rather than verifying credentials itself. Every frame written over
+// the resulting connection is stamped with that identity's Subject as
+// Frame.Principal.
+//
+// If cfg.RateLimit is set, every frame is checked against it before
+// writer.Write, keyed by subject and session_id together; a frame that
+// exhausts its bucket is rejected over the connection the same way a
+// BackpressureError rejection is (see reject), with
+// RESOURCE_EXHAUSTED, rather than the connection being dropped.
+//
+// store's config is loaded fresh for each new connection, so a runtime
+// change made through AdminHandler's POST /admin/config takes effect
+// for every write stream opened after the change, without a restart;
+// connections already open keep whatever config they started with,
+// same as AdminHandler's doc comment on ConfigStore already promises.
+//
+// The optional tenant_id query parameter partitions where this
+// session's segments land on disk (see tenantDir) and, if cfg.Quota is
+// set, which tenant's byte and session-count quota this connection's
+// writes count against; omitting it uses the flat, single-tenant
+// layout. A connection that would push tenant_id over
+// cfg.Quota.MaxSessions is rejected before the WebSocket upgrade with
+// RESOURCE_EXHAUSTED; a frame that would push it over MaxBytes is
+// rejected over the connection the same way a BackpressureError
+// rejection is.
+func WriteStreamHandler(store *ConfigStore, ackEvery int, live *WriterRegistry) http.Handler {
+	if ackEvery <= 0 {
+		ackEvery = 1
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		cfg := store.Load()
+		defer observeSince(cfg.Metrics, "writestream", start)
+
+		id, ok := authn.FromContext(r.Context())
+		if !ok {
+			apierror.WriteHTTP(w, apierror.Unauthenticated("logservice: write stream requires an authenticated caller"))
+			return
+		}
+
+		sessionID := r.URL.Query().Get("session_id")
+		if sessionID == "" {
+			http.Error(w, "logservice: session_id is required", http.StatusBadRequest)
+			return
+		}
+		tenantID := r.URL.Query().Get("tenant_id")
+
+		if err := cfg.Quota.acquireSession(tenantID); err != nil {
+			if apiErr, ok := apierror.As(err); ok {
+				apierror.WriteHTTP(w, apiErr)
+				return
+			}
+			http.Error(w, "logservice: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer cfg.Quota.releaseSession(tenantID)
+
+		conn, err := wswire.Upgrade(w, r)
+		if err != nil {
+			http.Error(w, "logservice: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer conn.Close()
+
+		writer := NewSessionWriter(cfg, tenantID, sessionID)
+		if live != nil {
+			live.Register(writer)
+			defer live.Unregister(tenantID, sessionID)
+		}
+
+		var highestSeq int64
+		sinceAck := 0
+
+		for {
+			payload, err := conn.ReadText()
+			if err != nil {
+				break
+			}
+
+			var frame Frame
+			if err := json.Unmarshal(payload, &frame); err != nil {
+				log.Printf("logservice: write-stream %s: decoding frame: %v", sessionID, err)
+				continue
+			}
+			frame.Principal = id.Subject
+			frame.TenantID = tenantID
+
+			if cfg.RateLimit != nil {
+				allowed, err := cfg.RateLimit.Allow(r.Context(), fmt.Sprintf("%s:%s", id.Subject, sessionID))
+				if err != nil {
+					log.Printf("logservice: write-stream %s: checking rate limit: %v", sessionID, err)
+					break
+				}
+				if !allowed {
+					if err := reject(conn, sessionID, frame.Seq, apierror.QuotaExceeded("logservice: rate limit exceeded for this session")); err != nil {
+						break
+					}
+					continue
+				}
+			}
+
+			seq, _, err := writer.Write(frame)
+			if err != nil {
+				if apiErr, ok := apierror.As(err); ok {
+					if err := reject(conn, sessionID, frame.Seq, apiErr); err != nil {
+						break
+					}
+					continue
+				}
+				log.Printf("logservice: write-stream %s: writing frame: %v", sessionID, err)
+				break
+			}
+			highestSeq = seq
+
+			sinceAck++
+			if sinceAck >= ackEvery {
+				if err := flushAndAck(conn, sessionID, writer, highestSeq); err != nil {
+					break
+				}
+				sinceAck = 0
+			}
+		}
+
+		if err := writer.Flush(); err != nil {
+			log.Printf("logservice: write-stream %s: flushing on close: %v", sessionID, err)
+			return
+		}
+		if err := ack(conn, sessionID, highestSeq); err != nil {
+			log.Printf("logservice: write-stream %s: sending final ack: %v", sessionID, err)
+		}
+	})
+}
+
+// flushAndAck rotates writer's buffer to a segment file before acking,
+// so HighestSeq in the ack is never ahead of what's actually durable.
+func flushAndAck(conn *wswire.Conn, sessionID string, writer *SessionWriter, highestSeq int64) error {
+	if err := writer.Flush(); err != nil {
+		log.Printf("logservice: write-stream %s: flushing for ack: %v", sessionID, err)
+		return err
+	}
+	return ack(conn, sessionID, highestSeq)
+}
+
+func ack(conn *wswire.Conn, sessionID string, highestSeq int64) error {
+	data, err := json.Marshal(WriteStreamAck{SessionID: sessionID, HighestSeq: highestSeq})
+	if err != nil {
+		return err
+	}
+	return conn.WriteText(data)
+}
+
+// reject sends a WriteStreamReject for the frame seq was assigned,
+// reporting apiErr's Code as the snake_case name a client's error
+// taxonomy already knows from pkg/apierror, rather than its numeric
+// gRPC-style value.
+func reject(conn *wswire.Conn, sessionID string, seq int64, apiErr *apierror.Error) error {
+	code := "unknown"
+	switch apiErr.Code {
+	case apierror.CodeQuotaExceeded:
+		code = "resource_exhausted"
+	case apierror.CodeConflict:
+		code = "conflict"
+	case apierror.CodeNotFound:
+		code = "not_found"
+	case apierror.CodeDependencyUnavailable:
+		code = "unavailable"
+	}
+	data, err := json.Marshal(WriteStreamReject{SessionID: sessionID, Seq: seq, Code: code, Message: apiErr.Message})
+	if err != nil {
+		return err
+	}
+	return conn.WriteText(data)
+}
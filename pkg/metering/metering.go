@@ -0,0 +1,49 @@
+// Package metering tracks per-tenant usage — frames written, records
+// ingested, embeddings computed, and TopK queries — so usage-based
+// billing and abuse detection have something to read. Counters live in
+// Redis (RedisRecorder) for cheap high-frequency increments from the
+// request path; Rollup periodically sums them into Postgres for durable,
+// queryable history, and cmd/zenglow's "usage" subcommand rolls up and
+// exports that history as CSV.
+//
+// This repo has no separate tenant concept yet, so callers identify a
+// tenant with whatever they already have on hand — typically
+// authn.Identity.Subject.
+package metering
+
+import (
+	"context"
+	"time"
+)
+
+// Metric is one thing this package counts per tenant.
+type Metric string
+
+const (
+	FramesWritten      Metric = "frames_written"
+	RecordsIngested    Metric = "records_ingested"
+	EmbeddingsComputed Metric = "embeddings_computed"
+	TopKQueries        Metric = "topk_queries"
+)
+
+// AllMetrics lists every Metric Rollup aggregates, in a fixed order so
+// CSV export columns come out the same way every time.
+var AllMetrics = []Metric{FramesWritten, RecordsIngested, EmbeddingsComputed, TopKQueries}
+
+// Recorder increments a tenant's usage counters as calls happen.
+type Recorder interface {
+	Add(ctx context.Context, tenant string, metric Metric, delta int64) error
+}
+
+// Usage is one tenant's counts for a single rollup period.
+type Usage struct {
+	Tenant string
+	Period time.Time
+	Counts map[Metric]int64
+}
+
+// Sink persists rolled-up Usage records. PostgresSink is the production
+// implementation; tests can supply their own.
+type Sink interface {
+	Write(ctx context.Context, usages []Usage) error
+}
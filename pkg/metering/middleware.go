@@ -0,0 +1,37 @@
+package metering
+
+import (
+	"net/http"
+
+	"github.com/cgb808/ZenGlowNext/pkg/authn"
+)
+
+// Middleware wraps next, recording one unit of metric against
+// tenantFor(r) after next handles the request. Metering is best-effort:
+// a Recorder failure is reported to onError (if non-nil) rather than
+// failing the request it's counting, since a Redis hiccup shouldn't take
+// down the RPC it's metering.
+func Middleware(recorder Recorder, metric Metric, tenantFor func(*http.Request) string, onError func(error), next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		tenant := tenantFor(r)
+		if tenant == "" {
+			return
+		}
+		if err := recorder.Add(r.Context(), tenant, metric, 1); err != nil && onError != nil {
+			onError(err)
+		}
+	})
+}
+
+// TenantFromIdentity is a common tenantFor implementation: the
+// authenticated caller's Subject, since this repo has no separate
+// tenant concept yet. Returns "" for unauthenticated requests.
+func TenantFromIdentity(r *http.Request) string {
+	id, ok := authn.FromContext(r.Context())
+	if !ok {
+		return ""
+	}
+	return id.Subject
+}
@@ -0,0 +1,67 @@
+package metering
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// PostgresSink upserts rolled-up Usage records into Table via psql, the
+// same approach pkg/audit's PostgresSink uses to avoid depending on a Go
+// Postgres driver this repo can't fetch offline. A (tenant, period,
+// metric) row is updated in place on conflict so re-running Rollup for a
+// period it already wrote is safe.
+type PostgresSink struct {
+	DatabaseURL string
+	Table       string
+}
+
+func (s PostgresSink) table() string {
+	if s.Table == "" {
+		return "tenant_usage"
+	}
+	return s.Table
+}
+
+// Write implements Sink.
+func (s PostgresSink) Write(ctx context.Context, usages []Usage) error {
+	var rows []string
+	for _, u := range usages {
+		for _, metric := range AllMetrics {
+			count, ok := u.Counts[metric]
+			if !ok {
+				continue
+			}
+			rows = append(rows, fmt.Sprintf(
+				"(%s, %s, %s, %d)",
+				quoteLiteral(u.Tenant),
+				quoteLiteral(u.Period.Format("2006-01-02T15:04:05Z07:00")),
+				quoteLiteral(string(metric)),
+				count,
+			))
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	sql := fmt.Sprintf(
+		`INSERT INTO %s (tenant, period, metric, count) VALUES %s
+		 ON CONFLICT (tenant, period, metric) DO UPDATE SET count = EXCLUDED.count;`,
+		s.table(), strings.Join(rows, ", "),
+	)
+
+	cmd := exec.CommandContext(ctx, "psql", s.DatabaseURL, "-v", "ON_ERROR_STOP=1", "-c", sql)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("metering: upserting %d usage row(s): %w: %s", len(rows), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,183 @@
+package metering
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/respwire"
+)
+
+const periodLayout = "2006010215" // hourly buckets
+const periodsKey = "metering:periods"
+
+// RedisRecorder is a Recorder that increments hourly counters in Redis,
+// keyed "metering:<period>:<tenant>:<metric>", and tracks which
+// (period, tenant) pairs have pending counts in two Redis sets
+// ("metering:periods" and "metering:<period>:tenants") so Rollup can
+// enumerate them without a Redis SCAN, which respwire doesn't implement.
+type RedisRecorder struct {
+	Addr string
+}
+
+func (r *RedisRecorder) dial() (net.Conn, *bufio.Reader, error) {
+	conn, err := net.Dial("tcp", r.Addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("metering: dialing redis: %w", err)
+	}
+	return conn, bufio.NewReader(conn), nil
+}
+
+func (r *RedisRecorder) counterKey(period, tenant string, metric Metric) string {
+	return fmt.Sprintf("metering:%s:%s:%s", period, tenant, metric)
+}
+
+func (r *RedisRecorder) tenantsKey(period string) string {
+	return "metering:" + period + ":tenants"
+}
+
+// Add implements Recorder by incrementing the current hour's counter for
+// tenant and metric, and recording that the current period and tenant
+// are pending a rollup.
+func (r *RedisRecorder) Add(ctx context.Context, tenant string, metric Metric, delta int64) error {
+	period := currentPeriod()
+
+	conn, rdr, err := r.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := sendIncr(conn, rdr, r.counterKey(period, tenant, metric), delta); err != nil {
+		return fmt.Errorf("metering: recording %s for %s: %w", metric, tenant, err)
+	}
+	if err := sendSAdd(conn, rdr, r.tenantsKey(period), tenant); err != nil {
+		return fmt.Errorf("metering: recording %s for %s: %w", metric, tenant, err)
+	}
+	if err := sendSAdd(conn, rdr, periodsKey, period); err != nil {
+		return fmt.Errorf("metering: recording %s for %s: %w", metric, tenant, err)
+	}
+	return nil
+}
+
+// PendingPeriods returns every period with at least one tenant awaiting
+// rollup.
+func (r *RedisRecorder) PendingPeriods(ctx context.Context) ([]string, error) {
+	return r.smembers(periodsKey)
+}
+
+// TenantsFor returns every tenant with pending counts in period.
+func (r *RedisRecorder) TenantsFor(ctx context.Context, period string) ([]string, error) {
+	return r.smembers(r.tenantsKey(period))
+}
+
+// UsageFor reads tenant's counts for period.
+func (r *RedisRecorder) UsageFor(ctx context.Context, period, tenant string) (Usage, error) {
+	conn, rdr, err := r.dial()
+	if err != nil {
+		return Usage{}, err
+	}
+	defer conn.Close()
+
+	usage := Usage{Tenant: tenant, Counts: make(map[Metric]int64, len(AllMetrics))}
+	usage.Period, err = time.Parse(periodLayout, period)
+	if err != nil {
+		return Usage{}, fmt.Errorf("metering: malformed period %q: %w", period, err)
+	}
+
+	for _, metric := range AllMetrics {
+		if _, err := conn.Write(respwire.EncodeCommand("GET", r.counterKey(period, tenant, metric))); err != nil {
+			return Usage{}, fmt.Errorf("metering: reading %s for %s: %w", metric, tenant, err)
+		}
+		value, ok, err := respwire.ReadBulkString(rdr)
+		if err != nil {
+			return Usage{}, fmt.Errorf("metering: reading %s for %s: %w", metric, tenant, err)
+		}
+		if !ok {
+			continue
+		}
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return Usage{}, fmt.Errorf("metering: malformed counter %s: %q", r.counterKey(period, tenant, metric), value)
+		}
+		usage.Counts[metric] = count
+	}
+	return usage, nil
+}
+
+// ClearPeriod deletes every key recorded for period, once its usage has
+// been durably rolled up elsewhere.
+func (r *RedisRecorder) ClearPeriod(ctx context.Context, period string) error {
+	tenants, err := r.TenantsFor(ctx, period)
+	if err != nil {
+		return err
+	}
+
+	conn, rdr, err := r.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, tenant := range tenants {
+		for _, metric := range AllMetrics {
+			if err := sendDel(conn, rdr, r.counterKey(period, tenant, metric)); err != nil {
+				return fmt.Errorf("metering: clearing %s: %w", period, err)
+			}
+		}
+	}
+	if err := sendDel(conn, rdr, r.tenantsKey(period)); err != nil {
+		return fmt.Errorf("metering: clearing %s: %w", period, err)
+	}
+	if _, err := conn.Write(respwire.EncodeCommand("SREM", periodsKey, period)); err != nil {
+		return fmt.Errorf("metering: clearing %s: %w", period, err)
+	}
+	if _, err := respwire.ReadInteger(rdr); err != nil {
+		return fmt.Errorf("metering: clearing %s: %w", period, err)
+	}
+	return nil
+}
+
+func (r *RedisRecorder) smembers(key string) ([]string, error) {
+	conn, rdr, err := r.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(respwire.EncodeCommand("SMEMBERS", key)); err != nil {
+		return nil, fmt.Errorf("metering: reading %s: %w", key, err)
+	}
+	return respwire.ReadArray(rdr)
+}
+
+func sendIncr(conn net.Conn, rdr *bufio.Reader, key string, delta int64) error {
+	if _, err := conn.Write(respwire.EncodeCommand("INCRBY", key, strconv.FormatInt(delta, 10))); err != nil {
+		return err
+	}
+	_, err := respwire.ReadInteger(rdr)
+	return err
+}
+
+func sendSAdd(conn net.Conn, rdr *bufio.Reader, key, member string) error {
+	if _, err := conn.Write(respwire.EncodeCommand("SADD", key, member)); err != nil {
+		return err
+	}
+	_, err := respwire.ReadInteger(rdr)
+	return err
+}
+
+func sendDel(conn net.Conn, rdr *bufio.Reader, key string) error {
+	if _, err := conn.Write(respwire.EncodeCommand("DEL", key)); err != nil {
+		return err
+	}
+	_, err := respwire.ReadInteger(rdr)
+	return err
+}
+
+func currentPeriod() string {
+	return time.Now().UTC().Format(periodLayout)
+}
@@ -0,0 +1,50 @@
+package metering
+
+import (
+	"context"
+	"fmt"
+)
+
+// Rollup sums RedisRecorder's pending per-tenant counters into Sink and
+// clears them from Redis once durably written, so Redis only ever holds
+// the current and not-yet-rolled-up periods.
+type Rollup struct {
+	Recorder *RedisRecorder
+	Sink     Sink
+}
+
+// Run rolls up every pending period and returns how many it processed.
+func (r *Rollup) Run(ctx context.Context) (int, error) {
+	periods, err := r.Recorder.PendingPeriods(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("metering: listing pending periods: %w", err)
+	}
+
+	for _, period := range periods {
+		if err := r.rollupPeriod(ctx, period); err != nil {
+			return 0, fmt.Errorf("metering: rolling up %s: %w", period, err)
+		}
+	}
+	return len(periods), nil
+}
+
+func (r *Rollup) rollupPeriod(ctx context.Context, period string) error {
+	tenants, err := r.Recorder.TenantsFor(ctx, period)
+	if err != nil {
+		return err
+	}
+
+	usages := make([]Usage, 0, len(tenants))
+	for _, tenant := range tenants {
+		usage, err := r.Recorder.UsageFor(ctx, period, tenant)
+		if err != nil {
+			return err
+		}
+		usages = append(usages, usage)
+	}
+
+	if err := r.Sink.Write(ctx, usages); err != nil {
+		return err
+	}
+	return r.Recorder.ClearPeriod(ctx, period)
+}
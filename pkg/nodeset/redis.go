@@ -0,0 +1,141 @@
+package nodeset
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+)
+
+// RedisSource loads node IDs from a Redis set (SMEMBERS Key), each member
+// being an "id[:weight[:zone]]" string in the same format FileSource and
+// EnvSource use. It speaks the RESP protocol directly over a plain TCP
+// connection rather than pulling in a client library, since that's the
+// only command this package needs.
+type RedisSource struct {
+	Addr    string
+	Key     string
+	Timeout time.Duration // per-connection dial/IO timeout; defaults to 5s
+}
+
+func (s RedisSource) Load() ([]hashring.Node, error) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	conn, err := net.DialTimeout("tcp", s.Addr, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("nodeset: dialing redis at %s: %w", s.Addr, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err := conn.Write(encodeRESPCommand("SMEMBERS", s.Key)); err != nil {
+		return nil, fmt.Errorf("nodeset: writing to redis: %w", err)
+	}
+
+	members, err := readRESPArray(bufio.NewReader(conn))
+	if err != nil {
+		return nil, fmt.Errorf("nodeset: reading redis reply: %w", err)
+	}
+
+	var nodes []hashring.Node
+	for _, m := range members {
+		n, err := parseNodeLine(m)
+		if err != nil {
+			return nil, fmt.Errorf("nodeset: redis set %s: %w", s.Key, err)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+func encodeRESPCommand(args ...string) []byte {
+	out := fmt.Sprintf("*%d\r\n", len(args))
+	for _, a := range args {
+		out += fmt.Sprintf("$%d\r\n%s\r\n", len(a), a)
+	}
+	return []byte(out)
+}
+
+// readRESPArray reads one RESP reply and returns it as a []string. It
+// supports the array and bulk-string types SMEMBERS replies with, and
+// surfaces a RESP error reply as a Go error.
+func readRESPArray(r *bufio.Reader) ([]string, error) {
+	line, err := readRESPLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("empty RESP reply")
+	}
+	switch line[0] {
+	case '-':
+		return nil, fmt.Errorf("redis error: %s", line[1:])
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, nil
+		}
+		out := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			bulk, err := readRESPBulkString(r)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, bulk)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unexpected RESP reply type %q", line[0])
+	}
+}
+
+func readRESPBulkString(r *bufio.Reader) (string, error) {
+	line, err := readRESPLine(r)
+	if err != nil {
+		return "", err
+	}
+	if len(line) == 0 || line[0] != '$' {
+		return "", fmt.Errorf("expected bulk string, got %q", line)
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return "", err
+	}
+	if n < 0 {
+		return "", nil // nil bulk string
+	}
+	buf := make([]byte, n+2) // payload + trailing CRLF
+	if _, err := readFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf[:n]), nil
+}
+
+func readRESPLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	for len(line) > 0 && (line[len(line)-1] == '\n' || line[len(line)-1] == '\r') {
+		line = line[:len(line)-1]
+	}
+	return line, nil
+}
+
+func readFull(r *bufio.Reader, buf []byte) (int, error) {
+	read := 0
+	for read < len(buf) {
+		n, err := r.Read(buf[read:])
+		read += n
+		if err != nil {
+			return read, err
+		}
+	}
+	return read, nil
+}
@@ -0,0 +1,135 @@
+// Package nodeset loads the node set routed over by pkg/hashring from a
+// file, an environment variable, or a Redis set, and can watch any of
+// those for changes so long-running processes such as grpc-router or
+// "hrw -cmd serve" pick up membership changes without a restart.
+package nodeset
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/hashring"
+)
+
+// Source loads the current node set. Implementations must be safe to call
+// repeatedly; Watch calls Load on a timer.
+type Source interface {
+	Load() ([]hashring.Node, error)
+}
+
+// FileSource reads a newline-delimited node set from Path, one
+// "id[:weight[:zone]]" per line; blank lines and lines starting with '#'
+// are ignored.
+type FileSource struct {
+	Path string
+}
+
+func (s FileSource) Load() ([]hashring.Node, error) {
+	f, err := os.Open(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("nodeset: reading %s: %w", s.Path, err)
+	}
+	defer f.Close()
+
+	var nodes []hashring.Node
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		n, err := parseNodeLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("nodeset: %s: %w", s.Path, err)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, scanner.Err()
+}
+
+// EnvSource reads a comma-separated "id[:weight[:zone]]" list from the
+// environment variable Var.
+type EnvSource struct {
+	Var string
+}
+
+func (s EnvSource) Load() ([]hashring.Node, error) {
+	val := os.Getenv(s.Var)
+	var nodes []hashring.Node
+	for _, p := range strings.Split(val, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		n, err := parseNodeLine(p)
+		if err != nil {
+			return nil, fmt.Errorf("nodeset: env %s: %w", s.Var, err)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+func parseNodeLine(line string) (hashring.Node, error) {
+	fields := strings.Split(line, ":")
+	n := hashring.Node{ID: fields[0], Weight: 1}
+	if len(fields) > 1 && fields[1] != "" {
+		w, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return n, fmt.Errorf("invalid weight for node %q: %w", n.ID, err)
+		}
+		n.Weight = w
+	}
+	if len(fields) > 2 {
+		n.Zone = fields[2]
+	}
+	return n, nil
+}
+
+// Watch polls src every interval and invokes onChange whenever the loaded
+// node set differs from the previous one (including the first successful
+// load). Load errors are passed to onErr and otherwise ignored, so a
+// transient outage of the backing store doesn't tear down the watch.
+// Watch blocks until stop is closed.
+func Watch(src Source, interval time.Duration, onChange func([]hashring.Node), onErr func(error), stop <-chan struct{}) {
+	var last string
+	check := func() {
+		nodes, err := src.Load()
+		if err != nil {
+			if onErr != nil {
+				onErr(err)
+			}
+			return
+		}
+		key := fingerprint(nodes)
+		if key == last {
+			return
+		}
+		last = key
+		onChange(nodes)
+	}
+
+	check()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+func fingerprint(nodes []hashring.Node) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "%s:%g:%s;", n.ID, n.Weight, n.Zone)
+	}
+	return b.String()
+}
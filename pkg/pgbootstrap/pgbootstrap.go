@@ -0,0 +1,143 @@
+// Package pgbootstrap prepares a Postgres database for pgvector-backed
+// event storage: it verifies the pgvector extension is available,
+// creates the events schema with the vector column sized for the
+// configured embedding model, and builds the HNSW index used by the
+// top-k search path. It drives psql via os/exec rather than a Go
+// Postgres driver, since this repo has no way to fetch one offline and
+// a bootstrap script shelling out to psql is how this kind of one-shot
+// DDL is normally run anyway.
+package pgbootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// EmbeddingDims maps a model name to the vector column width pgvector
+// needs for it. Add an entry here whenever a new embedding model is
+// adopted.
+var EmbeddingDims = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"all-MiniLM-L6-v2":       384,
+}
+
+// Options configures Bootstrap.
+type Options struct {
+	DatabaseURL string
+	Model       string
+	Table       string
+}
+
+// Report summarizes what Bootstrap did, for the CLI's readiness printout.
+type Report struct {
+	PgvectorAvailable bool
+	Dimensions        int
+	TableCreated      bool
+	IndexCreated      bool
+}
+
+// Bootstrap verifies pgvector, creates the events table sized for
+// opts.Model, and builds its HNSW index, running each step with psql
+// against opts.DatabaseURL.
+func Bootstrap(ctx context.Context, opts Options) (Report, error) {
+	var report Report
+
+	dims, ok := EmbeddingDims[opts.Model]
+	if !ok {
+		return report, fmt.Errorf("pgbootstrap: unknown model %q; known models: %s", opts.Model, knownModels())
+	}
+	report.Dimensions = dims
+
+	table := opts.Table
+	if table == "" {
+		table = "events"
+	}
+
+	available, err := checkPgvector(ctx, opts.DatabaseURL)
+	if err != nil {
+		return report, fmt.Errorf("pgbootstrap: checking pgvector: %w", err)
+	}
+	report.PgvectorAvailable = available
+	if !available {
+		return report, fmt.Errorf("pgbootstrap: pgvector extension is not available on this database")
+	}
+
+	if err := runSQL(ctx, opts.DatabaseURL, createTableSQL(table, dims)); err != nil {
+		return report, fmt.Errorf("pgbootstrap: creating %s table: %w", table, err)
+	}
+	report.TableCreated = true
+
+	if err := runSQL(ctx, opts.DatabaseURL, createIndexSQL(table)); err != nil {
+		return report, fmt.Errorf("pgbootstrap: building HNSW index on %s: %w", table, err)
+	}
+	report.IndexCreated = true
+
+	return report, nil
+}
+
+// RebuildIndex (re)builds table's HNSW index, for a caller that already
+// knows the table exists (e.g. a DR restore that bulk-loaded rows into
+// an empty database with Bootstrap already run) and just needs the
+// index in place afterward.
+func RebuildIndex(ctx context.Context, databaseURL, table string) error {
+	if err := runSQL(ctx, databaseURL, fmt.Sprintf("DROP INDEX IF EXISTS %s_embedding_hnsw_idx;", table)); err != nil {
+		return fmt.Errorf("pgbootstrap: dropping existing index on %s: %w", table, err)
+	}
+	if err := runSQL(ctx, databaseURL, createIndexSQL(table)); err != nil {
+		return fmt.Errorf("pgbootstrap: building HNSW index on %s: %w", table, err)
+	}
+	return nil
+}
+
+func knownModels() string {
+	names := make([]string, 0, len(EmbeddingDims))
+	for name := range EmbeddingDims {
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func checkPgvector(ctx context.Context, databaseURL string) (bool, error) {
+	out, err := runSQLOutput(ctx, databaseURL, "SELECT extname FROM pg_extension WHERE extname = 'vector';")
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(out, "vector"), nil
+}
+
+func createTableSQL(table string, dims int) string {
+	return fmt.Sprintf(`
+CREATE EXTENSION IF NOT EXISTS vector;
+CREATE TABLE IF NOT EXISTS %s (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	embedding vector(%d) NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);`, table, dims)
+}
+
+func createIndexSQL(table string) string {
+	return fmt.Sprintf(`
+CREATE INDEX IF NOT EXISTS %s_embedding_hnsw_idx
+	ON %s USING hnsw (embedding vector_cosine_ops)
+	WITH (m = 16, ef_construction = 64);`, table, table)
+}
+
+func runSQL(ctx context.Context, databaseURL, sql string) error {
+	_, err := runSQLOutput(ctx, databaseURL, sql)
+	return err
+}
+
+func runSQLOutput(ctx context.Context, databaseURL, sql string) (string, error) {
+	cmd := exec.CommandContext(ctx, "psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", sql)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
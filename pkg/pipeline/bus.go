@@ -0,0 +1,36 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cgb808/ZenGlowNext/pkg/bus"
+)
+
+// BusSegmentSource is a SegmentSource backed by a pkg/bus subscription,
+// for deployments that route the segment-rotation queue through Redis
+// Streams, NATS, or Kafka instead of talking to the Redis list directly
+// (see RedisSegmentSource).
+type BusSegmentSource struct {
+	Subscriber bus.Subscriber
+	Topic      string
+}
+
+// Next implements SegmentSource by pulling the next message off s.Topic
+// and decoding it as a JSON-encoded Segment.
+func (s *BusSegmentSource) Next(ctx context.Context) (Segment, error) {
+	msg, err := s.Subscriber.Next(ctx, s.Topic)
+	if err != nil {
+		return Segment{}, fmt.Errorf("pipeline: next segment from bus: %w", err)
+	}
+
+	var seg Segment
+	if err := json.Unmarshal(msg.Payload, &seg); err != nil {
+		return Segment{}, fmt.Errorf("pipeline: decoding segment from bus: %w", err)
+	}
+	if err := s.Subscriber.Ack(ctx, s.Topic, msg); err != nil {
+		return Segment{}, fmt.Errorf("pipeline: acking segment %s: %w", seg.ID, err)
+	}
+	return seg, nil
+}
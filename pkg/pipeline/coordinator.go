@@ -0,0 +1,89 @@
+// Package pipeline connects the stages that turn a rotated log segment
+// into a searchable event: a SegmentSource hands the coordinator rotated
+// segments, a FrameSink streams their frames through persistence and
+// embedding, and a CanonicalChecker confirms the resulting events became
+// visible for search. The three concrete services these interfaces front
+// (logservice, the ingester, and CanonicalService) are each still
+// growing; Coordinator lets them be wired together without the pipeline
+// itself depending on any one of their implementations directly.
+package pipeline
+
+import (
+	"context"
+	"fmt"
+)
+
+// Segment is a rotated log segment ready to be ingested.
+type Segment struct {
+	ID   string
+	Path string
+}
+
+// SegmentSource yields rotated segments, e.g. popped off the logservice
+// Redis queue. Next blocks until a segment is available or ctx is done.
+type SegmentSource interface {
+	Next(ctx context.Context) (Segment, error)
+}
+
+// FrameSink streams a segment's frames through the ingester's
+// persistence/embedding path and returns the event IDs it produced.
+type FrameSink interface {
+	Ingest(ctx context.Context, seg Segment) (eventIDs []string, err error)
+}
+
+// CanonicalChecker confirms that the given event IDs are visible to
+// search through CanonicalService.
+type CanonicalChecker interface {
+	Visible(ctx context.Context, eventIDs []string) (bool, error)
+}
+
+// Coordinator drives segments from a SegmentSource through a FrameSink
+// and confirms visibility with a CanonicalChecker, one segment at a time.
+type Coordinator struct {
+	Source FrameSource
+	Sink   FrameSink
+	Check  CanonicalChecker
+}
+
+// FrameSource is an alias kept for the SegmentSource this Coordinator
+// reads from; named separately so callers reading Coordinator's fields
+// see "where segments come from" rather than the interface's own name.
+type FrameSource = SegmentSource
+
+// RunOnce pulls a single segment from c.Source, ingests it through
+// c.Sink, and confirms visibility with c.Check. It returns the segment's
+// event IDs on success.
+func (c *Coordinator) RunOnce(ctx context.Context) ([]string, error) {
+	seg, err := c.Source.Next(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline: next segment: %w", err)
+	}
+
+	eventIDs, err := c.Sink.Ingest(ctx, seg)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline: ingest segment %s: %w", seg.ID, err)
+	}
+
+	visible, err := c.Check.Visible(ctx, eventIDs)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline: check visibility of segment %s: %w", seg.ID, err)
+	}
+	if !visible {
+		return nil, fmt.Errorf("pipeline: segment %s ingested but not yet visible in canonical search", seg.ID)
+	}
+	return eventIDs, nil
+}
+
+// Run calls RunOnce in a loop until ctx is done, logging each segment's
+// outcome via onSegment.
+func (c *Coordinator) Run(ctx context.Context, onSegment func(eventIDs []string, err error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		eventIDs, err := c.RunOnce(ctx)
+		onSegment(eventIDs, err)
+	}
+}
@@ -0,0 +1,125 @@
+package pipeline
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// RedisSegmentSource is a SegmentSource backed by a Redis list that
+// logservice pushes rotated segments onto (as JSON-encoded Segment
+// values). It polls with LPOP rather than BLPOP so a single dial can be
+// reused across calls without juggling blocking-command timeouts.
+type RedisSegmentSource struct {
+	Addr         string
+	Key          string
+	PollInterval time.Duration
+	DialTimeout  time.Duration
+}
+
+// Next implements SegmentSource by polling Key with LPOP until a segment
+// is available or ctx is done.
+func (s *RedisSegmentSource) Next(ctx context.Context) (Segment, error) {
+	interval := s.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	for {
+		seg, ok, err := s.tryPop()
+		if err != nil {
+			return Segment{}, err
+		}
+		if ok {
+			return seg, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return Segment{}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (s *RedisSegmentSource) tryPop() (Segment, bool, error) {
+	timeout := s.DialTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	conn, err := net.DialTimeout("tcp", s.Addr, timeout)
+	if err != nil {
+		return Segment{}, false, fmt.Errorf("pipeline: dial redis %s: %w", s.Addr, err)
+	}
+	defer conn.Close()
+
+	cmd := encodeRESPCommand("LPOP", s.Key)
+	if _, err := conn.Write(cmd); err != nil {
+		return Segment{}, false, fmt.Errorf("pipeline: write LPOP: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	raw, ok, err := readRESPBulkString(reader)
+	if err != nil {
+		return Segment{}, false, fmt.Errorf("pipeline: read LPOP reply: %w", err)
+	}
+	if !ok {
+		return Segment{}, false, nil
+	}
+
+	var seg Segment
+	if err := json.Unmarshal([]byte(raw), &seg); err != nil {
+		return Segment{}, false, fmt.Errorf("pipeline: decode segment: %w", err)
+	}
+	return seg, true, nil
+}
+
+func encodeRESPCommand(parts ...string) []byte {
+	out := fmt.Sprintf("*%d\r\n", len(parts))
+	for _, p := range parts {
+		out += fmt.Sprintf("$%d\r\n%s\r\n", len(p), p)
+	}
+	return []byte(out)
+}
+
+// readRESPBulkString reads a single RESP reply that is expected to be a
+// bulk string (or nil bulk string, reported as ok=false).
+func readRESPBulkString(r *bufio.Reader) (value string, ok bool, err error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", false, err
+	}
+	if len(line) < 3 || line[0] != '$' {
+		return "", false, fmt.Errorf("unexpected RESP reply: %q", line)
+	}
+	if line[1] == '-' {
+		return "", false, nil
+	}
+
+	var n int
+	if _, err := fmt.Sscanf(line[1:], "%d", &n); err != nil {
+		return "", false, fmt.Errorf("malformed bulk length: %q", line)
+	}
+
+	buf := make([]byte, n+2) // payload + trailing \r\n
+	if _, err := readFull(r, buf); err != nil {
+		return "", false, err
+	}
+	return string(buf[:n]), true, nil
+}
+
+func readFull(r *bufio.Reader, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := r.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
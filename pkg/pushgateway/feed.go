@@ -0,0 +1,24 @@
+package pushgateway
+
+import (
+	"context"
+
+	"github.com/cgb808/ZenGlowNext/pkg/replication"
+)
+
+// ChangeFeedSink adapts a Registry to replication.Sink, so a
+// replication.Worker already polling Postgres's canonical events table
+// for pkg/replication's DR use case can, with the same Source, dispatch
+// each changed row to standing queries instead of (or alongside)
+// shipping it to a secondary region.
+type ChangeFeedSink struct {
+	Registry *Registry
+}
+
+// Apply implements replication.Sink.
+func (s ChangeFeedSink) Apply(ctx context.Context, rows []replication.Row) error {
+	for _, row := range rows {
+		s.Registry.Dispatch(Event{EventID: row.ID, Columns: row.Columns})
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// Package pushgateway lets UI clients register a standing query (exact
+// column filters, an embedding similarity threshold, or both) and
+// receive newly ingested events matching it as they arrive off
+// pkg/replication's canonical change feed, pushed over SSE or
+// WebSocket rather than polled for.
+package pushgateway
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cgb808/ZenGlowNext/pkg/reqid"
+)
+
+// Event is a newly changed canonical event dispatched to matching
+// standing queries.
+type Event struct {
+	EventID string
+	Columns map[string]string
+}
+
+// Query is a client's standing interest: filters must all match
+// exactly, and if Embedding is set the event's own embedding column
+// must be at least Threshold similar to it. A Query with no filters and
+// no embedding matches every event.
+type Query struct {
+	Filters         map[string]string
+	Embedding       []float32
+	EmbeddingColumn string // Columns key holding the event's embedding; default "embedding"
+	Threshold       float64
+}
+
+// Matches reports whether event satisfies q.
+func (q Query) Matches(event Event) bool {
+	for col, want := range q.Filters {
+		if event.Columns[col] != want {
+			return false
+		}
+	}
+	if len(q.Embedding) == 0 {
+		return true
+	}
+	column := q.EmbeddingColumn
+	if column == "" {
+		column = "embedding"
+	}
+	vec, ok := parseVector(event.Columns[column])
+	if !ok {
+		return false
+	}
+	return cosineSimilarity(q.Embedding, vec) >= q.Threshold
+}
+
+// Subscription is one registered Query and the channel matching Events
+// are pushed to. Events is buffered so a slow client can't block the
+// change feed's dispatch loop; Dispatch drops an event for a
+// subscription whose buffer is full instead of blocking on it.
+type Subscription struct {
+	ID     string
+	Query  Query
+	Events chan Event
+}
+
+// Registry holds every live Subscription and matches each dispatched
+// Event against all of them.
+type Registry struct {
+	mu   sync.RWMutex
+	subs map[string]*Subscription
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{subs: make(map[string]*Subscription)}
+}
+
+// Register adds a standing query and returns the Subscription a
+// transport handler (SSE or WebSocket) reads matching Events from.
+func (r *Registry) Register(q Query) *Subscription {
+	sub := &Subscription{ID: reqid.New(), Query: q, Events: make(chan Event, 32)}
+	r.mu.Lock()
+	r.subs[sub.ID] = sub
+	r.mu.Unlock()
+	return sub
+}
+
+// Unregister removes a subscription and closes its Events channel, e.g.
+// once its client disconnects.
+func (r *Registry) Unregister(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if sub, ok := r.subs[id]; ok {
+		close(sub.Events)
+		delete(r.subs, id)
+	}
+}
+
+// Dispatch pushes event to every subscription whose Query matches it.
+func (r *Registry) Dispatch(event Event) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, sub := range r.subs {
+		if !sub.Query.Matches(event) {
+			continue
+		}
+		select {
+		case sub.Events <- event:
+		default: // slow subscriber; drop rather than block the feed
+		}
+	}
+}
+
+// parseVector parses pgvector's text representation, "[0.1,0.2,0.3]",
+// the form psql's \copy CSV output gives an embedding column.
+func parseVector(text string) ([]float32, bool) {
+	text = strings.TrimSpace(text)
+	text = strings.TrimPrefix(text, "[")
+	text = strings.TrimSuffix(text, "]")
+	if text == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(text, ",")
+	vec := make([]float32, len(parts))
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 32)
+		if err != nil {
+			return nil, false
+		}
+		vec[i] = float32(f)
+	}
+	return vec, true
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
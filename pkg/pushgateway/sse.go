@@ -0,0 +1,89 @@
+package pushgateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// SSEHandler returns an http.Handler that registers the requesting
+// client's standing query against registry, built from its query
+// parameters (filter.<column>=<value> repeated, embedding=<comma
+// separated floats>, embedding_column, threshold), and streams matching
+// Events as server-sent events until the client disconnects.
+func SSEHandler(registry *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		query, err := queryFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		sub := registry.Register(query)
+		defer registry.Unregister(sub.ID)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "event: subscribed\ndata: %s\n\n", sub.ID)
+		flusher.Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case event, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "event: match\ndata: %s\n\n", data)
+				flusher.Flush()
+			}
+		}
+	})
+}
+
+// queryFromRequest builds a Query from an SSE or WebSocket upgrade
+// request's query string, the only place either transport can carry
+// subscription parameters before the standing connection is established.
+func queryFromRequest(r *http.Request) (Query, error) {
+	q := Query{Filters: map[string]string{}}
+	for key, values := range r.URL.Query() {
+		if len(values) == 0 {
+			continue
+		}
+		value := values[0]
+		switch {
+		case strings.HasPrefix(key, "filter."):
+			q.Filters[strings.TrimPrefix(key, "filter.")] = value
+		case key == "embedding":
+			vec, ok := parseVector("[" + value + "]")
+			if !ok {
+				return Query{}, fmt.Errorf("pushgateway: malformed embedding parameter")
+			}
+			q.Embedding = vec
+		case key == "embedding_column":
+			q.EmbeddingColumn = value
+		case key == "threshold":
+			t, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return Query{}, fmt.Errorf("pushgateway: malformed threshold parameter")
+			}
+			q.Threshold = t
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,58 @@
+package pushgateway
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/cgb808/ZenGlowNext/pkg/wswire"
+)
+
+// WebSocketHandler returns an http.Handler that upgrades the request to
+// a WebSocket, registers the same standing query SSEHandler builds from
+// the request's query string, and pushes matching Events as JSON text
+// frames until the client closes the connection.
+func WebSocketHandler(registry *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, err := queryFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		conn, err := wswire.Upgrade(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer conn.Close()
+
+		sub := registry.Register(query)
+		defer registry.Unregister(sub.ID)
+
+		disconnected := make(chan struct{})
+		go func() {
+			defer close(disconnected)
+			conn.ReadLoop() // blocks until the client closes or errors out
+		}()
+
+		for {
+			select {
+			case <-disconnected:
+				return
+			case event, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				if err := conn.WriteText(data); err != nil {
+					log.Printf("pushgateway: writing to subscriber %s: %v", sub.ID, err)
+					return
+				}
+			}
+		}
+	})
+}
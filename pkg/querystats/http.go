@@ -0,0 +1,43 @@
+package querystats
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Handler serves GET /stats?from=<RFC3339>&to=<RFC3339>&service=<name>,
+// the stats RPC product and SRE teams query for usage trends. from and
+// to default to the last hour and now, respectively, if omitted.
+func Handler(sink PostgresSink) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		to := time.Now()
+		from := to.Add(-time.Hour)
+
+		if v := r.URL.Query().Get("from"); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "querystats: invalid from: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			from = t
+		}
+		if v := r.URL.Query().Get("to"); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "querystats: invalid to: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			to = t
+		}
+
+		buckets, err := sink.Query(r.Context(), from, to, r.URL.Query().Get("service"))
+		if err != nil {
+			http.Error(w, "querystats: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"buckets": buckets})
+	})
+}
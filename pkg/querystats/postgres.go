@@ -0,0 +1,159 @@
+package querystats
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PostgresSink upserts rolled-up Buckets into Table via psql, the same
+// approach pkg/metering.PostgresSink uses to avoid depending on a Go
+// Postgres driver this repo can't fetch offline. A (bucket_start,
+// service, cache_tier) row is updated in place on conflict so re-running
+// Rollup for a period it already wrote is safe. FilterCounts is stored
+// as JSON text since its keys vary per deployment.
+type PostgresSink struct {
+	DatabaseURL string
+	Table       string
+}
+
+func (s PostgresSink) table() string {
+	if s.Table == "" {
+		return "query_stats_rollup"
+	}
+	return s.Table
+}
+
+// Write implements Sink.
+func (s PostgresSink) Write(ctx context.Context, buckets []Bucket) error {
+	var rows []string
+	for _, b := range buckets {
+		filtersJSON, err := json.Marshal(b.FilterCounts)
+		if err != nil {
+			return fmt.Errorf("querystats: encoding filter counts: %w", err)
+		}
+		rows = append(rows, fmt.Sprintf(
+			"(%s, %s, %s, %d, %s, %d, %s)",
+			quoteLiteral(b.BucketStart.Format(timeLayout)),
+			quoteLiteral(b.Service),
+			quoteLiteral(b.CacheTier),
+			b.SampleCount,
+			quoteLiteral(strconv.FormatFloat(b.TotalLatencyMS, 'f', -1, 64)),
+			b.TotalResultCount,
+			quoteLiteral(string(filtersJSON)),
+		))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	sql := fmt.Sprintf(
+		`INSERT INTO %s (bucket_start, service, cache_tier, sample_count, total_latency_ms, total_result_count, filter_counts)
+		 VALUES %s
+		 ON CONFLICT (bucket_start, service, cache_tier) DO UPDATE SET
+		   sample_count = EXCLUDED.sample_count,
+		   total_latency_ms = EXCLUDED.total_latency_ms,
+		   total_result_count = EXCLUDED.total_result_count,
+		   filter_counts = EXCLUDED.filter_counts;`,
+		s.table(), strings.Join(rows, ", "),
+	)
+	return runPsql(ctx, s.DatabaseURL, sql)
+}
+
+// Query returns the rolled-up buckets in [from, to) for service, or for
+// every service if service is "". It backs the stats RPC (see http.go).
+func (s PostgresSink) Query(ctx context.Context, from, to time.Time, service string) ([]Bucket, error) {
+	where := fmt.Sprintf("bucket_start >= %s AND bucket_start < %s", quoteLiteral(from.Format(timeLayout)), quoteLiteral(to.Format(timeLayout)))
+	if service != "" {
+		where += fmt.Sprintf(" AND service = %s", quoteLiteral(service))
+	}
+	sql := fmt.Sprintf(
+		"SELECT bucket_start, service, cache_tier, sample_count, total_latency_ms, total_result_count, filter_counts FROM %s WHERE %s ORDER BY bucket_start ASC",
+		s.table(), where,
+	)
+	records, err := runPsqlCSV(ctx, s.DatabaseURL, sql)
+	if err != nil {
+		return nil, fmt.Errorf("querystats: querying buckets: %w", err)
+	}
+
+	buckets := make([]Bucket, 0, len(records))
+	for _, r := range records {
+		b, err := bucketFromRecord(r)
+		if err != nil {
+			continue
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
+
+// DeleteOlderThan implements a RetentionPolicy by deleting every row
+// with bucket_start before cutoff.
+func (s PostgresSink) DeleteOlderThan(ctx context.Context, cutoff time.Time) error {
+	sql := fmt.Sprintf("DELETE FROM %s WHERE bucket_start < %s;", s.table(), quoteLiteral(cutoff.Format(timeLayout)))
+	return runPsql(ctx, s.DatabaseURL, sql)
+}
+
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+func bucketFromRecord(r []string) (Bucket, error) {
+	if len(r) < 7 {
+		return Bucket{}, fmt.Errorf("querystats: malformed row: %v", r)
+	}
+	bucketStart, err := time.Parse("2006-01-02 15:04:05", r[0])
+	if err != nil {
+		return Bucket{}, fmt.Errorf("querystats: malformed bucket_start: %w", err)
+	}
+	sampleCount, _ := strconv.ParseInt(r[3], 10, 64)
+	totalLatencyMS, _ := strconv.ParseFloat(r[4], 64)
+	totalResultCount, _ := strconv.ParseInt(r[5], 10, 64)
+
+	filterCounts := map[string]int64{}
+	if r[6] != "" {
+		if err := json.Unmarshal([]byte(r[6]), &filterCounts); err != nil {
+			return Bucket{}, fmt.Errorf("querystats: decoding filter_counts: %w", err)
+		}
+	}
+
+	return Bucket{
+		BucketStart:      bucketStart,
+		Service:          r[1],
+		CacheTier:        r[2],
+		SampleCount:      sampleCount,
+		TotalLatencyMS:   totalLatencyMS,
+		TotalResultCount: totalResultCount,
+		FilterCounts:     filterCounts,
+	}, nil
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func runPsql(ctx context.Context, databaseURL, sql string) error {
+	cmd := exec.CommandContext(ctx, "psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", sql)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func runPsqlCSV(ctx context.Context, databaseURL, sql string) ([][]string, error) {
+	copySQL := fmt.Sprintf(`\copy (%s) TO STDOUT WITH (FORMAT csv)`, sql)
+	cmd := exec.CommandContext(ctx, "psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", copySQL)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return csv.NewReader(&stdout).ReadAll()
+}
@@ -0,0 +1,66 @@
+// Package querystats samples per-request metadata from TopK search and
+// RouterService lookups — latency, which filters a query used, which
+// cache tier served it, and how many results came back — and rolls
+// those samples up into fixed-width time buckets so product and SRE
+// teams get queryable usage trends without standing up a full metrics
+// stack (pkg/telemetry's counters and histograms answer "is it healthy
+// right now", not "how did p50 latency trend over the last month").
+//
+// The flow mirrors pkg/metering: RedisRecorder accumulates samples
+// cheaply on the request path, Rollup periodically sums them into
+// Postgres via PostgresSink, and a RetentionPolicy prunes rows older
+// than a deployment's retention window. Unlike pkg/metering.Middleware,
+// there is no generic HTTP middleware here — cache tier and result
+// count are computed by each handler's own query logic, not derivable
+// from the request/response alone, so instrumented handlers call
+// Record directly (see cmd/grpc-router's route handler).
+package querystats
+
+import (
+	"context"
+	"time"
+)
+
+// Sample is one request's metadata, as recorded by the handler that
+// served it.
+type Sample struct {
+	Service     string // "topk" or "router"
+	Timestamp   time.Time
+	LatencyMS   float64
+	Filters     []string // names of the filter fields the query set, e.g. "user", "type"
+	CacheTier   string   // e.g. "hot", "cold", "" if the service has no cache tiers
+	ResultCount int
+}
+
+// Recorder records a Sample for later rollup.
+type Recorder interface {
+	Record(ctx context.Context, sample Sample) error
+}
+
+// Bucket is one (period, service, cache tier) rollup row. Sums rather
+// than averages are stored so re-running Rollup against a period that
+// already has a Postgres row (e.g. after a crash mid-rollup) can add to
+// it instead of overwriting it with a partial aggregate.
+type Bucket struct {
+	BucketStart      time.Time
+	Service          string
+	CacheTier        string
+	SampleCount      int64
+	TotalLatencyMS   float64
+	TotalResultCount int64
+	FilterCounts     map[string]int64
+}
+
+// AvgLatencyMS returns b's mean latency, or 0 if it has no samples.
+func (b Bucket) AvgLatencyMS() float64 {
+	if b.SampleCount == 0 {
+		return 0
+	}
+	return b.TotalLatencyMS / float64(b.SampleCount)
+}
+
+// Sink persists rolled-up Buckets. PostgresSink is the production
+// implementation; tests can supply their own.
+type Sink interface {
+	Write(ctx context.Context, buckets []Bucket) error
+}
@@ -0,0 +1,285 @@
+package querystats
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/respwire"
+)
+
+const bucketLayout = "200601021504" // minute buckets
+const periodsKey = "querystats:periods"
+
+// RedisRecorder is a Recorder that accumulates samples into per-minute
+// buckets in Redis, keyed "querystats:<period>:<dimension>:*" where
+// dimension is "<service>|<cache tier>". It tracks which (period,
+// dimension) pairs have pending data in two Redis sets
+// ("querystats:periods" and "querystats:<period>:dimensions"), the same
+// approach pkg/metering.RedisRecorder uses to enumerate pending work
+// without a Redis SCAN, which respwire doesn't implement.
+type RedisRecorder struct {
+	Addr string
+}
+
+func (r *RedisRecorder) dial() (net.Conn, *bufio.Reader, error) {
+	conn, err := net.Dial("tcp", r.Addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querystats: dialing redis: %w", err)
+	}
+	return conn, bufio.NewReader(conn), nil
+}
+
+func dimension(service, cacheTier string) string {
+	return service + "|" + cacheTier
+}
+
+func splitDimension(dim string) (service, cacheTier string) {
+	service, cacheTier, _ = strings.Cut(dim, "|")
+	return service, cacheTier
+}
+
+func (r *RedisRecorder) dimensionsKey(period string) string {
+	return "querystats:" + period + ":dimensions"
+}
+
+func (r *RedisRecorder) countKey(period, dim string) string {
+	return "querystats:" + period + ":" + dim + ":count"
+}
+func (r *RedisRecorder) latencySumKey(period, dim string) string {
+	return "querystats:" + period + ":" + dim + ":latency_ms_sum"
+}
+func (r *RedisRecorder) resultSumKey(period, dim string) string {
+	return "querystats:" + period + ":" + dim + ":result_count_sum"
+}
+func (r *RedisRecorder) filterCountsKey(period, dim string) string {
+	return "querystats:" + period + ":" + dim + ":filters"
+}
+
+// Record implements Recorder by incrementing the current minute
+// bucket's counters for sample's (service, cache tier) dimension.
+func (r *RedisRecorder) Record(ctx context.Context, sample Sample) error {
+	ts := sample.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	period := ts.UTC().Format(bucketLayout)
+	dim := dimension(sample.Service, sample.CacheTier)
+
+	conn, rdr, err := r.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := sendIncrBy(conn, rdr, r.countKey(period, dim), 1); err != nil {
+		return fmt.Errorf("querystats: recording sample: %w", err)
+	}
+	if err := sendIncrByFloat(conn, rdr, r.latencySumKey(period, dim), sample.LatencyMS); err != nil {
+		return fmt.Errorf("querystats: recording sample: %w", err)
+	}
+	if err := sendIncrBy(conn, rdr, r.resultSumKey(period, dim), int64(sample.ResultCount)); err != nil {
+		return fmt.Errorf("querystats: recording sample: %w", err)
+	}
+	for _, filter := range sample.Filters {
+		if err := sendHIncrBy(conn, rdr, r.filterCountsKey(period, dim), filter, 1); err != nil {
+			return fmt.Errorf("querystats: recording filter %q: %w", filter, err)
+		}
+	}
+	if err := sendSAdd(conn, rdr, r.dimensionsKey(period), dim); err != nil {
+		return fmt.Errorf("querystats: recording sample: %w", err)
+	}
+	if err := sendSAdd(conn, rdr, periodsKey, period); err != nil {
+		return fmt.Errorf("querystats: recording sample: %w", err)
+	}
+	return nil
+}
+
+// PendingPeriods returns every period with at least one dimension
+// awaiting rollup.
+func (r *RedisRecorder) PendingPeriods(ctx context.Context) ([]string, error) {
+	return r.smembers(periodsKey)
+}
+
+// DimensionsFor returns every "<service>|<cache tier>" dimension with
+// pending data in period.
+func (r *RedisRecorder) DimensionsFor(ctx context.Context, period string) ([]string, error) {
+	return r.smembers(r.dimensionsKey(period))
+}
+
+// BucketFor reads dim's accumulated counters for period.
+func (r *RedisRecorder) BucketFor(ctx context.Context, period, dim string) (Bucket, error) {
+	conn, rdr, err := r.dial()
+	if err != nil {
+		return Bucket{}, err
+	}
+	defer conn.Close()
+
+	bucketStart, err := time.Parse(bucketLayout, period)
+	if err != nil {
+		return Bucket{}, fmt.Errorf("querystats: malformed period %q: %w", period, err)
+	}
+	service, cacheTier := splitDimension(dim)
+	bucket := Bucket{BucketStart: bucketStart, Service: service, CacheTier: cacheTier, FilterCounts: map[string]int64{}}
+
+	count, err := getInt(conn, rdr, r.countKey(period, dim))
+	if err != nil {
+		return Bucket{}, err
+	}
+	bucket.SampleCount = count
+
+	latencySum, err := getFloat(conn, rdr, r.latencySumKey(period, dim))
+	if err != nil {
+		return Bucket{}, err
+	}
+	bucket.TotalLatencyMS = latencySum
+
+	resultSum, err := getInt(conn, rdr, r.resultSumKey(period, dim))
+	if err != nil {
+		return Bucket{}, err
+	}
+	bucket.TotalResultCount = resultSum
+
+	if _, err := conn.Write(respwire.EncodeCommand("HGETALL", r.filterCountsKey(period, dim))); err != nil {
+		return Bucket{}, fmt.Errorf("querystats: reading filter counts for %s: %w", dim, err)
+	}
+	fields, err := respwire.ReadArray(rdr)
+	if err != nil {
+		return Bucket{}, fmt.Errorf("querystats: reading filter counts for %s: %w", dim, err)
+	}
+	for i := 0; i+1 < len(fields); i += 2 {
+		n, err := strconv.ParseInt(fields[i+1], 10, 64)
+		if err != nil {
+			continue
+		}
+		bucket.FilterCounts[fields[i]] = n
+	}
+
+	return bucket, nil
+}
+
+// ClearPeriod deletes every key recorded for period, once its samples
+// have been durably rolled up elsewhere.
+func (r *RedisRecorder) ClearPeriod(ctx context.Context, period string) error {
+	dims, err := r.DimensionsFor(ctx, period)
+	if err != nil {
+		return err
+	}
+
+	conn, rdr, err := r.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, dim := range dims {
+		for _, key := range []string{r.countKey(period, dim), r.latencySumKey(period, dim), r.resultSumKey(period, dim), r.filterCountsKey(period, dim)} {
+			if err := sendDel(conn, rdr, key); err != nil {
+				return fmt.Errorf("querystats: clearing %s: %w", period, err)
+			}
+		}
+	}
+	if err := sendDel(conn, rdr, r.dimensionsKey(period)); err != nil {
+		return fmt.Errorf("querystats: clearing %s: %w", period, err)
+	}
+	if _, err := conn.Write(respwire.EncodeCommand("SREM", periodsKey, period)); err != nil {
+		return fmt.Errorf("querystats: clearing %s: %w", period, err)
+	}
+	if _, err := respwire.ReadInteger(rdr); err != nil {
+		return fmt.Errorf("querystats: clearing %s: %w", period, err)
+	}
+	return nil
+}
+
+func (r *RedisRecorder) smembers(key string) ([]string, error) {
+	conn, rdr, err := r.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(respwire.EncodeCommand("SMEMBERS", key)); err != nil {
+		return nil, fmt.Errorf("querystats: reading %s: %w", key, err)
+	}
+	return respwire.ReadArray(rdr)
+}
+
+func getInt(conn net.Conn, rdr *bufio.Reader, key string) (int64, error) {
+	if _, err := conn.Write(respwire.EncodeCommand("GET", key)); err != nil {
+		return 0, fmt.Errorf("querystats: reading %s: %w", key, err)
+	}
+	value, ok, err := respwire.ReadBulkString(rdr)
+	if err != nil {
+		return 0, fmt.Errorf("querystats: reading %s: %w", key, err)
+	}
+	if !ok {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("querystats: malformed counter %s: %q", key, value)
+	}
+	return n, nil
+}
+
+func getFloat(conn net.Conn, rdr *bufio.Reader, key string) (float64, error) {
+	if _, err := conn.Write(respwire.EncodeCommand("GET", key)); err != nil {
+		return 0, fmt.Errorf("querystats: reading %s: %w", key, err)
+	}
+	value, ok, err := respwire.ReadBulkString(rdr)
+	if err != nil {
+		return 0, fmt.Errorf("querystats: reading %s: %w", key, err)
+	}
+	if !ok {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("querystats: malformed counter %s: %q", key, value)
+	}
+	return f, nil
+}
+
+func sendIncrBy(conn net.Conn, rdr *bufio.Reader, key string, delta int64) error {
+	if _, err := conn.Write(respwire.EncodeCommand("INCRBY", key, strconv.FormatInt(delta, 10))); err != nil {
+		return err
+	}
+	_, err := respwire.ReadInteger(rdr)
+	return err
+}
+
+func sendIncrByFloat(conn net.Conn, rdr *bufio.Reader, key string, delta float64) error {
+	if _, err := conn.Write(respwire.EncodeCommand("INCRBYFLOAT", key, strconv.FormatFloat(delta, 'f', -1, 64))); err != nil {
+		return err
+	}
+	_, _, err := respwire.ReadBulkString(rdr)
+	return err
+}
+
+func sendHIncrBy(conn net.Conn, rdr *bufio.Reader, key, field string, delta int64) error {
+	if _, err := conn.Write(respwire.EncodeCommand("HINCRBY", key, field, strconv.FormatInt(delta, 10))); err != nil {
+		return err
+	}
+	_, err := respwire.ReadInteger(rdr)
+	return err
+}
+
+func sendSAdd(conn net.Conn, rdr *bufio.Reader, key, member string) error {
+	if _, err := conn.Write(respwire.EncodeCommand("SADD", key, member)); err != nil {
+		return err
+	}
+	_, err := respwire.ReadInteger(rdr)
+	return err
+}
+
+func sendDel(conn net.Conn, rdr *bufio.Reader, key string) error {
+	if _, err := conn.Write(respwire.EncodeCommand("DEL", key)); err != nil {
+		return err
+	}
+	_, err := respwire.ReadInteger(rdr)
+	return err
+}
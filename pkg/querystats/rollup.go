@@ -0,0 +1,50 @@
+package querystats
+
+import (
+	"context"
+	"fmt"
+)
+
+// Rollup sums RedisRecorder's pending per-minute buckets into Sink and
+// clears them from Redis once durably written, so Redis only ever holds
+// the current and not-yet-rolled-up periods.
+type Rollup struct {
+	Recorder *RedisRecorder
+	Sink     Sink
+}
+
+// Run rolls up every pending period and returns how many it processed.
+func (r *Rollup) Run(ctx context.Context) (int, error) {
+	periods, err := r.Recorder.PendingPeriods(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("querystats: listing pending periods: %w", err)
+	}
+
+	for _, period := range periods {
+		if err := r.rollupPeriod(ctx, period); err != nil {
+			return 0, fmt.Errorf("querystats: rolling up %s: %w", period, err)
+		}
+	}
+	return len(periods), nil
+}
+
+func (r *Rollup) rollupPeriod(ctx context.Context, period string) error {
+	dims, err := r.Recorder.DimensionsFor(ctx, period)
+	if err != nil {
+		return err
+	}
+
+	buckets := make([]Bucket, 0, len(dims))
+	for _, dim := range dims {
+		bucket, err := r.Recorder.BucketFor(ctx, period, dim)
+		if err != nil {
+			return err
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	if err := r.Sink.Write(ctx, buckets); err != nil {
+		return err
+	}
+	return r.Recorder.ClearPeriod(ctx, period)
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryLimiter is a Limiter backed by per-key token buckets held in
+// process memory. Rate and Burst describe the bucket: Rate tokens are
+// added per second, up to Burst tokens held at once.
+type MemoryLimiter struct {
+	Rate  float64
+	Burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens   float64
+	lastFill time.Time
+}
+
+// NewMemoryLimiter returns a MemoryLimiter refilling at rate tokens/sec
+// up to a maximum of burst tokens.
+func NewMemoryLimiter(rate, burst float64) *MemoryLimiter {
+	return &MemoryLimiter{Rate: rate, Burst: burst, buckets: map[string]*bucket{}}
+}
+
+// Allow implements Limiter.
+func (l *MemoryLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.Burst, lastFill: now}
+		l.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.lastFill).Seconds()
+	b.tokens += elapsed * l.Rate
+	if b.tokens > l.Burst {
+		b.tokens = l.Burst
+	}
+	b.lastFill = now
+
+	if b.tokens < 1 {
+		return false, nil
+	}
+	b.tokens--
+	return true, nil
+}
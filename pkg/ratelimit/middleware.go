@@ -0,0 +1,26 @@
+package ratelimit
+
+import "net/http"
+
+// Middleware wraps next, rejecting requests with 429 once keyFor(r)
+// exhausts its bucket in l.
+func Middleware(l Limiter, keyFor func(*http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, err := l.Allow(r.Context(), keyFor(r))
+		if err != nil {
+			http.Error(w, "ratelimit: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// KeyFromRemoteAddr is a common keyFor implementation: one bucket per
+// client address.
+func KeyFromRemoteAddr(r *http.Request) string {
+	return r.RemoteAddr
+}
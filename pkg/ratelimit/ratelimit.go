@@ -0,0 +1,15 @@
+// Package ratelimit provides token-bucket rate limiting with either an
+// in-memory backend (for a single replica) or a Redis backend (for a
+// fleet of replicas sharing one limit), behind a common Limiter
+// interface, plus an HTTP middleware so logservice, the ingester, and
+// the router enforce limits the same way instead of each growing a
+// bespoke implementation.
+package ratelimit
+
+import "context"
+
+// Limiter decides whether a call identified by key may proceed right
+// now, consuming one token from its bucket if so.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/cgb808/ZenGlowNext/pkg/respwire"
+)
+
+// RedisLimiter is a Limiter shared across replicas, approximating a
+// token bucket with a fixed-window counter per key: at most Limit calls
+// are allowed within each Window, using Redis INCR/EXPIRE rather than a
+// Lua script so it only needs the RESP primitives pkg/respwire already
+// speaks. This is coarser than MemoryLimiter's true token bucket (it can
+// allow a burst at a window boundary) but is enough for protecting a
+// backend from being overwhelmed.
+type RedisLimiter struct {
+	Addr    string
+	Prefix  string
+	Limit   int64
+	Window  time.Duration
+	Timeout time.Duration
+}
+
+// Allow implements Limiter.
+func (l *RedisLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	timeout := l.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	conn, err := net.DialTimeout("tcp", l.Addr, timeout)
+	if err != nil {
+		return false, fmt.Errorf("ratelimit: dial redis %s: %w", l.Addr, err)
+	}
+	defer conn.Close()
+
+	redisKey := l.Prefix + key
+	if _, err := conn.Write(respwire.EncodeCommand("INCR", redisKey)); err != nil {
+		return false, fmt.Errorf("ratelimit: write INCR: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	count, err := respwire.ReadInteger(reader)
+	if err != nil {
+		return false, fmt.Errorf("ratelimit: read INCR reply: %w", err)
+	}
+
+	if count == 1 {
+		seconds := int(l.Window.Seconds())
+		if seconds < 1 {
+			seconds = 1
+		}
+		if _, err := conn.Write(respwire.EncodeCommand("EXPIRE", redisKey, strconv.Itoa(seconds))); err != nil {
+			return false, fmt.Errorf("ratelimit: write EXPIRE: %w", err)
+		}
+		if _, err := respwire.ReadInteger(reader); err != nil {
+			return false, fmt.Errorf("ratelimit: read EXPIRE reply: %w", err)
+		}
+	}
+
+	return count <= l.Limit, nil
+}
@@ -0,0 +1,126 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// PostgresSource reads changed rows from Table in DatabaseURL, ordered
+// by UpdatedAtColumn, via psql.
+type PostgresSource struct {
+	DatabaseURL     string
+	Table           string
+	UpdatedAtColumn string
+	Columns         []string
+}
+
+// ChangesSince implements Source, matching its doc comment's "at or
+// after since" with >= rather than > (see Worker's lastSeenIDs for why
+// since's own instant has to be included rather than just the instants
+// after it: re-including it, deduped by ID on the way back in Worker, is
+// what catches a row whose UpdatedAt ties the watermark but whose
+// transaction commits after Worker already advanced past it).
+func (s PostgresSource) ChangesSince(ctx context.Context, since time.Time) ([]Row, error) {
+	columns := append([]string{"id", s.UpdatedAtColumn}, s.Columns...)
+	sql := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE %s >= '%s' ORDER BY %s ASC",
+		strings.Join(columns, ", "), s.Table, s.UpdatedAtColumn,
+		since.Format("2006-01-02T15:04:05.000Z07:00"), s.UpdatedAtColumn,
+	)
+
+	out, err := runPsqlCSV(ctx, s.DatabaseURL, sql)
+	if err != nil {
+		return nil, fmt.Errorf("replication: reading changes: %w", err)
+	}
+
+	var rows []Row
+	for _, record := range out {
+		if len(record) < 2 {
+			continue
+		}
+		updatedAt, err := time.Parse("2006-01-02 15:04:05", record[1])
+		if err != nil {
+			continue
+		}
+		row := Row{ID: record[0], UpdatedAt: updatedAt, Columns: map[string]string{}}
+		for i, col := range s.Columns {
+			if idx := 2 + i; idx < len(record) {
+				row.Columns[col] = record[idx]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// PostgresSink applies rows to Table in DatabaseURL with
+// "INSERT ... ON CONFLICT (id) DO UPDATE" guarded by UpdatedAtColumn, so
+// a row only overwrites the secondary's copy if it is actually newer
+// (last-writer-wins).
+type PostgresSink struct {
+	DatabaseURL     string
+	Table           string
+	UpdatedAtColumn string
+}
+
+// Apply implements Sink.
+func (s PostgresSink) Apply(ctx context.Context, rows []Row) error {
+	for _, row := range rows {
+		if err := s.applyOne(ctx, row); err != nil {
+			return fmt.Errorf("replication: applying row %s: %w", row.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s PostgresSink) applyOne(ctx context.Context, row Row) error {
+	columns := []string{"id", s.UpdatedAtColumn}
+	values := []string{quoteLiteral(row.ID), quoteLiteral(row.UpdatedAt.Format("2006-01-02T15:04:05.000Z07:00"))}
+	var setClauses []string
+
+	for col, val := range row.Columns {
+		columns = append(columns, col)
+		values = append(values, quoteLiteral(val))
+		setClauses = append(setClauses, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+	}
+	setClauses = append(setClauses, fmt.Sprintf("%s = EXCLUDED.%s", s.UpdatedAtColumn, s.UpdatedAtColumn))
+
+	sql := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (id) DO UPDATE SET %s WHERE %s.%s < EXCLUDED.%s;",
+		s.Table, strings.Join(columns, ", "), strings.Join(values, ", "),
+		strings.Join(setClauses, ", "), s.Table, s.UpdatedAtColumn, s.UpdatedAtColumn,
+	)
+
+	return runPsql(ctx, s.DatabaseURL, sql)
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func runPsql(ctx context.Context, databaseURL, sql string) error {
+	cmd := exec.CommandContext(ctx, "psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", sql)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func runPsqlCSV(ctx context.Context, databaseURL, sql string) ([][]string, error) {
+	copySQL := fmt.Sprintf(`\copy (%s) TO STDOUT WITH (FORMAT csv)`, sql)
+	cmd := exec.CommandContext(ctx, "psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", copySQL)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return csv.NewReader(&stdout).ReadAll()
+}
@@ -0,0 +1,62 @@
+// Package reqid propagates a request ID through logservice, the
+// ingester, the router, and CanonicalService, via an HTTP header that
+// each hop both reads and forwards, so a single request can be traced
+// across all of them in logs.
+package reqid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Header is the HTTP header request IDs travel in between services.
+const Header = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// FromContext returns the request ID attached to ctx, or "" if none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestID returns a context carrying id, retrievable with
+// FromContext.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// New generates a fresh random request ID.
+func New() string {
+	var b [16]byte
+	// crypto/rand.Read on this size never returns a short read in
+	// practice; an error here means the system RNG is broken, which
+	// is not something a request ID can recover from anyway.
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Middleware wraps next: if the incoming request carries Header, that
+// value is propagated; otherwise a new request ID is generated. Either
+// way, the ID is attached to the request context and echoed back on
+// Header in the response.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if id == "" {
+			id = New()
+		}
+
+		w.Header().Set(Header, id)
+		ctx := WithRequestID(r.Context(), id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// SetOnRequest sets Header on req to id, for propagating it to a
+// downstream service call.
+func SetOnRequest(req *http.Request, id string) {
+	req.Header.Set(Header, id)
+}
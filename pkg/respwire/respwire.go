@@ -0,0 +1,204 @@
+// Package respwire is a minimal RESP (REdis Serialization Protocol)
+// client used by the handful of packages in this repo that talk to Redis
+// directly over TCP instead of through a client library we have no way
+// to fetch offline (pkg/nodeset, pkg/pipeline, pkg/flags, and now
+// pkg/bus). It only implements what those callers need: encoding a
+// command and decoding bulk strings, arrays of bulk strings, and (via
+// ReadReply) the nested array shapes Stream commands like XRANGE return.
+package respwire
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EncodeCommand renders parts as a RESP array of bulk strings, the wire
+// format Redis expects for commands.
+func EncodeCommand(parts ...string) []byte {
+	out := fmt.Sprintf("*%d\r\n", len(parts))
+	for _, p := range parts {
+		out += fmt.Sprintf("$%d\r\n%s\r\n", len(p), p)
+	}
+	return []byte(out)
+}
+
+// ReadBulkString reads a single RESP reply expected to be a bulk string.
+// ok is false for a RESP nil bulk string ("$-1").
+func ReadBulkString(r *bufio.Reader) (value string, ok bool, err error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", false, err
+	}
+	if len(line) < 3 || line[0] != '$' {
+		return "", false, fmt.Errorf("respwire: unexpected reply: %q", line)
+	}
+	if line[1] == '-' {
+		return "", false, nil
+	}
+
+	var n int
+	if _, err := fmt.Sscanf(line[1:], "%d", &n); err != nil {
+		return "", false, fmt.Errorf("respwire: malformed bulk length: %q", line)
+	}
+
+	buf := make([]byte, n+2) // payload + trailing \r\n
+	if err := readFull(r, buf); err != nil {
+		return "", false, err
+	}
+	return string(buf[:n]), true, nil
+}
+
+// ReadSimpleString reads a RESP simple string reply, e.g. the "+OK"
+// Redis sends back for SET.
+func ReadSimpleString(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 1 || line[0] != '+' {
+		return "", fmt.Errorf("respwire: unexpected reply: %q", line)
+	}
+	return strings.TrimRight(line[1:], "\r\n"), nil
+}
+
+// ReadInteger reads a RESP integer reply, e.g. what INCR and EXPIRE
+// return.
+func ReadInteger(r *bufio.Reader) (int64, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	if len(line) < 2 || line[0] != ':' {
+		return 0, fmt.Errorf("respwire: unexpected reply: %q", line)
+	}
+	return strconv.ParseInt(strings.TrimRight(line[1:], "\r\n"), 10, 64)
+}
+
+// ReadArray reads a RESP array reply where every element is a bulk
+// string (Redis commands like SMEMBERS and HKEYS reply this way).
+func ReadArray(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 2 || line[0] != '*' {
+		return nil, fmt.Errorf("respwire: unexpected reply: %q", line)
+	}
+
+	var n int
+	if _, err := fmt.Sscanf(line[1:], "%d", &n); err != nil {
+		return nil, fmt.Errorf("respwire: malformed array length: %q", line)
+	}
+	if n < 0 {
+		return nil, nil
+	}
+
+	items := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		v, ok, err := ReadBulkString(r)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			items = append(items, v)
+		}
+	}
+	return items, nil
+}
+
+// Reply is a decoded RESP value of arbitrary shape: a string (from a
+// bulk or simple string), an int64, a nested []Reply (from an array), or
+// nil (from a null bulk string or empty array element). It exists for
+// replies ReadArray can't express, e.g. the array-of-[id,
+// array-of-fields] shape XRANGE returns.
+type Reply interface{}
+
+// ErrReply is a RESP error reply ("-ERR ...", "-BUSYGROUP ...").
+// ReadReply returns it as the error value rather than folding it into
+// a generic "unexpected reply" message, so a caller can recognize a
+// specific error code (e.g. consumer-group commands checking for
+// BUSYGROUP to treat "already exists" as success).
+type ErrReply struct {
+	Message string
+}
+
+func (e *ErrReply) Error() string {
+	return e.Message
+}
+
+// ReadReply reads and fully decodes one RESP value of any shape,
+// recursing into nested arrays.
+func ReadReply(r *bufio.Reader) (Reply, error) {
+	prefix, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch prefix[0] {
+	case '$':
+		v, ok, err := ReadBulkString(r)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, nil
+		}
+		return v, nil
+	case '+':
+		return ReadSimpleString(r)
+	case ':':
+		return ReadInteger(r)
+	case '*':
+		return readReplyArray(r)
+	case '-':
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		return nil, &ErrReply{Message: strings.TrimRight(line[1:], "\r\n")}
+	default:
+		return nil, fmt.Errorf("respwire: unexpected reply type: %q", prefix[0])
+	}
+}
+
+func readReplyArray(r *bufio.Reader) (Reply, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 2 || line[0] != '*' {
+		return nil, fmt.Errorf("respwire: unexpected reply: %q", line)
+	}
+
+	var n int
+	if _, err := fmt.Sscanf(line[1:], "%d", &n); err != nil {
+		return nil, fmt.Errorf("respwire: malformed array length: %q", line)
+	}
+	if n < 0 {
+		return nil, nil
+	}
+
+	items := make([]Reply, 0, n)
+	for i := 0; i < n; i++ {
+		v, err := ReadReply(r)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+func readFull(r *bufio.Reader, buf []byte) error {
+	total := 0
+	for total < len(buf) {
+		n, err := r.Read(buf[total:])
+		total += n
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
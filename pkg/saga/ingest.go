@@ -0,0 +1,158 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cgb808/ZenGlowNext/pkg/bus"
+	"github.com/cgb808/ZenGlowNext/pkg/pipeline"
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// IngestDefinitionName is the Definition.Name NewIngestDefinition
+// registers under, and the value persisted in Run.Definition for runs
+// started against it.
+const IngestDefinitionName = "ingest"
+
+// ingestSegmentKey, ingestEventIDsKey are the State keys the ingest
+// Definition's steps read and write, in the order they're populated.
+const (
+	ingestSegmentKey  = "segment"
+	ingestEventIDsKey = "event_ids"
+)
+
+// NewIngestDefinition builds the "persist record -> compute embedding ->
+// update index -> notify" Definition described by this package's
+// motivating request. pkg/pipeline.FrameSink already persists a segment
+// and computes its embeddings as one call (see pipeline.Coordinator),
+// so "persist record" and "compute embedding" are combined into a
+// single ingest step here rather than split into two that pipeline has
+// no seam for; splitting that call further would require changing
+// FrameSink's contract, which is out of scope for this workflow.
+func NewIngestDefinition(sink pipeline.FrameSink, index *topkclient.Client, notifier bus.Publisher, notifyTopic string) Definition {
+	return Definition{
+		Name: IngestDefinitionName,
+		Steps: []Step{
+			{
+				Name: "ingest",
+				Run: func(ctx context.Context, state State) (State, error) {
+					seg, err := segmentFromState(state)
+					if err != nil {
+						return nil, err
+					}
+					eventIDs, err := sink.Ingest(ctx, seg)
+					if err != nil {
+						return nil, fmt.Errorf("ingesting segment %s: %w", seg.ID, err)
+					}
+					next := cloneState(state)
+					next[ingestEventIDsKey] = eventIDs
+					return next, nil
+				},
+				// Nothing to compensate: a failed Ingest call either
+				// wrote nothing or left rows the next attempt upserts
+				// over, so there is no separate undo step.
+			},
+			{
+				Name: "update-index",
+				Run: func(ctx context.Context, state State) (State, error) {
+					eventIDs, err := eventIDsFromState(state)
+					if err != nil {
+						return nil, err
+					}
+					if _, err := index.Call(ctx, "upsert", map[string]interface{}{"event_ids": eventIDs}); err != nil {
+						return nil, fmt.Errorf("updating index for %d event(s): %w", len(eventIDs), err)
+					}
+					return state, nil
+				},
+				Compensate: func(ctx context.Context, state State) error {
+					eventIDs, err := eventIDsFromState(state)
+					if err != nil {
+						return err
+					}
+					_, err = index.Call(ctx, "delete", map[string]interface{}{"event_ids": eventIDs})
+					return err
+				},
+			},
+			{
+				Name: "notify",
+				Run: func(ctx context.Context, state State) (State, error) {
+					eventIDs, err := eventIDsFromState(state)
+					if err != nil {
+						return nil, err
+					}
+					payload, err := notifyPayload(eventIDs)
+					if err != nil {
+						return nil, err
+					}
+					if err := notifier.Publish(ctx, notifyTopic, payload); err != nil {
+						return nil, fmt.Errorf("notifying %s: %w", notifyTopic, err)
+					}
+					return state, nil
+				},
+				// Notifications are fire-and-forget; there is nothing
+				// to undo if a later step fails (there is no later
+				// step after notify).
+			},
+		},
+	}
+}
+
+func segmentFromState(state State) (pipeline.Segment, error) {
+	raw, ok := state[ingestSegmentKey]
+	if !ok {
+		return pipeline.Segment{}, fmt.Errorf("saga: ingest: missing %q in state", ingestSegmentKey)
+	}
+	switch v := raw.(type) {
+	case pipeline.Segment:
+		return v, nil
+	case map[string]interface{}:
+		// Runs reloaded from a Store decode State through JSON, which
+		// turns the original Segment struct into a plain map.
+		seg := pipeline.Segment{}
+		if id, ok := v["ID"].(string); ok {
+			seg.ID = id
+		}
+		if path, ok := v["Path"].(string); ok {
+			seg.Path = path
+		}
+		return seg, nil
+	default:
+		return pipeline.Segment{}, fmt.Errorf("saga: ingest: unexpected %q type %T", ingestSegmentKey, raw)
+	}
+}
+
+func eventIDsFromState(state State) ([]string, error) {
+	raw, ok := state[ingestEventIDsKey]
+	if !ok {
+		return nil, fmt.Errorf("saga: ingest: missing %q in state", ingestEventIDsKey)
+	}
+	switch v := raw.(type) {
+	case []string:
+		return v, nil
+	case []interface{}:
+		ids := make([]string, 0, len(v))
+		for _, id := range v {
+			s, ok := id.(string)
+			if !ok {
+				return nil, fmt.Errorf("saga: ingest: non-string event id %v", id)
+			}
+			ids = append(ids, s)
+		}
+		return ids, nil
+	default:
+		return nil, fmt.Errorf("saga: ingest: unexpected %q type %T", ingestEventIDsKey, raw)
+	}
+}
+
+func notifyPayload(eventIDs []string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"event_ids": eventIDs})
+}
+
+func cloneState(state State) State {
+	next := make(State, len(state)+1)
+	for k, v := range state {
+		next[k] = v
+	}
+	return next
+}
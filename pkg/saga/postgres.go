@@ -0,0 +1,151 @@
+package saga
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PostgresStore persists Runs to Table via psql, the same approach
+// pkg/dlq.PostgresStore and pkg/pgbootstrap use to avoid depending on a
+// Go Postgres driver this repo can't fetch offline. State is stored as
+// JSON text.
+type PostgresStore struct {
+	DatabaseURL string
+	Table       string
+}
+
+func (s PostgresStore) table() string {
+	if s.Table == "" {
+		return "saga_runs"
+	}
+	return s.Table
+}
+
+// Create implements Store.
+func (s PostgresStore) Create(ctx context.Context, run Run) error {
+	stateJSON, err := json.Marshal(run.State)
+	if err != nil {
+		return fmt.Errorf("saga: encoding state for run %s: %w", run.ID, err)
+	}
+	sql := fmt.Sprintf(
+		`INSERT INTO %s (id, definition, state_json, step_index, status, error, created_at, updated_at)
+		 VALUES (%s, %s, %s, %d, %s, %s, %s, %s);`,
+		s.table(),
+		quoteLiteral(run.ID), quoteLiteral(run.Definition), quoteLiteral(string(stateJSON)), run.StepIndex,
+		quoteLiteral(string(run.Status)), quoteLiteral(run.Error),
+		quoteLiteral(run.CreatedAt.Format(timeLayout)), quoteLiteral(run.UpdatedAt.Format(timeLayout)),
+	)
+	return runPsql(ctx, s.DatabaseURL, sql)
+}
+
+// Update implements Store.
+func (s PostgresStore) Update(ctx context.Context, run Run) error {
+	stateJSON, err := json.Marshal(run.State)
+	if err != nil {
+		return fmt.Errorf("saga: encoding state for run %s: %w", run.ID, err)
+	}
+	sql := fmt.Sprintf(
+		`UPDATE %s SET state_json = %s, step_index = %d, status = %s, error = %s, updated_at = %s
+		 WHERE id = %s;`,
+		s.table(),
+		quoteLiteral(string(stateJSON)), run.StepIndex, quoteLiteral(string(run.Status)), quoteLiteral(run.Error),
+		quoteLiteral(run.UpdatedAt.Format(timeLayout)), quoteLiteral(run.ID),
+	)
+	return runPsql(ctx, s.DatabaseURL, sql)
+}
+
+// Get implements Store.
+func (s PostgresStore) Get(ctx context.Context, id string) (Run, error) {
+	sql := fmt.Sprintf(
+		"SELECT id, definition, state_json, step_index, status, error, created_at, updated_at FROM %s WHERE id = %s",
+		s.table(), quoteLiteral(id),
+	)
+	records, err := runPsqlCSV(ctx, s.DatabaseURL, sql)
+	if err != nil {
+		return Run{}, fmt.Errorf("saga: getting run %s: %w", id, err)
+	}
+	if len(records) == 0 {
+		return Run{}, fmt.Errorf("saga: no run %s", id)
+	}
+	return runFromRecord(records[0])
+}
+
+// ListByStatus implements Store.
+func (s PostgresStore) ListByStatus(ctx context.Context, status Status) ([]Run, error) {
+	sql := fmt.Sprintf(
+		"SELECT id, definition, state_json, step_index, status, error, created_at, updated_at FROM %s WHERE status = %s ORDER BY updated_at ASC",
+		s.table(), quoteLiteral(string(status)),
+	)
+	records, err := runPsqlCSV(ctx, s.DatabaseURL, sql)
+	if err != nil {
+		return nil, fmt.Errorf("saga: listing runs with status %s: %w", status, err)
+	}
+
+	runs := make([]Run, 0, len(records))
+	for _, r := range records {
+		run, err := runFromRecord(r)
+		if err != nil {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+func runFromRecord(r []string) (Run, error) {
+	if len(r) < 8 {
+		return Run{}, fmt.Errorf("saga: malformed row: %v", r)
+	}
+	var state State
+	if err := json.Unmarshal([]byte(r[2]), &state); err != nil {
+		return Run{}, fmt.Errorf("saga: decoding state: %w", err)
+	}
+	stepIndex, _ := strconv.Atoi(r[3])
+	createdAt, _ := time.Parse("2006-01-02 15:04:05", r[6])
+	updatedAt, _ := time.Parse("2006-01-02 15:04:05", r[7])
+	return Run{
+		ID:         r[0],
+		Definition: r[1],
+		State:      state,
+		StepIndex:  stepIndex,
+		Status:     Status(r[4]),
+		Error:      r[5],
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+	}, nil
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func runPsql(ctx context.Context, databaseURL, sql string) error {
+	cmd := exec.CommandContext(ctx, "psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", sql)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func runPsqlCSV(ctx context.Context, databaseURL, sql string) ([][]string, error) {
+	copySQL := fmt.Sprintf(`\copy (%s) TO STDOUT WITH (FORMAT csv)`, sql)
+	cmd := exec.CommandContext(ctx, "psql", databaseURL, "-v", "ON_ERROR_STOP=1", "-c", copySQL)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return csv.NewReader(&stdout).ReadAll()
+}
@@ -0,0 +1,197 @@
+// Package saga coordinates a multi-step workflow as a sequence of
+// resumable steps, each with an optional compensation to undo it if a
+// later step fails. It exists so a workflow like ingest's
+// "persist record -> compute embedding -> update index -> notify" can
+// crash partway through and be resumed (or rolled back) from exactly
+// where it left off, instead of leaving an event stored but unsearchable
+// because the process died between steps. Progress is persisted through
+// a Store (see PostgresStore) rather than kept only in memory.
+package saga
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// State is the data a saga's steps read and write as they run. Each
+// step's Run receives the State left by the previous step and returns
+// the State for the next one.
+type State map[string]interface{}
+
+// Status is where a Run currently stands.
+type Status string
+
+const (
+	StatusRunning      Status = "running"
+	StatusCompleted    Status = "completed"
+	StatusCompensating Status = "compensating"
+	StatusCompensated  Status = "compensated"
+	StatusFailed       Status = "failed"
+)
+
+// Step is one unit of work in a Definition. Compensate undoes whatever
+// Run did and may be nil for steps with nothing to undo (e.g. a
+// read-only check).
+type Step struct {
+	Name       string
+	Run        func(ctx context.Context, state State) (State, error)
+	Compensate func(ctx context.Context, state State) error
+}
+
+// Definition is a named, ordered list of Steps. Coordinator looks
+// Definitions up by name when resuming a Run, so the name persisted in
+// the Store must stay stable across deploys.
+type Definition struct {
+	Name  string
+	Steps []Step
+}
+
+// Run is one execution of a Definition, persisted so it can be resumed
+// after a crash.
+type Run struct {
+	ID         string
+	Definition string
+	State      State
+	StepIndex  int // index of the next step to run, or len(Steps) once completed
+	Status     Status
+	Error      string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Store persists Run state between steps.
+type Store interface {
+	Create(ctx context.Context, run Run) error
+	Update(ctx context.Context, run Run) error
+	Get(ctx context.Context, id string) (Run, error)
+	ListByStatus(ctx context.Context, status Status) ([]Run, error)
+}
+
+// Coordinator runs Definitions and persists their progress through a
+// Store after every step, so Resume can pick a Run back up from
+// wherever it stopped.
+type Coordinator struct {
+	Store       Store
+	Definitions map[string]Definition
+}
+
+// NewCoordinator returns a Coordinator serving the given Definitions,
+// keyed by their own Name.
+func NewCoordinator(store Store, defs ...Definition) *Coordinator {
+	byName := make(map[string]Definition, len(defs))
+	for _, d := range defs {
+		byName[d.Name] = d
+	}
+	return &Coordinator{Store: store, Definitions: byName}
+}
+
+// Start creates a new Run of definitionName with the given initial
+// State and drives it to completion or compensation.
+func (c *Coordinator) Start(ctx context.Context, definitionName, id string, initial State) (Run, error) {
+	def, ok := c.Definitions[definitionName]
+	if !ok {
+		return Run{}, fmt.Errorf("saga: unknown definition %q", definitionName)
+	}
+
+	now := time.Now()
+	run := Run{
+		ID:         id,
+		Definition: definitionName,
+		State:      initial,
+		StepIndex:  0,
+		Status:     StatusRunning,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := c.Store.Create(ctx, run); err != nil {
+		return Run{}, fmt.Errorf("saga: creating run %s: %w", id, err)
+	}
+	return c.drive(ctx, def, run)
+}
+
+// Resume loads a previously started Run and continues it from its
+// persisted StepIndex, re-running compensation if it was interrupted
+// mid-rollback.
+func (c *Coordinator) Resume(ctx context.Context, id string) (Run, error) {
+	run, err := c.Store.Get(ctx, id)
+	if err != nil {
+		return Run{}, fmt.Errorf("saga: loading run %s: %w", id, err)
+	}
+	def, ok := c.Definitions[run.Definition]
+	if !ok {
+		return Run{}, fmt.Errorf("saga: unknown definition %q for run %s", run.Definition, id)
+	}
+
+	switch run.Status {
+	case StatusCompleted, StatusCompensated, StatusFailed:
+		return run, nil
+	case StatusCompensating:
+		return c.compensate(ctx, def, run, run.Error)
+	default:
+		return c.drive(ctx, def, run)
+	}
+}
+
+// drive runs def's steps starting at run.StepIndex, persisting State
+// after each one, and falls back to compensation on the first failure.
+func (c *Coordinator) drive(ctx context.Context, def Definition, run Run) (Run, error) {
+	for run.StepIndex < len(def.Steps) {
+		step := def.Steps[run.StepIndex]
+		next, err := step.Run(ctx, run.State)
+		if err != nil {
+			return c.compensate(ctx, def, run, fmt.Sprintf("step %q: %v", step.Name, err))
+		}
+
+		run.State = next
+		run.StepIndex++
+		run.UpdatedAt = time.Now()
+		if err := c.Store.Update(ctx, run); err != nil {
+			return run, fmt.Errorf("saga: persisting run %s after step %q: %w", run.ID, step.Name, err)
+		}
+	}
+
+	run.Status = StatusCompleted
+	run.UpdatedAt = time.Now()
+	if err := c.Store.Update(ctx, run); err != nil {
+		return run, fmt.Errorf("saga: persisting completed run %s: %w", run.ID, err)
+	}
+	return run, nil
+}
+
+// compensate unwinds the steps of def that already ran (indices
+// [0, run.StepIndex)) in reverse order, marking run failed with
+// failureReason once it stops.
+func (c *Coordinator) compensate(ctx context.Context, def Definition, run Run, failureReason string) (Run, error) {
+	run.Status = StatusCompensating
+	run.Error = failureReason
+	run.UpdatedAt = time.Now()
+	if err := c.Store.Update(ctx, run); err != nil {
+		return run, fmt.Errorf("saga: persisting run %s before compensation: %w", run.ID, err)
+	}
+
+	for run.StepIndex > 0 {
+		step := def.Steps[run.StepIndex-1]
+		if step.Compensate != nil {
+			if err := step.Compensate(ctx, run.State); err != nil {
+				run.Status = StatusFailed
+				run.Error = fmt.Sprintf("compensating step %q: %v (original failure: %s)", step.Name, err, run.Error)
+				run.UpdatedAt = time.Now()
+				c.Store.Update(ctx, run)
+				return run, fmt.Errorf("saga: run %s: %s", run.ID, run.Error)
+			}
+		}
+		run.StepIndex--
+		run.UpdatedAt = time.Now()
+		if err := c.Store.Update(ctx, run); err != nil {
+			return run, fmt.Errorf("saga: persisting run %s during compensation: %w", run.ID, err)
+		}
+	}
+
+	run.Status = StatusCompensated
+	run.UpdatedAt = time.Now()
+	if err := c.Store.Update(ctx, run); err != nil {
+		return run, fmt.Errorf("saga: persisting compensated run %s: %w", run.ID, err)
+	}
+	return run, fmt.Errorf("saga: run %s failed and was compensated: %s", run.ID, run.Error)
+}
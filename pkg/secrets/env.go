@@ -0,0 +1,20 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// EnvSource reads secrets directly from environment variables, named
+// exactly as given to Get (e.g. "DATABASE_URL").
+type EnvSource struct{}
+
+// Get implements Source.
+func (EnvSource) Get(ctx context.Context, key string) (string, error) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("secrets: %s not set in environment", key)
+	}
+	return v, nil
+}
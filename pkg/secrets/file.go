@@ -0,0 +1,27 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileSource reads secrets from individual files under Dir, the layout
+// Kubernetes and most secret-mount sidecars use (one file per secret,
+// file contents is the value). Key is lower-cased to form the filename,
+// e.g. "DATABASE_URL" is read from Dir/database_url.
+type FileSource struct {
+	Dir string
+}
+
+// Get implements Source.
+func (s FileSource) Get(ctx context.Context, key string) (string, error) {
+	path := filepath.Join(s.Dir, strings.ToLower(key))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("secrets: reading %s: %w", path, err)
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
@@ -0,0 +1,105 @@
+// Package secrets resolves connection strings, API keys, and TLS
+// material (DATABASE_URL, REDIS_URL, and similar) from whichever backing
+// store an environment provides, instead of the raw os.Getenv calls that
+// used to be scattered through the various command mains. A Resolver
+// caches looked-up values and can notify callers when a secret rotates,
+// so long-running services don't need to restart to pick up a new key.
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Source looks up a single secret by name. Callers ask a Resolver, not a
+// Source directly, so lookups are cached and can be watched for
+// rotation.
+type Source interface {
+	Get(ctx context.Context, key string) (string, error)
+}
+
+// Resolver wraps a Source with a cache and rotation callbacks.
+type Resolver struct {
+	source Source
+	ttl    time.Duration
+
+	mu       sync.Mutex
+	cached   map[string]cacheEntry
+	watchers map[string][]func(key, value string)
+}
+
+type cacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// New returns a Resolver backed by source. Values are cached for ttl; a
+// ttl of zero disables caching and resolves on every Get.
+func New(source Source, ttl time.Duration) *Resolver {
+	return &Resolver{
+		source:   source,
+		ttl:      ttl,
+		cached:   map[string]cacheEntry{},
+		watchers: map[string][]func(key, value string){},
+	}
+}
+
+// Get returns the current value for key, resolving from the underlying
+// Source if the cached value is missing or has expired.
+func (r *Resolver) Get(ctx context.Context, key string) (string, error) {
+	r.mu.Lock()
+	entry, ok := r.cached[key]
+	r.mu.Unlock()
+	if ok && r.ttl > 0 && time.Now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	value, err := r.source.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	r.store(key, value)
+	return value, nil
+}
+
+// Watch registers onRotate to be called whenever Refresh observes key's
+// value change from what was previously cached.
+func (r *Resolver) Watch(key string, onRotate func(key, value string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.watchers[key] = append(r.watchers[key], onRotate)
+}
+
+// Refresh re-resolves key from the underlying Source, updates the cache,
+// and notifies any watchers if the value changed.
+func (r *Resolver) Refresh(ctx context.Context, key string) error {
+	value, err := r.source.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	prev, had := r.cached[key]
+	changed := !had || prev.value != value
+	watchers := append([]func(key, value string){}, r.watchers[key]...)
+	r.mu.Unlock()
+
+	r.store(key, value)
+	if changed {
+		for _, w := range watchers {
+			w(key, value)
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) store(key, value string) {
+	entry := cacheEntry{value: value}
+	if r.ttl > 0 {
+		entry.expiresAt = time.Now().Add(r.ttl)
+	}
+	r.mu.Lock()
+	r.cached[key] = entry
+	r.mu.Unlock()
+}
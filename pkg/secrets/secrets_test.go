@@ -0,0 +1,71 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+)
+
+type countingSource struct {
+	values map[string]string
+	calls  int
+}
+
+func (s *countingSource) Get(ctx context.Context, key string) (string, error) {
+	s.calls++
+	return s.values[key], nil
+}
+
+func TestGetZeroTTLResolvesEveryCall(t *testing.T) {
+	src := &countingSource{values: map[string]string{"k": "v"}}
+	r := New(src, 0)
+
+	for i := 0; i < 3; i++ {
+		v, err := r.Get(context.Background(), "k")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if v != "v" {
+			t.Fatalf("Get = %q, want %q", v, "v")
+		}
+	}
+
+	if src.calls != 3 {
+		t.Fatalf("source called %d times, want 3 (ttl=0 must resolve every Get)", src.calls)
+	}
+}
+
+func TestGetPositiveTTLCaches(t *testing.T) {
+	src := &countingSource{values: map[string]string{"k": "v"}}
+	r := New(src, 0) // ttl set directly below to avoid a real sleep in the test
+	r.ttl = 1 << 40  // effectively "never expires" for the duration of this test
+
+	for i := 0; i < 3; i++ {
+		if _, err := r.Get(context.Background(), "k"); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+
+	if src.calls != 1 {
+		t.Fatalf("source called %d times, want 1 (positive ttl must cache)", src.calls)
+	}
+}
+
+func TestWatchNotifiedOnRefreshChange(t *testing.T) {
+	src := &countingSource{values: map[string]string{"k": "v1"}}
+	r := New(src, 0)
+
+	var got string
+	r.Watch("k", func(key, value string) { got = value })
+
+	if _, err := r.Get(context.Background(), "k"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	src.values["k"] = "v2"
+	if err := r.Refresh(context.Background(), "k"); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	if got != "v2" {
+		t.Fatalf("watcher got %q, want %q", got, "v2")
+	}
+}
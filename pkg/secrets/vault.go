@@ -0,0 +1,80 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// VaultSource reads secrets from a HashiCorp Vault KV v2 mount over
+// Vault's HTTP API, which is plain JSON, so it needs no SDK the repo
+// would otherwise have no way to fetch offline. Key is interpreted as
+// "<secret-path>#<field>", e.g. "database/primary#DATABASE_URL" reads
+// the DATABASE_URL field of the secret stored at database/primary.
+type VaultSource struct {
+	Addr       string
+	Token      string
+	Mount      string // defaults to "secret"
+	HTTPClient *http.Client
+}
+
+type vaultKVv2Response struct {
+	Data struct {
+		Data map[string]string `json:"data"`
+	} `json:"data"`
+}
+
+// Get implements Source.
+func (s VaultSource) Get(ctx context.Context, key string) (string, error) {
+	path, field, err := splitVaultKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	mount := s.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/data/%s", s.Addr, mount, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("secrets: building vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", s.Token)
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("secrets: vault request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secrets: vault returned %s for %s", resp.Status, path)
+	}
+
+	var parsed vaultKVv2Response
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("secrets: decoding vault response: %w", err)
+	}
+
+	value, ok := parsed.Data.Data[field]
+	if !ok {
+		return "", fmt.Errorf("secrets: vault secret %s has no field %s", path, field)
+	}
+	return value, nil
+}
+
+func splitVaultKey(key string) (path, field string, err error) {
+	for i := len(key) - 1; i >= 0; i-- {
+		if key[i] == '#' {
+			return key[:i], key[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("secrets: vault key %q must be of the form path#field", key)
+}
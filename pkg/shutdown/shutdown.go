@@ -0,0 +1,69 @@
+// Package shutdown is the graceful-shutdown runner every main should
+// use: it waits for SIGINT/SIGTERM, then stops registered components in
+// the order they were added (so e.g. an HTTP listener stops accepting
+// before its background workers drain), each with its own timeout.
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Component is one thing to stop on shutdown, e.g. an http.Server (via
+// its Shutdown method) or a background worker loop.
+type Component struct {
+	Name    string
+	Stop    func(ctx context.Context) error
+	Timeout time.Duration
+}
+
+// Runner collects Components and stops them in registration order when
+// the process receives SIGINT or SIGTERM.
+type Runner struct {
+	mu         sync.Mutex
+	components []Component
+}
+
+// New returns an empty Runner.
+func New() *Runner {
+	return &Runner{}
+}
+
+// Register adds c to the shutdown sequence. Components are stopped in
+// the order Register was called.
+func (r *Runner) Register(c Component) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.components = append(r.components, c)
+}
+
+// Wait blocks until SIGINT or SIGTERM, then stops every registered
+// Component in order, logging (via onErr) any that fails or times out,
+// and returns once they've all been given a chance to stop.
+func (r *Runner) Wait(onErr func(component string, err error)) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	<-sigCh
+
+	r.mu.Lock()
+	components := append([]Component(nil), r.components...)
+	r.mu.Unlock()
+
+	for _, c := range components {
+		timeout := c.Timeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := c.Stop(ctx)
+		cancel()
+		if err != nil && onErr != nil {
+			onErr(c.Name, fmt.Errorf("shutdown: %s: %w", c.Name, err))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package spiffe
+
+import "net/http"
+
+// Policy maps a SPIFFE ID to the set of methods it may call, the same
+// shape as pkg/authz.Policy but keyed on cryptographic identity instead
+// of a bearer token's role claim.
+type Policy map[string]map[string]bool
+
+// NewPolicy builds a Policy from a SPIFFE ID -> method list map, the
+// form it's most convenient to write in config.
+func NewPolicy(allowed map[string][]string) Policy {
+	p := make(Policy, len(allowed))
+	for id, methods := range allowed {
+		set := make(map[string]bool, len(methods))
+		for _, m := range methods {
+			set[m] = true
+		}
+		p[id] = set
+	}
+	return p
+}
+
+// Allows reports whether id may call method.
+func (p Policy) Allows(id ID, method string) bool {
+	methods, ok := p[id.String()]
+	if !ok {
+		return false
+	}
+	return methods[method]
+}
+
+// Middleware wraps next, requiring that the request arrived over a
+// connection whose peer presented a client certificate (as required by
+// a ServerConfig listener) and that p allows that certificate's SPIFFE
+// ID to call methodFor(r).
+func Middleware(p Policy, methodFor func(*http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			http.Error(w, "spiffe: no client certificate presented", http.StatusUnauthorized)
+			return
+		}
+
+		id, err := LeafID(r.TLS.PeerCertificates[0])
+		if err != nil {
+			http.Error(w, "spiffe: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		method := methodFor(r)
+		if !p.Allows(id, method) {
+			http.Error(w, "spiffe: "+id.String()+" may not call "+method, http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,23 @@
+package telemetry
+
+import "sync/atomic"
+
+// Counter is a monotonically increasing count, safe for concurrent use.
+type Counter struct {
+	value int64
+}
+
+// Inc increments the counter by 1.
+func (c *Counter) Inc() {
+	atomic.AddInt64(&c.value, 1)
+}
+
+// Add increments the counter by delta.
+func (c *Counter) Add(delta int64) {
+	atomic.AddInt64(&c.value, delta)
+}
+
+// Value returns the current count.
+func (c *Counter) Value() int64 {
+	return atomic.LoadInt64(&c.value)
+}
@@ -0,0 +1,22 @@
+package telemetry
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Gauge is a value that can go up or down, e.g. replication lag or queue
+// depth, as opposed to Counter which only increases.
+type Gauge struct {
+	bits uint64
+}
+
+// Set updates the gauge's current value.
+func (g *Gauge) Set(v float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(v))
+}
+
+// Value returns the gauge's current value.
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
@@ -0,0 +1,71 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// Histogram tracks the distribution of observed values across a fixed set
+// of cumulative buckets, in the same shape Prometheus histograms use.
+type Histogram struct {
+	mu      sync.Mutex
+	bounds []float64
+	counts []int64 // counts[i] is the number of observations <= bounds[i]
+	sum    float64
+	total  int64
+}
+
+func newHistogram(bounds []float64) *Histogram {
+	b := make([]float64, len(bounds))
+	copy(b, bounds)
+	return &Histogram{bounds: b, counts: make([]int64, len(b))}
+}
+
+// Observe records a single value.
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.total++
+}
+
+func (h *Histogram) writeTo(w io.Writer, name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# TYPE %s histogram\n", name); err != nil {
+		return err
+	}
+	for i, bound := range h.bounds {
+		le := boundLabel(bound)
+		if _, err := fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", name, le, h.counts[i]); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.total); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%s_sum %g\n", name, h.sum); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s_count %d\n", name, h.total)
+	return err
+}
+
+func boundLabel(bound float64) string {
+	if math.IsInf(bound, 1) {
+		return "+Inf"
+	}
+	return fmt.Sprintf("%g", bound)
+}
+
+// DefaultLatencyBounds are the bucket upper bounds (in seconds) used by
+// telemetry.Middleware for request latency histograms.
+var DefaultLatencyBounds = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
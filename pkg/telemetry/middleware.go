@@ -0,0 +1,31 @@
+package telemetry
+
+import (
+	"net/http"
+	"time"
+)
+
+// Middleware wraps next, recording a request counter and a latency
+// histogram (in seconds) under the given metric name prefix. Counters are
+// named "<prefix>_requests_total" and the histogram
+// "<prefix>_request_duration_seconds".
+func Middleware(reg *Registry, prefix string, next http.Handler) http.Handler {
+	requests := reg.Counter(prefix + "_requests_total")
+	latency := reg.Histogram(prefix+"_request_duration_seconds", DefaultLatencyBounds)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		requests.Inc()
+		latency.Observe(time.Since(start).Seconds())
+	})
+}
+
+// Handler returns an http.Handler that serves reg's metrics in Prometheus
+// text exposition format, suitable for mounting at /metrics.
+func Handler(reg *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		reg.DumpTo(w)
+	})
+}
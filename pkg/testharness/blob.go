@@ -0,0 +1,36 @@
+package testharness
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FakeBlobStore is an in-memory stand-in for whatever object store
+// segment archives and attachments normally land in.
+type FakeBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+// NewFakeBlobStore returns an empty FakeBlobStore.
+func NewFakeBlobStore() *FakeBlobStore {
+	return &FakeBlobStore{blobs: map[string][]byte{}}
+}
+
+// Put stores data under key.
+func (s *FakeBlobStore) Put(key string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = append([]byte(nil), data...)
+}
+
+// Get returns the bytes stored under key, or an error if key is unset.
+func (s *FakeBlobStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, fmt.Errorf("testharness: no blob stored under %q", key)
+	}
+	return append([]byte(nil), data...), nil
+}
@@ -0,0 +1,40 @@
+// Package testharness spins up in-process, in-memory fakes for the
+// pieces integration tests need so they can exercise a full request path
+// without Docker: a vector store, a Redis-like key/value and list store,
+// and a blob store. Each fake is served over httptest.Server using the
+// same HTTP/JSON transport the real services speak, so a test can point
+// a real client (e.g. pkg/topkclient) at harness.TopK.Addr and get
+// production-shaped behavior.
+package testharness
+
+import (
+	"net/http/httptest"
+)
+
+// Harness bundles the fakes a full-path integration test typically needs.
+type Harness struct {
+	Vector *FakeVectorStore
+	Store  *FakeStore
+	Blob   *FakeBlobStore
+
+	TopK *httptest.Server
+}
+
+// New starts every fake service and returns a Harness with their
+// addresses populated. Call Close when the test is done.
+func New() *Harness {
+	h := &Harness{
+		Vector: NewFakeVectorStore(),
+		Store:  NewFakeStore(),
+		Blob:   NewFakeBlobStore(),
+	}
+	h.TopK = httptest.NewServer(h.Vector.Handler())
+	return h
+}
+
+// Close shuts down every server started by New.
+func (h *Harness) Close() {
+	if h.TopK != nil {
+		h.TopK.Close()
+	}
+}
@@ -0,0 +1,64 @@
+package testharness
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// TestHarnessTopKSearch exercises the full request path this package
+// exists for: a real topkclient.Client talking HTTP/JSON to the
+// harness's FakeVectorStore, the same way it would talk to the real
+// top-k service in production.
+func TestHarnessTopKSearch(t *testing.T) {
+	h := New()
+	defer h.Close()
+
+	h.Vector.Insert("a", []float32{1, 0, 0})
+	h.Vector.Insert("b", []float32{0, 1, 0})
+	h.Vector.Insert("c", []float32{1, 0, 0})
+
+	client := topkclient.New(h.TopK.URL)
+	result, err := client.Search(context.Background(), topkclient.Query{
+		Embedding: []float32{1, 0, 0},
+		K:         2,
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(result.Matches) != 2 {
+		t.Fatalf("got %d matches, want 2", len(result.Matches))
+	}
+	for _, m := range result.Matches {
+		if m.ID != "a" && m.ID != "c" {
+			t.Errorf("unexpected match %q with score %v; want a or c scored above b", m.ID, m.Score)
+		}
+	}
+}
+
+// TestHarnessStoreAndBlob exercises FakeStore and FakeBlobStore directly,
+// the other two fakes a full-path test typically needs alongside the
+// vector store.
+func TestHarnessStoreAndBlob(t *testing.T) {
+	h := New()
+	defer h.Close()
+
+	h.Store.Set("session:1", "active")
+	if v, ok := h.Store.Get("session:1"); !ok || v != "active" {
+		t.Fatalf("Get(session:1) = %q, %v; want active, true", v, ok)
+	}
+
+	h.Blob.Put("segment-1.jsonl.gz", []byte("payload"))
+	data, err := h.Blob.Get("segment-1.jsonl.gz")
+	if err != nil {
+		t.Fatalf("Blob.Get: %v", err)
+	}
+	if string(data) != "payload" {
+		t.Fatalf("Blob.Get = %q, want %q", data, "payload")
+	}
+
+	if _, err := h.Blob.Get("missing"); err == nil {
+		t.Fatal("Get(missing) succeeded, want error")
+	}
+}
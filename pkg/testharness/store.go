@@ -0,0 +1,81 @@
+package testharness
+
+import "sync"
+
+// FakeStore is an in-memory stand-in for the Redis instances used
+// elsewhere in this repo (pkg/nodeset.RedisSource, pkg/pipeline's segment
+// queue): a key/value map plus per-key lists, enough for tests that don't
+// need miniredis's full command surface.
+type FakeStore struct {
+	mu     sync.Mutex
+	values map[string]string
+	lists  map[string][]string
+	sets   map[string]map[string]bool
+}
+
+// NewFakeStore returns an empty FakeStore.
+func NewFakeStore() *FakeStore {
+	return &FakeStore{
+		values: map[string]string{},
+		lists:  map[string][]string{},
+		sets:   map[string]map[string]bool{},
+	}
+}
+
+// Set stores value under key.
+func (s *FakeStore) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// Get returns the value stored under key, if any.
+func (s *FakeStore) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// RPush appends value to the list stored under key.
+func (s *FakeStore) RPush(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists[key] = append(s.lists[key], value)
+}
+
+// LPop removes and returns the first element of the list stored under
+// key, if any.
+func (s *FakeStore) LPop(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := s.lists[key]
+	if len(items) == 0 {
+		return "", false
+	}
+	s.lists[key] = items[1:]
+	return items[0], true
+}
+
+// SAdd adds member to the set stored under key.
+func (s *FakeStore) SAdd(key, member string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set, ok := s.sets[key]
+	if !ok {
+		set = map[string]bool{}
+		s.sets[key] = set
+	}
+	set[member] = true
+}
+
+// SMembers returns the members of the set stored under key.
+func (s *FakeStore) SMembers(key string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	members := make([]string, 0, len(s.sets[key]))
+	for m := range s.sets[key] {
+		members = append(members, m)
+	}
+	return members
+}
@@ -0,0 +1,77 @@
+package testharness
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/cgb808/ZenGlowNext/pkg/topkclient"
+)
+
+// FakeVectorStore is an in-memory stand-in for the top-k vector search
+// service, scoring by cosine similarity over whatever vectors the test
+// has inserted with Insert.
+type FakeVectorStore struct {
+	mu    sync.Mutex
+	items map[string][]float32
+}
+
+// NewFakeVectorStore returns an empty FakeVectorStore.
+func NewFakeVectorStore() *FakeVectorStore {
+	return &FakeVectorStore{items: map[string][]float32{}}
+}
+
+// Insert adds or replaces the vector stored under id.
+func (s *FakeVectorStore) Insert(id string, embedding []float32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[id] = embedding
+}
+
+// Handler serves /search compatibly with pkg/topkclient.Client.Search.
+func (s *FakeVectorStore) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", s.handleSearch)
+	return mux
+}
+
+func (s *FakeVectorStore) handleSearch(w http.ResponseWriter, r *http.Request) {
+	var q topkclient.Query
+	if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	matches := make([]topkclient.Match, 0, len(s.items))
+	for id, v := range s.items {
+		matches = append(matches, topkclient.Match{ID: id, Score: cosineSimilarity(q.Embedding, v)})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	k := q.K
+	if k <= 0 || k > len(matches) {
+		k = len(matches)
+	}
+
+	json.NewEncoder(w).Encode(topkclient.Result{Matches: matches[:k]})
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(b) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
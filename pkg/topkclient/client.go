@@ -0,0 +1,153 @@
+// Package topkclient is the client-side library behind the topk-client
+// CLI: it talks to the top-k vector search service over HTTP/JSON and is
+// kept separate from cmd/topk-client so other Go callers can embed it
+// without shelling out to the binary.
+package topkclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Client is a thin wrapper around the topk search service's HTTP API.
+type Client struct {
+	Addr       string
+	AuthToken  string // sent as a Bearer token on every request, if set
+	HTTPClient *http.Client
+}
+
+// New returns a Client with a sane default timeout and no TLS
+// customization (suitable for plain-HTTP addresses).
+func New(addr string) *Client {
+	return &Client{Addr: addr, HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// TLSOptions configures the client's TLS behavior for https:// addresses.
+type TLSOptions struct {
+	CAFile             string // PEM file of CA certs to trust; defaults to the system pool
+	CertFile, KeyFile  string // client certificate for mutual TLS, if required by the server
+	InsecureSkipVerify bool   // disable server certificate verification; for local testing only
+}
+
+// NewWithTLS is New with an explicit TLS configuration.
+func NewWithTLS(addr string, opts TLSOptions) (*Client, error) {
+	tlsConfig, err := buildTLSConfig(opts)
+	if err != nil {
+		return nil, fmt.Errorf("topkclient: building TLS config: %w", err)
+	}
+	return &Client{
+		Addr: addr,
+		HTTPClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+// Query is a single top-k search request.
+type Query struct {
+	Text      string    `json:"text,omitempty"`
+	Embedding []float32 `json:"embedding,omitempty"`
+	K         int       `json:"k"`
+	Filters   *Filters  `json:"filters,omitempty"`
+	Explain   bool      `json:"explain,omitempty"`
+}
+
+// Filters narrows a search to a subset of the indexed events. Zero-value
+// fields are omitted from the request and left unfiltered by the service.
+type Filters struct {
+	User string     `json:"user,omitempty"`
+	Type string     `json:"type,omitempty"`
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// Match is a single search result. Explanation is populated only when the
+// originating Query set Explain, and holds the service's breakdown of how
+// the score was computed (e.g. per-signal contributions); its shape is
+// service-defined, so it's left untyped here.
+type Match struct {
+	ID          string      `json:"id"`
+	Score       float64     `json:"score"`
+	Explanation interface{} `json:"explanation,omitempty"`
+}
+
+// Result is the full response to a Search call.
+type Result struct {
+	Matches []Match `json:"matches"`
+}
+
+// Call invokes an arbitrary service method by name, POSTing body as JSON
+// to /<method> and returning the raw JSON response. Unlike Search, the
+// request and response shapes aren't known ahead of time — this exists so
+// the CLI can drive methods this library hasn't grown a typed wrapper
+// for yet (the Go analogue of invoking an RPC discovered through
+// reflection rather than a generated stub).
+func (c *Client) Call(ctx context.Context, method string, body interface{}) (json.RawMessage, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("topkclient: encoding request for %s: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Addr+"/"+method, bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("topkclient: building request for %s: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AuthToken)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("topkclient: calling %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("topkclient: reading response from %s: %w", method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("topkclient: %s returned status %d: %s", method, resp.StatusCode, raw)
+	}
+	return json.RawMessage(raw), nil
+}
+
+// Search issues q against the service's /search endpoint.
+func (c *Client) Search(ctx context.Context, q Query) (*Result, error) {
+	body, err := json.Marshal(q)
+	if err != nil {
+		return nil, fmt.Errorf("topkclient: encoding query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Addr+"/search", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("topkclient: building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AuthToken)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("topkclient: calling %s: %w", c.Addr, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("topkclient: %s returned status %d", c.Addr, resp.StatusCode)
+	}
+
+	var result Result
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("topkclient: decoding response: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,41 @@
+package topkclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// buildTLSConfig turns TLSOptions into a *tls.Config. A zero-value
+// TLSOptions produces nil, which tells http.Transport to use its default
+// (system trust store, no client cert).
+func buildTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	if opts == (TLSOptions{}) {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", opts.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
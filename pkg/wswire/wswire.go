@@ -0,0 +1,208 @@
+// Package wswire is a minimal WebSocket (RFC 6455) server implementation
+// used by pkg/pushgateway to push match notifications to UI clients,
+// hand-rolled for the same reason pkg/respwire hand-rolls RESP: this
+// repo has no way to vendor a client library offline. It only
+// implements what a server that pushes text frames and watches for the
+// client closing the connection needs — the handshake, writing text and
+// close frames, and reading client frames far enough to notice a close
+// or ping.
+package wswire
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// Conn is a hijacked HTTP connection upgraded to WebSocket.
+type Conn struct {
+	netConn net.Conn
+	rw      *bufio.ReadWriter
+}
+
+// Upgrade performs the RFC 6455 handshake on r, hijacking w's underlying
+// connection. The caller owns the returned Conn and must Close it.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Upgrade") != "websocket" {
+		return nil, errors.New("wswire: not a websocket upgrade request")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("wswire: response does not support hijacking")
+	}
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("wswire: hijacking connection: %w", err)
+	}
+
+	accept := acceptKey(key)
+	_, err = fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", accept)
+	if err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("wswire: writing handshake response: %w", err)
+	}
+	if err := rw.Flush(); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("wswire: flushing handshake response: %w", err)
+	}
+
+	return &Conn{netConn: netConn, rw: rw}, nil
+}
+
+// WriteText sends payload as a single unfragmented text frame.
+func (c *Conn) WriteText(payload []byte) error {
+	return c.writeFrame(opText, payload)
+}
+
+// Close sends a close frame and closes the underlying connection.
+func (c *Conn) Close() error {
+	_ = c.writeFrame(opClose, nil)
+	return c.netConn.Close()
+}
+
+// ReadLoop blocks reading client frames until the connection closes or a
+// close frame arrives, responding to pings with pongs along the way. It
+// exists so a handler can notice client disconnects without writing to
+// the connection, the same role context cancellation plays for SSE.
+func (c *Conn) ReadLoop() error {
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return err
+		}
+		switch opcode {
+		case opClose:
+			return io.EOF
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ReadText blocks for the next text frame from the client, answering any
+// ping frames along the way (same as ReadLoop) instead of surfacing
+// them. It returns io.EOF once the client sends a close frame or the
+// connection drops, the same error ReadLoop treats as a clean
+// disconnect, so a caller reading frames in a loop can stop on io.EOF
+// exactly like ReadLoop's caller does.
+func (c *Conn) ReadText() ([]byte, error) {
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opClose:
+			return nil, io.EOF
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opText:
+			return payload, nil
+		}
+	}
+}
+
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode} // FIN=1, no fragmentation
+	length := len(payload)
+	switch {
+	case length <= 125:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		ext := make([]byte, 2)
+		binary.BigEndian.PutUint16(ext, uint16(length))
+		header = append(header, 126)
+		header = append(header, ext...)
+	default:
+		ext := make([]byte, 8)
+		binary.BigEndian.PutUint64(ext, uint64(length))
+		header = append(header, 127)
+		header = append(header, ext...)
+	}
+	// Server-to-client frames are sent unmasked per RFC 6455 §5.1.
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+func (c *Conn) readFrame() (opcode byte, payload []byte, err error) {
+	first, err := c.rw.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	opcode = first & 0x0F
+
+	second, err := c.rw.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	masked := second&0x80 != 0
+	length := int64(second & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.rw, maskKey[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+	return opcode, payload, nil
+}
+
+func acceptKey(clientKey string) string {
+	h := sha1.New()
+	h.Write([]byte(clientKey + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}